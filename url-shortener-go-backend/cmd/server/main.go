@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"log/slog"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
@@ -10,18 +11,31 @@ import (
 	"syscall"
 	"time"
 
+	"url-shortener-go-backend/internal/acmecert"
 	"url-shortener-go-backend/internal/cache"
 	"url-shortener-go-backend/internal/config"
+	"url-shortener-go-backend/internal/deeplink"
 	"url-shortener-go-backend/internal/handler"
+	"url-shortener-go-backend/internal/linkhealth"
 	"url-shortener-go-backend/internal/logger"
+	"url-shortener-go-backend/internal/metadata"
 	"url-shortener-go-backend/internal/metrics"
 	"url-shortener-go-backend/internal/middleware"
+	"url-shortener-go-backend/internal/model"
+	"url-shortener-go-backend/internal/netguard"
 	"url-shortener-go-backend/internal/repository"
+	"url-shortener-go-backend/internal/reputation"
 	"url-shortener-go-backend/internal/router"
 	"url-shortener-go-backend/internal/service"
+	"url-shortener-go-backend/internal/shortcode"
+	"url-shortener-go-backend/internal/targeting"
 	"url-shortener-go-backend/internal/telemetry"
+	"url-shortener-go-backend/internal/urlnorm"
+	"url-shortener-go-backend/internal/webhook"
+	"url-shortener-go-backend/internal/worker"
 
 	"github.com/joho/godotenv"
+	"golang.org/x/crypto/acme/autocert"
 )
 
 func main() {
@@ -72,15 +86,77 @@ func main() {
 
 	urlRepo := repository.NewURLRepository(supabase, cfg.ShortDomain)
 	analyticsRepo := repository.NewAnalyticsRepository(supabase)
+	reportRepo := repository.NewReportRepository(supabase)
+	domainRepo := repository.NewDomainRepository(supabase)
+	workspaceRepo := repository.NewWorkspaceRepository(supabase)
+	webhookRepo := repository.NewWebhookRepository(supabase)
 
 	urlRepo = repository.NewInstrumentedURLRepository(urlRepo)
 	analyticsRepo = repository.NewInstrumentedAnalyticsRepository(analyticsRepo)
+	reportRepo = repository.NewInstrumentedReportRepository(reportRepo)
+	domainRepo = repository.NewInstrumentedDomainRepository(domainRepo)
+	workspaceRepo = repository.NewInstrumentedWorkspaceRepository(workspaceRepo)
+	webhookRepo = repository.NewInstrumentedWebhookRepository(webhookRepo)
 
-	urlService := service.NewURLService(urlRepo, rc, cfg.Salt)
+	checker, blocklist := buildReputationChecker(cfg, rc)
+
+	var fetcher metadata.Fetcher
+	if cfg.MetadataEnabled {
+		fetcher = metadata.NewHTTPFetcher(cfg.MetadataTimeout)
+	}
+
+	webhookService := service.NewWebhookService(webhookRepo, rc,
+		webhook.NewSender(cfg.WebhookTimeout, netguard.Policy{}),
+		model.DeliveryPolicy{
+			MaxAttempts: cfg.WebhookMaxAttempts,
+			BaseBackoff: cfg.WebhookBackoff,
+			MaxBackoff:  cfg.WebhookMaxBackoff,
+		}, cfg.WebhookWorkers, cfg.WebhookDeliveryRetention)
+
+	urlService := service.NewURLService(urlRepo, rc, shortcode.NewMux(buildCodeGenerator(cfg, rc), shortcode.NewFilter(cfg.ReservedCodes)), shortcode.CollisionPolicy{
+		MaxAttempts: cfg.ShortCodeMaxAttempts,
+		GrowLength:  cfg.ShortCodeGrowOnCollision,
+	}, checker, fetcher, urlnorm.New(cfg.StripTrackingParams), webhookService)
 	analyticsService := service.NewAnalyticsService(analyticsRepo, rc, cfg.Salt)
+	reportService := service.NewReportService(reportRepo, urlService, cfg.Salt)
+	domainService := service.NewDomainService(domainRepo, rc, net.DefaultResolver, cfg.ShortDomain)
+	workspaceService := service.NewWorkspaceService(workspaceRepo, rc)
+	linkHealthService := service.NewLinkHealthService(urlRepo, rc,
+		linkhealth.NewRunner(linkhealth.NewProber(cfg.LinkHealthTimeout, netguard.Policy{}), cfg.LinkHealthConcurrency, cfg.LinkHealthPerHost),
+		model.HealthPolicy{
+			Interval:         cfg.LinkHealthInterval,
+			MaxBackoff:       cfg.LinkHealthMaxBackoff,
+			FailureThreshold: cfg.LinkHealthFailureThreshold,
+		})
 
-	urlHandler := handler.NewURLHandler(urlService)
-	analyticsHandler := handler.NewAnalyticsHandler(analyticsService)
+	validatorConfig := middleware.DefaultConfig()
+	validatorConfig.PreflightEnabled = cfg.PreflightEnabled
+	validatorConfig.PreflightTimeout = cfg.PreflightTimeout
+	validatorConfig.MaxRedirects = cfg.PreflightMaxRedirects
+	validatorConfig.ValidateSSL = cfg.PreflightValidateSSL
+	validatorConfig.DNSTimeout = cfg.ValidatorDNSTimeout
+	validatorConfig.HomographPolicy = middleware.HomographPolicy(cfg.HomographPolicy)
+	if len(cfg.ProtectedDomains) > 0 {
+		validatorConfig.ProtectedDomains = cfg.ProtectedDomains
+	}
+	urlValidator := middleware.NewURLValidator(validatorConfig)
+
+	urlHandler := handler.NewURLHandler(urlService, analyticsService, domainService, workspaceService, urlValidator, targeting.NewDetector(cfg.GeoCountryHeader), webhookService)
+	analyticsHandler := handler.NewAnalyticsHandler(analyticsService, workspaceService)
+	reportHandler := handler.NewReportHandler(reportService, urlService)
+	domainHandler := handler.NewDomainHandler(domainService)
+	workspaceHandler := handler.NewWorkspaceHandler(workspaceService)
+	webhookHandler := handler.NewWebhookHandler(webhookService, workspaceService)
+	wellKnownHandler, err := handler.NewWellKnownHandler(deeplink.Config{
+		IOSAppIDs:           cfg.IOSAppIDs,
+		IOSPaths:            cfg.IOSAppPaths,
+		AndroidPackage:      cfg.AndroidAppPackage,
+		AndroidFingerprints: cfg.AndroidCertFingerprints,
+	})
+	if err != nil {
+		slog.Error("invalid app link configuration", "error", err)
+		os.Exit(1)
+	}
 
 	authMw := middleware.AuthMiddleware(cfg.JWTSecret)
 
@@ -97,12 +173,50 @@ func main() {
 		cfg,
 		urlHandler,
 		analyticsHandler,
+		reportHandler,
+		wellKnownHandler,
+		domainHandler,
+		domainService,
+		workspaceHandler,
+		webhookHandler,
+		limiter,
 		rc,
 		supabase,
 		authMw,
+		middleware.ClientIPMiddleware(cfg.TrustedProxies),
 		limiter.Middleware,
 	)
 
+	if cfg.ACMEEnabled {
+		server.EnableAutoTLS(cfg.ACMEAddr, buildCertManager(cfg, rc, domainService))
+	}
+
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	go urlService.RunMetadataWorkers(workerCtx, cfg.MetadataWorkers)
+	go webhookService.RunWorkers(workerCtx)
+	go worker.RunPeriodic(workerCtx, "webhook-retry", cfg.WebhookRetryInterval, webhookService.RetryDeliveries)
+	go worker.RunPeriodic(workerCtx, "webhook-click-flush", cfg.WebhookClickFlushInterval, webhookService.FlushClicks)
+	if cfg.WebhookDeliveryRetention > 0 {
+		go worker.RunPeriodic(workerCtx, "webhook-delivery-prune", time.Hour, webhookService.PruneDeliveries)
+	}
+	if cfg.WebhookExpirySweep > 0 {
+		go worker.RunPeriodic(workerCtx, "link-expiry-sweep", cfg.WebhookExpirySweep, urlService.NotifyExpiredLinks)
+	}
+
+	if checker != nil {
+		go worker.RunPeriodic(workerCtx, "reputation-recheck", cfg.ReputationRecheckInterval, urlService.RecheckReputation)
+	}
+	if cfg.LinkHealthInterval > 0 {
+		go worker.RunPeriodic(workerCtx, "link-health", cfg.LinkHealthScanInterval, linkHealthService.CheckDueLinks)
+	}
+	if blocklist != nil {
+		go worker.RunPeriodic(workerCtx, "reputation-blocklist-reload", cfg.ReputationBlocklistRefresh, func(context.Context) error {
+			return blocklist.Reload()
+		})
+	}
+
 	go func() {
 		if err := server.Run(); err != nil && err != http.ErrServerClosed {
 			slog.Error("server failed to start", "error", err)
@@ -117,6 +231,7 @@ func main() {
 	<-quit
 
 	slog.Info("shutting down server")
+	stopWorkers()
 
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
 	defer cancel()
@@ -141,6 +256,94 @@ func main() {
 	slog.Info("server stopped")
 }
 
+func buildReputationChecker(cfg *config.Config, rc cache.Cache) (reputation.ReputationChecker, *reputation.HashPrefixBlocklist) {
+	var checkers []reputation.ReputationChecker
+	var blocklist *reputation.HashPrefixBlocklist
+
+	if cfg.ReputationBlocklistFile != "" {
+		var finder reputation.FullHashFinder
+		if cfg.ReputationFullHashKey != "" {
+			finder = reputation.NewSafeBrowsingFullHashes(cfg.ReputationFullHashURL, cfg.ReputationFullHashKey, cfg.ReputationTimeout)
+		}
+		bl, err := reputation.NewHashPrefixBlocklist(cfg.ReputationBlocklistFile, finder)
+		if err != nil {
+			slog.Error("failed to load reputation blocklist", "path", cfg.ReputationBlocklistFile, "error", err)
+			os.Exit(1)
+		}
+		blocklist = bl
+		checkers = append(checkers, bl)
+	}
+
+	if cfg.ReputationWebhookURL != "" {
+		var webhook reputation.ReputationChecker = reputation.NewWebhookChecker(cfg.ReputationWebhookURL, cfg.ReputationWebhookToken, cfg.ReputationTimeout)
+		if rc != nil {
+			webhook = reputation.NewCachedChecker(webhook, rc, cfg.Salt, cfg.ReputationCacheTTL)
+		}
+		checkers = append(checkers, webhook)
+	}
+
+	if len(checkers) == 0 {
+		slog.Info("no reputation checkers configured, reputation checks disabled")
+		return nil, nil
+	}
+
+	slog.Info("reputation checks enabled", "checkers", len(checkers), "fail_closed", cfg.ReputationFailClosed)
+	return reputation.NewChain(cfg.ReputationFailClosed, checkers...), blocklist
+}
+
+func buildCodeGenerator(cfg *config.Config, rc cache.Cache) shortcode.CodeGenerator {
+	var gen shortcode.CodeGenerator
+	switch cfg.ShortCodeGenerator {
+	case shortcode.GeneratorCounter, shortcode.GeneratorSnowflake:
+		if rc == nil {
+			slog.Error("SHORTCODE_GENERATOR requires REDIS_URL", "generator", cfg.ShortCodeGenerator)
+			os.Exit(1)
+		}
+		if cfg.ShortCodeGenerator == shortcode.GeneratorCounter {
+			gen = shortcode.NewCounterGenerator(rc, cfg.Salt)
+		} else {
+			gen = shortcode.NewSnowflakeGenerator(rc, cfg.ShortCodeNodeID)
+		}
+	default:
+		gen = shortcode.NewRandomGenerator(cfg.Salt)
+	}
+
+	slog.Info("short code generator configured", "generator", gen.Name(), "max_attempts", cfg.ShortCodeMaxAttempts, "grow_on_collision", cfg.ShortCodeGrowOnCollision)
+	return gen
+}
+
+func buildCertManager(cfg *config.Config, rc cache.Cache, domains service.DomainService) *autocert.Manager {
+	var store autocert.Cache
+	switch cfg.ACMECache {
+	case "redis":
+		if rc == nil {
+			slog.Error("ACME_CACHE=redis requires REDIS_URL")
+			os.Exit(1)
+		}
+		store = acmecert.NewCacheStore(rc)
+	default:
+		store = autocert.DirCache(cfg.ACMECacheDir)
+	}
+
+	hosts := append([]string{}, cfg.ACMEHosts...)
+	if u, err := url.Parse(cfg.ShortDomain); err == nil && u.Hostname() != "" {
+		hosts = append(hosts, u.Hostname())
+	}
+
+	manager, err := acmecert.NewManager(acmecert.Options{
+		Email:        cfg.ACMEEmail,
+		DirectoryURL: cfg.ACMEDirectoryURL,
+		CACertFile:   cfg.ACMECACert,
+	}, acmecert.HostPolicy(hosts, domains.ResolveHost), store)
+	if err != nil {
+		slog.Error("invalid acme configuration", "error", err)
+		os.Exit(1)
+	}
+
+	slog.Info("acme certificates enabled", "cache", cfg.ACMECache, "static_hosts", len(hosts))
+	return manager
+}
+
 func parseRedisURL(raw string) (addr, password string, err error) {
 	u, err := url.Parse(raw)
 	if err != nil {
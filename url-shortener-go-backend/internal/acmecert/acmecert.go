@@ -0,0 +1,115 @@
+package acmecert
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"url-shortener-go-backend/internal/cache"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+const (
+	cacheKeyPrefix = "acme:"
+	cacheTTL       = 120 * 24 * time.Hour
+	clientTimeout  = 30 * time.Second
+)
+
+type Options struct {
+	Email        string
+	DirectoryURL string
+	CACertFile   string
+}
+
+type HostResolver func(ctx context.Context, host string) (string, bool)
+
+func NewManager(opts Options, policy autocert.HostPolicy, store autocert.Cache) (*autocert.Manager, error) {
+	client := &acme.Client{DirectoryURL: opts.DirectoryURL}
+	if client.DirectoryURL == "" {
+		client.DirectoryURL = autocert.DefaultACMEDirectory
+	}
+
+	if opts.CACertFile != "" {
+		pem, err := os.ReadFile(opts.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ACME CA certificate: %w", err)
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", opts.CACertFile)
+		}
+		client.HTTPClient = &http.Client{
+			Timeout: clientTimeout,
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12},
+			},
+		}
+	}
+
+	return &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		Email:      opts.Email,
+		Client:     client,
+		Cache:      store,
+		HostPolicy: policy,
+	}, nil
+}
+
+func HostPolicy(static []string, resolve HostResolver) autocert.HostPolicy {
+	allowed := make(map[string]bool, len(static))
+	for _, host := range static {
+		if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+			allowed[host] = true
+		}
+	}
+
+	return func(ctx context.Context, host string) error {
+		host = strings.ToLower(host)
+		if allowed[host] {
+			return nil
+		}
+		if resolve != nil {
+			if _, ok := resolve(ctx, host); ok {
+				return nil
+			}
+		}
+		slog.Warn("acme certificate refused for unknown host", "host", host)
+		return fmt.Errorf("acmecert: host %q is not a verified domain", host)
+	}
+}
+
+type CacheStore struct {
+	cache cache.Cache
+}
+
+func NewCacheStore(c cache.Cache) *CacheStore {
+	return &CacheStore{cache: c}
+}
+
+func (s *CacheStore) Get(ctx context.Context, key string) ([]byte, error) {
+	val, ok, err := s.cache.Get(ctx, cacheKeyPrefix+key)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, autocert.ErrCacheMiss
+	}
+	return []byte(val), nil
+}
+
+func (s *CacheStore) Put(ctx context.Context, key string, data []byte) error {
+	return s.cache.Set(ctx, cacheKeyPrefix+key, string(data), cacheTTL)
+}
+
+func (s *CacheStore) Delete(ctx context.Context, key string) error {
+	return s.cache.Delete(ctx, cacheKeyPrefix+key)
+}
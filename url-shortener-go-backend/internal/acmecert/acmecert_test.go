@@ -0,0 +1,170 @@
+package acmecert
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+func verifiedHosts(hosts ...string) HostResolver {
+	set := make(map[string]bool, len(hosts))
+	for _, h := range hosts {
+		set[h] = true
+	}
+	return func(ctx context.Context, host string) (string, bool) {
+		return host, set[host]
+	}
+}
+
+func TestHostPolicy(t *testing.T) {
+	policy := HostPolicy([]string{" Short.Example ", ""}, verifiedHosts("links.customer.com"))
+
+	tests := []struct {
+		host    string
+		allowed bool
+	}{
+		{host: "short.example", allowed: true},
+		{host: "SHORT.EXAMPLE", allowed: true},
+		{host: "links.customer.com", allowed: true},
+		{host: "Links.Customer.com", allowed: true},
+		{host: "pending.customer.com", allowed: false},
+		{host: "", allowed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			err := policy(context.Background(), tt.host)
+			if tt.allowed && err != nil {
+				t.Fatalf("policy(%q) = %v, want allowed", tt.host, err)
+			}
+			if !tt.allowed && err == nil {
+				t.Fatalf("policy(%q) allowed, want refused", tt.host)
+			}
+		})
+	}
+}
+
+func TestHostPolicyWithoutResolver(t *testing.T) {
+	policy := HostPolicy([]string{"short.example"}, nil)
+	if err := policy(context.Background(), "short.example"); err != nil {
+		t.Fatalf("static host refused: %v", err)
+	}
+	if err := policy(context.Background(), "links.customer.com"); err == nil {
+		t.Fatal("unknown host allowed without a resolver")
+	}
+}
+
+type memCache struct {
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func (c *memCache) Get(ctx context.Context, key string) (string, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.data[key]
+	return v, ok, nil
+}
+
+func (c *memCache) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.data[key] = value
+	return nil
+}
+
+func (c *memCache) Incr(ctx context.Context, key string) (int64, error) {
+	return 0, nil
+}
+
+func (c *memCache) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return nil
+}
+
+func (c *memCache) TTL(ctx context.Context, key string) (time.Duration, error) {
+	return 0, nil
+}
+
+func (c *memCache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.data, key)
+	return nil
+}
+
+func (c *memCache) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (c *memCache) Close() error {
+	return nil
+}
+
+func TestCacheStore(t *testing.T) {
+	mem := &memCache{data: map[string]string{}}
+	store := NewCacheStore(mem)
+	ctx := context.Background()
+
+	if _, err := store.Get(ctx, "links.customer.com"); !errors.Is(err, autocert.ErrCacheMiss) {
+		t.Fatalf("Get on empty store = %v, want ErrCacheMiss", err)
+	}
+	if err := store.Put(ctx, "links.customer.com", []byte("cert")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if _, ok := mem.data[cacheKeyPrefix+"links.customer.com"]; !ok {
+		t.Fatal("certificate not stored under the acme: prefix")
+	}
+	got, err := store.Get(ctx, "links.customer.com")
+	if err != nil || string(got) != "cert" {
+		t.Fatalf("Get = %q, %v", got, err)
+	}
+	if err := store.Delete(ctx, "links.customer.com"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get(ctx, "links.customer.com"); !errors.Is(err, autocert.ErrCacheMiss) {
+		t.Fatalf("Get after Delete = %v, want ErrCacheMiss", err)
+	}
+}
+
+func TestPebbleIssuance(t *testing.T) {
+	directory := os.Getenv("PEBBLE_DIRECTORY_URL")
+	if directory == "" {
+		t.Skip("PEBBLE_DIRECTORY_URL not set; run Pebble with PEBBLE_VA_ALWAYS_VALID=1 to enable")
+	}
+
+	policy := HostPolicy([]string{"short.example"}, verifiedHosts("links.customer.com"))
+	manager, err := NewManager(Options{
+		Email:        "ops@short.example",
+		DirectoryURL: directory,
+		CACertFile:   os.Getenv("PEBBLE_CA_FILE"),
+	}, policy, NewCacheStore(&memCache{data: map[string]string{}}))
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+
+	for _, host := range []string{"short.example", "links.customer.com"} {
+		cert, err := manager.GetCertificate(&tls.ClientHelloInfo{
+			ServerName:        host,
+			SupportedProtos:   []string{"h2", "http/1.1"},
+			CipherSuites:      []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256},
+			SignatureSchemes:  []tls.SignatureScheme{tls.ECDSAWithP256AndSHA256},
+			SupportedVersions: []uint16{tls.VersionTLS13, tls.VersionTLS12},
+		})
+		if err != nil {
+			t.Fatalf("GetCertificate(%q): %v", host, err)
+		}
+		if cert.Leaf == nil || cert.Leaf.VerifyHostname(host) != nil {
+			t.Fatalf("certificate for %q does not cover the host", host)
+		}
+	}
+
+	if _, err := manager.GetCertificate(&tls.ClientHelloInfo{ServerName: "pending.customer.com"}); err == nil {
+		t.Fatal("certificate issued for an unverified host")
+	}
+}
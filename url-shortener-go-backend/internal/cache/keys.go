@@ -34,8 +34,12 @@ func KeyURLAnalytics(salt, urlID string, startDate, endDate time.Time) string {
 		endDate.Format("2006-01-02"))
 }
 
-func KeyUserAnalytics(salt, userID string, startDate, endDate time.Time) string {
-	return SecureKey(salt, "analytics", userID,
+func KeyWorkspaceAnalytics(salt, workspaceID string, startDate, endDate time.Time) string {
+	return SecureKey(salt, "workspace_analytics", workspaceID,
 		startDate.Format("2006-01-02"),
 		endDate.Format("2006-01-02"))
 }
+
+func KeyReputation(salt, url string) string {
+	return SecureKey(salt, "reputation", url)
+}
@@ -0,0 +1,28 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+const versionTTL = 24 * time.Hour
+
+func Version(ctx context.Context, c Cache, key string) int64 {
+	val, ok, err := c.Get(ctx, key)
+	if err != nil || !ok {
+		return 0
+	}
+	v, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+func BumpVersion(ctx context.Context, c Cache, key string) error {
+	if _, err := c.Incr(ctx, key); err != nil {
+		return err
+	}
+	return c.Expire(ctx, key, versionTTL)
+}
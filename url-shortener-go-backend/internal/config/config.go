@@ -2,7 +2,9 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -20,6 +22,75 @@ type Config struct {
 	ShutdownTimeout     time.Duration
 	OTLPEndpoint        string
 	Version             string
+	AdminUserIDs        []string
+	ReportRateLimit     int
+	TrustedProxies      []*net.IPNet
+
+	ReputationBlocklistFile    string
+	ReputationFullHashURL      string
+	ReputationFullHashKey      string
+	ReputationWebhookURL       string
+	ReputationWebhookToken     string
+	ReputationTimeout          time.Duration
+	ReputationCacheTTL         time.Duration
+	ReputationRecheckInterval  time.Duration
+	ReputationBlocklistRefresh time.Duration
+	ReputationFailClosed       bool
+
+	PreflightEnabled      bool
+	PreflightTimeout      time.Duration
+	PreflightMaxRedirects int
+	PreflightValidateSSL  bool
+
+	ValidatorDNSTimeout time.Duration
+	HomographPolicy     string
+	ProtectedDomains    []string
+
+	MetadataEnabled bool
+	MetadataTimeout time.Duration
+	MetadataWorkers int
+
+	GeoCountryHeader string
+
+	LinkHealthInterval         time.Duration
+	LinkHealthScanInterval     time.Duration
+	LinkHealthTimeout          time.Duration
+	LinkHealthConcurrency      int
+	LinkHealthPerHost          int
+	LinkHealthFailureThreshold int
+	LinkHealthMaxBackoff       time.Duration
+
+	StripTrackingParams bool
+
+	WebhookTimeout            time.Duration
+	WebhookWorkers            int
+	WebhookMaxAttempts        int
+	WebhookBackoff            time.Duration
+	WebhookMaxBackoff         time.Duration
+	WebhookRetryInterval      time.Duration
+	WebhookClickFlushInterval time.Duration
+	WebhookDeliveryRetention  time.Duration
+	WebhookExpirySweep        time.Duration
+
+	ShortCodeGenerator       string
+	ShortCodeMaxAttempts     int
+	ShortCodeGrowOnCollision bool
+	ShortCodeNodeID          int
+	ReservedCodes            []string
+
+	IOSAppIDs               []string
+	IOSAppPaths             []string
+	AndroidAppPackage       string
+	AndroidCertFingerprints []string
+
+	ACMEEnabled      bool
+	ACMEAddr         string
+	ACMEEmail        string
+	ACMEDirectoryURL string
+	ACMECACert       string
+	ACMECache        string
+	ACMECacheDir     string
+	ACMEHosts        []string
 }
 
 func Load() (*Config, error) {
@@ -33,16 +104,7 @@ func Load() (*Config, error) {
 		env = "production"
 	}
 
-	allowedOriginsStr := os.Getenv("ALLOWED_ORIGINS")
-	var allowedOrigins []string
-	if allowedOriginsStr != "" {
-		for _, o := range strings.Split(allowedOriginsStr, ",") {
-			o = strings.TrimSpace(o)
-			if o != "" {
-				allowedOrigins = append(allowedOrigins, o)
-			}
-		}
-	}
+	allowedOrigins := splitList(os.Getenv("ALLOWED_ORIGINS"))
 	if len(allowedOrigins) == 0 {
 		allowedOrigins = []string{"https://url-shortener-nu-two-32.vercel.app"}
 	}
@@ -82,6 +144,212 @@ func Load() (*Config, error) {
 		version = "dev"
 	}
 
+	reportRateLimit, err := intEnv("REPORT_RATE_LIMIT", 5)
+	if err != nil {
+		return nil, err
+	}
+
+	trustedProxies, err := parseProxies(os.Getenv("TRUSTED_PROXIES"))
+	if err != nil {
+		return nil, err
+	}
+
+	reputationTimeout, err := durationEnv("REPUTATION_TIMEOUT", 3*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	reputationCacheTTL, err := durationEnv("REPUTATION_CACHE_TTL", 6*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	reputationRecheck, err := durationEnv("REPUTATION_RECHECK_INTERVAL", 24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	blocklistRefresh, err := durationEnv("REPUTATION_BLOCKLIST_REFRESH", 30*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	reputationFailClosed, err := boolEnv("REPUTATION_FAIL_CLOSED", false)
+	if err != nil {
+		return nil, err
+	}
+
+	preflightEnabled, err := boolEnv("PREFLIGHT_ENABLED", false)
+	if err != nil {
+		return nil, err
+	}
+
+	preflightTimeout, err := durationEnv("PREFLIGHT_TIMEOUT", 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	preflightMaxRedirects, err := intEnv("PREFLIGHT_MAX_REDIRECTS", 5)
+	if err != nil {
+		return nil, err
+	}
+
+	preflightValidateSSL, err := boolEnv("PREFLIGHT_VALIDATE_SSL", true)
+	if err != nil {
+		return nil, err
+	}
+
+	validatorDNSTimeout, err := durationEnv("VALIDATOR_DNS_TIMEOUT", 2*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	metadataEnabled, err := boolEnv("METADATA_ENABLED", true)
+	if err != nil {
+		return nil, err
+	}
+
+	metadataTimeout, err := durationEnv("METADATA_TIMEOUT", 5*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	metadataWorkers, err := intEnv("METADATA_WORKERS", 2)
+	if err != nil {
+		return nil, err
+	}
+
+	homographPolicy := strings.ToLower(os.Getenv("HOMOGRAPH_POLICY"))
+	switch homographPolicy {
+	case "":
+		homographPolicy = "warn"
+	case "off", "warn", "block":
+	default:
+		return nil, fmt.Errorf("invalid HOMOGRAPH_POLICY %q: must be off, warn or block", homographPolicy)
+	}
+
+	linkHealthInterval, err := durationEnv("LINK_HEALTH_INTERVAL", 24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	linkHealthScan, err := durationEnv("LINK_HEALTH_SCAN_INTERVAL", 15*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	linkHealthTimeout, err := durationEnv("LINK_HEALTH_TIMEOUT", 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	linkHealthConcurrency, err := intEnv("LINK_HEALTH_CONCURRENCY", 8)
+	if err != nil {
+		return nil, err
+	}
+
+	linkHealthPerHost, err := intEnv("LINK_HEALTH_PER_HOST", 2)
+	if err != nil {
+		return nil, err
+	}
+
+	linkHealthThreshold, err := intEnv("LINK_HEALTH_FAILURE_THRESHOLD", 3)
+	if err != nil {
+		return nil, err
+	}
+
+	linkHealthMaxBackoff, err := durationEnv("LINK_HEALTH_MAX_BACKOFF", 7*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	stripTrackingParams, err := boolEnv("NORMALIZE_STRIP_TRACKING", false)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookTimeout, err := durationEnv("WEBHOOK_TIMEOUT", 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookWorkers, err := intEnv("WEBHOOK_WORKERS", 4)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookMaxAttempts, err := intEnv("WEBHOOK_MAX_ATTEMPTS", 8)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookBackoff, err := durationEnv("WEBHOOK_BACKOFF", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookMaxBackoff, err := durationEnv("WEBHOOK_MAX_BACKOFF", 6*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookRetryInterval, err := durationEnv("WEBHOOK_RETRY_INTERVAL", 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookClickFlush, err := durationEnv("WEBHOOK_CLICK_FLUSH_INTERVAL", time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookRetention, err := durationEnv("WEBHOOK_DELIVERY_RETENTION", 30*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookExpirySweep, err := durationEnv("WEBHOOK_EXPIRY_SWEEP_INTERVAL", time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	shortCodeGenerator := strings.ToLower(stringEnv("SHORTCODE_GENERATOR", "random"))
+	switch shortCodeGenerator {
+	case "random", "counter", "snowflake":
+	default:
+		return nil, fmt.Errorf("invalid SHORTCODE_GENERATOR %q: must be random, counter or snowflake", shortCodeGenerator)
+	}
+
+	shortCodeMaxAttempts, err := intEnv("SHORTCODE_MAX_ATTEMPTS", 3)
+	if err != nil {
+		return nil, err
+	}
+
+	shortCodeGrow, err := boolEnv("SHORTCODE_GROW_ON_COLLISION", false)
+	if err != nil {
+		return nil, err
+	}
+
+	shortCodeNodeID := -1
+	if v := os.Getenv("SHORTCODE_NODE_ID"); v != "" {
+		shortCodeNodeID, err = strconv.Atoi(v)
+		if err != nil || shortCodeNodeID < 0 || shortCodeNodeID > 1023 {
+			return nil, fmt.Errorf("SHORTCODE_NODE_ID must be an integer between 0 and 1023")
+		}
+	}
+
+	acmeEnabled, err := boolEnv("ACME_ENABLED", false)
+	if err != nil {
+		return nil, err
+	}
+
+	acmeCache := strings.ToLower(stringEnv("ACME_CACHE", "dir"))
+	switch acmeCache {
+	case "dir", "redis":
+	default:
+		return nil, fmt.Errorf("invalid ACME_CACHE %q: must be dir or redis", acmeCache)
+	}
+
 	return &Config{
 		Port:                port,
 		Environment:         env,
@@ -95,5 +363,152 @@ func Load() (*Config, error) {
 		ShutdownTimeout:     shutdownTimeout,
 		OTLPEndpoint:        os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"),
 		Version:             version,
+		AdminUserIDs:        splitList(os.Getenv("ADMIN_USER_IDS")),
+		ReportRateLimit:     reportRateLimit,
+		TrustedProxies:      trustedProxies,
+
+		ReputationBlocklistFile:    os.Getenv("REPUTATION_BLOCKLIST_FILE"),
+		ReputationFullHashURL:      os.Getenv("REPUTATION_FULL_HASH_URL"),
+		ReputationFullHashKey:      os.Getenv("REPUTATION_FULL_HASH_KEY"),
+		ReputationWebhookURL:       os.Getenv("REPUTATION_WEBHOOK_URL"),
+		ReputationWebhookToken:     os.Getenv("REPUTATION_WEBHOOK_TOKEN"),
+		ReputationTimeout:          reputationTimeout,
+		ReputationCacheTTL:         reputationCacheTTL,
+		ReputationRecheckInterval:  reputationRecheck,
+		ReputationBlocklistRefresh: blocklistRefresh,
+		ReputationFailClosed:       reputationFailClosed,
+
+		PreflightEnabled:      preflightEnabled,
+		PreflightTimeout:      preflightTimeout,
+		PreflightMaxRedirects: preflightMaxRedirects,
+		PreflightValidateSSL:  preflightValidateSSL,
+
+		ValidatorDNSTimeout: validatorDNSTimeout,
+		HomographPolicy:     homographPolicy,
+		ProtectedDomains:    splitList(os.Getenv("HOMOGRAPH_PROTECTED_DOMAINS")),
+
+		MetadataEnabled: metadataEnabled,
+		MetadataTimeout: metadataTimeout,
+		MetadataWorkers: metadataWorkers,
+
+		GeoCountryHeader: stringEnv("GEO_COUNTRY_HEADER", "CF-IPCountry"),
+
+		LinkHealthInterval:         linkHealthInterval,
+		LinkHealthScanInterval:     linkHealthScan,
+		LinkHealthTimeout:          linkHealthTimeout,
+		LinkHealthConcurrency:      linkHealthConcurrency,
+		LinkHealthPerHost:          linkHealthPerHost,
+		LinkHealthFailureThreshold: linkHealthThreshold,
+		LinkHealthMaxBackoff:       linkHealthMaxBackoff,
+
+		StripTrackingParams: stripTrackingParams,
+
+		WebhookTimeout:            webhookTimeout,
+		WebhookWorkers:            webhookWorkers,
+		WebhookMaxAttempts:        webhookMaxAttempts,
+		WebhookBackoff:            webhookBackoff,
+		WebhookMaxBackoff:         webhookMaxBackoff,
+		WebhookRetryInterval:      webhookRetryInterval,
+		WebhookClickFlushInterval: webhookClickFlush,
+		WebhookDeliveryRetention:  webhookRetention,
+		WebhookExpirySweep:        webhookExpirySweep,
+
+		ShortCodeGenerator:       shortCodeGenerator,
+		ShortCodeMaxAttempts:     shortCodeMaxAttempts,
+		ShortCodeGrowOnCollision: shortCodeGrow,
+		ShortCodeNodeID:          shortCodeNodeID,
+		ReservedCodes:            splitList(os.Getenv("RESERVED_CODES")),
+
+		IOSAppIDs:               splitList(os.Getenv("IOS_APP_IDS")),
+		IOSAppPaths:             splitList(os.Getenv("IOS_APP_PATHS")),
+		AndroidAppPackage:       os.Getenv("ANDROID_APP_PACKAGE"),
+		AndroidCertFingerprints: splitList(os.Getenv("ANDROID_CERT_FINGERPRINTS")),
+
+		ACMEEnabled:      acmeEnabled,
+		ACMEAddr:         stringEnv("ACME_ADDR", ":443"),
+		ACMEEmail:        os.Getenv("ACME_EMAIL"),
+		ACMEDirectoryURL: os.Getenv("ACME_DIRECTORY_URL"),
+		ACMECACert:       os.Getenv("ACME_CA_CERT"),
+		ACMECache:        acmeCache,
+		ACMECacheDir:     stringEnv("ACME_CACHE_DIR", "acme-certs"),
+		ACMEHosts:        splitList(os.Getenv("ACME_HOSTS")),
 	}, nil
 }
+
+func intEnv(name string, def int) (int, error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%s must be a positive integer", name)
+	}
+	return n, nil
+}
+
+func durationEnv(name string, def time.Duration) (time.Duration, error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative duration (e.g. 30s, 6h)", name)
+	}
+	return d, nil
+}
+
+func boolEnv(name string, def bool) (bool, error) {
+	v := os.Getenv(name)
+	if v == "" {
+		return def, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("%s must be a boolean", name)
+	}
+	return b, nil
+}
+
+func splitList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func parseProxies(raw string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, item := range splitList(raw) {
+		if !strings.Contains(item, "/") {
+			ip := net.ParseIP(item)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid TRUSTED_PROXIES entry %q: must be an IP or CIDR", item)
+			}
+			bits := 128
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(item)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TRUSTED_PROXIES entry %q: must be an IP or CIDR", item)
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+func stringEnv(name, def string) string {
+	if v := strings.TrimSpace(os.Getenv(name)); v != "" {
+		return v
+	}
+	return def
+}
@@ -0,0 +1,128 @@
+package deeplink
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const browserFallbackKey = "S.browser_fallback_url="
+
+var (
+	appIDPattern       = regexp.MustCompile(`^[A-Z0-9]{10}\.[A-Za-z0-9.\-]+$`)
+	packagePattern     = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*(\.[a-zA-Z][a-zA-Z0-9_]*)+$`)
+	fingerprintPattern = regexp.MustCompile(`^([0-9A-F]{2}:){31}[0-9A-F]{2}$`)
+)
+
+type Config struct {
+	IOSAppIDs           []string
+	IOSPaths            []string
+	AndroidPackage      string
+	AndroidFingerprints []string
+}
+
+type aasaComponent struct {
+	Path string `json:"/"`
+}
+
+type aasaDetail struct {
+	AppIDs     []string        `json:"appIDs"`
+	Components []aasaComponent `json:"components"`
+	Paths      []string        `json:"paths"`
+}
+
+type aasa struct {
+	AppLinks struct {
+		Apps    []string     `json:"apps"`
+		Details []aasaDetail `json:"details"`
+	} `json:"applinks"`
+}
+
+type assetLinkTarget struct {
+	Namespace    string   `json:"namespace"`
+	PackageName  string   `json:"package_name"`
+	Fingerprints []string `json:"sha256_cert_fingerprints"`
+}
+
+type assetLink struct {
+	Relation []string        `json:"relation"`
+	Target   assetLinkTarget `json:"target"`
+}
+
+func (c Config) Validate() error {
+	for _, id := range c.IOSAppIDs {
+		if !appIDPattern.MatchString(id) {
+			return fmt.Errorf("invalid iOS app ID %q: expected TEAMID.bundle.identifier", id)
+		}
+	}
+	if c.AndroidPackage != "" && !packagePattern.MatchString(c.AndroidPackage) {
+		return fmt.Errorf("invalid Android package name %q", c.AndroidPackage)
+	}
+	for _, fp := range c.AndroidFingerprints {
+		if !fingerprintPattern.MatchString(strings.ToUpper(fp)) {
+			return fmt.Errorf("invalid Android certificate fingerprint %q: expected 32 colon-separated hex bytes", fp)
+		}
+	}
+	if (c.AndroidPackage == "") != (len(c.AndroidFingerprints) == 0) {
+		return fmt.Errorf("android app links need both a package name and at least one certificate fingerprint")
+	}
+	return nil
+}
+
+func AppleAppSiteAssociation(cfg Config) ([]byte, error) {
+	if len(cfg.IOSAppIDs) == 0 {
+		return nil, nil
+	}
+	paths := cfg.IOSPaths
+	if len(paths) == 0 {
+		paths = []string{"*"}
+	}
+	components := make([]aasaComponent, 0, len(paths))
+	for _, p := range paths {
+		components = append(components, aasaComponent{Path: p})
+	}
+
+	var doc aasa
+	doc.AppLinks.Apps = []string{}
+	doc.AppLinks.Details = []aasaDetail{{
+		AppIDs:     cfg.IOSAppIDs,
+		Components: components,
+		Paths:      paths,
+	}}
+	return json.Marshal(doc)
+}
+
+func AssetLinks(cfg Config) ([]byte, error) {
+	if cfg.AndroidPackage == "" || len(cfg.AndroidFingerprints) == 0 {
+		return nil, nil
+	}
+	fingerprints := make([]string, 0, len(cfg.AndroidFingerprints))
+	for _, fp := range cfg.AndroidFingerprints {
+		fingerprints = append(fingerprints, strings.ToUpper(fp))
+	}
+	return json.Marshal([]assetLink{{
+		Relation: []string{"delegate_permission/common.handle_all_urls"},
+		Target: assetLinkTarget{
+			Namespace:    "android_app",
+			PackageName:  cfg.AndroidPackage,
+			Fingerprints: fingerprints,
+		},
+	}})
+}
+
+func IsIntent(target string) bool {
+	return strings.HasPrefix(strings.ToLower(target), "intent:")
+}
+
+func WithBrowserFallback(intent, fallback string) string {
+	if fallback == "" || !IsIntent(intent) || strings.Contains(intent, browserFallbackKey) {
+		return intent
+	}
+	idx := strings.LastIndex(intent, "end")
+	if idx < 0 {
+		return intent
+	}
+	return intent[:idx] + browserFallbackKey + url.QueryEscape(fallback) + ";" + intent[idx:]
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -12,6 +13,7 @@ import (
 	"url-shortener-go-backend/internal/handler/dto"
 	"url-shortener-go-backend/internal/handler/mapper"
 	"url-shortener-go-backend/internal/middleware"
+	"url-shortener-go-backend/internal/model"
 	"url-shortener-go-backend/internal/service"
 
 	"url-shortener-go-backend/internal/utils"
@@ -22,6 +24,7 @@ const (
 	ErrMsgUnauthorized     = "Authentication required"
 	ErrMsgInvalidLimit     = "Invalid limit parameter"
 	ErrMsgInvalidDays      = "Invalid days parameter"
+	ErrMsgInvalidCursor    = "Invalid cursor parameter"
 	ErrMsgInvalidRequest   = "Invalid request format"
 	ErrMsgInvalidURLID     = "Invalid URL identifier"
 	ErrMsgInternalError    = "An error occurred while processing your request"
@@ -31,6 +34,8 @@ const (
 	ErrMsgDevicesFetch     = "Unable to fetch device breakdown"
 	ErrMsgTrendFetch       = "Unable to fetch trend data"
 	ErrMsgRecordFailed     = "Unable to record analytics data"
+	ErrMsgVariantsFetch    = "Unable to fetch variant report"
+	ErrMsgURLNotFound      = "URL not found"
 
 	MaxLimit              = 100
 	MinLimit              = 1
@@ -55,11 +60,13 @@ var (
 
 type AnalyticsHandler struct {
 	analyticsService service.AnalyticsService
+	workspaceService service.WorkspaceService
 }
 
-func NewAnalyticsHandler(analyticsService service.AnalyticsService) *AnalyticsHandler {
+func NewAnalyticsHandler(analyticsService service.AnalyticsService, workspaceService service.WorkspaceService) *AnalyticsHandler {
 	return &AnalyticsHandler{
 		analyticsService: analyticsService,
+		workspaceService: workspaceService,
 	}
 }
 
@@ -78,9 +85,16 @@ func (h *AnalyticsHandler) HandleGetDashboard() http.HandlerFunc {
 			return
 		}
 
+		access, err := h.workspaceService.Resolve(r.Context(), userID, r.Header.Get(WorkspaceHeader))
+		if err != nil {
+			status, message := workspaceErrorStatus(err)
+			h.respondError(w, r, status, message, requestID)
+			return
+		}
+
 		slog.Info("fetching dashboard", "request_id", requestID, "user_id", truncateID(userID))
 
-		summary, err := h.analyticsService.GetUserDashboard(r.Context(), userID)
+		summary, err := h.analyticsService.GetWorkspaceDashboard(r.Context(), access.WorkspaceID)
 		if err != nil {
 			slog.Error("dashboard fetch failed", "request_id", requestID, "user_id", truncateID(userID), "error", err)
 			h.respondError(w, r, http.StatusInternalServerError,
@@ -115,15 +129,32 @@ func (h *AnalyticsHandler) HandleGetTopURLs() http.HandlerFunc {
 			return
 		}
 
+		access, err := h.workspaceService.Resolve(r.Context(), userID, r.Header.Get(WorkspaceHeader))
+		if err != nil {
+			status, message := workspaceErrorStatus(err)
+			h.respondError(w, r, status, message, requestID)
+			return
+		}
+
 		limit, err := h.parseLimit(r.URL.Query().Get("limit"), DefaultTopURLsLimit, MaxLimit)
 		if err != nil {
 			h.respondError(w, r, http.StatusBadRequest, ErrMsgInvalidLimit, requestID)
 			return
 		}
 
+		cursor, err := model.DecodePageCursor(r.URL.Query().Get("cursor"))
+		if err != nil {
+			h.respondError(w, r, http.StatusBadRequest, ErrMsgInvalidCursor, requestID)
+			return
+		}
+
 		slog.Info("fetching top urls", "request_id", requestID, "user_id", truncateID(userID), "limit", limit)
 
-		urls, err := h.analyticsService.GetUserTopURLs(r.Context(), userID, limit)
+		urls, err := h.analyticsService.GetWorkspaceTopURLs(r.Context(), access.WorkspaceID, limit, cursor)
+		if errors.Is(err, model.ErrInvalidCursor) {
+			h.respondError(w, r, http.StatusBadRequest, ErrMsgInvalidCursor, requestID)
+			return
+		}
 		if err != nil {
 			slog.Error("top urls fetch failed", "request_id", requestID, "user_id", truncateID(userID), "error", err)
 			h.respondError(w, r, http.StatusInternalServerError,
@@ -151,15 +182,32 @@ func (h *AnalyticsHandler) HandleGetTopReferrers() http.HandlerFunc {
 			return
 		}
 
+		access, err := h.workspaceService.Resolve(r.Context(), userID, r.Header.Get(WorkspaceHeader))
+		if err != nil {
+			status, message := workspaceErrorStatus(err)
+			h.respondError(w, r, status, message, requestID)
+			return
+		}
+
 		limit, err := h.parseLimit(r.URL.Query().Get("limit"), DefaultReferrersLimit, 50)
 		if err != nil {
 			h.respondError(w, r, http.StatusBadRequest, ErrMsgInvalidLimit, requestID)
 			return
 		}
 
+		cursor, err := model.DecodePageCursor(r.URL.Query().Get("cursor"))
+		if err != nil {
+			h.respondError(w, r, http.StatusBadRequest, ErrMsgInvalidCursor, requestID)
+			return
+		}
+
 		slog.Info("fetching top referrers", "request_id", requestID, "user_id", truncateID(userID), "limit", limit)
 
-		referrers, err := h.analyticsService.GetUserTopReferrers(r.Context(), userID, limit)
+		referrers, err := h.analyticsService.GetWorkspaceTopReferrers(r.Context(), access.WorkspaceID, limit, cursor)
+		if errors.Is(err, model.ErrInvalidCursor) {
+			h.respondError(w, r, http.StatusBadRequest, ErrMsgInvalidCursor, requestID)
+			return
+		}
 		if err != nil {
 			slog.Error("top referrers fetch failed", "request_id", requestID, "user_id", truncateID(userID), "error", err)
 			h.respondError(w, r, http.StatusInternalServerError,
@@ -172,6 +220,53 @@ func (h *AnalyticsHandler) HandleGetTopReferrers() http.HandlerFunc {
 	}
 }
 
+func (h *AnalyticsHandler) HandleGetVariantReport() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetRequestID(r.Context())
+
+		if r.Method != http.MethodGet {
+			h.respondError(w, r, http.StatusMethodNotAllowed, ErrMsgMethodNotAllowed, requestID)
+			return
+		}
+
+		userID := middleware.GetUserIDFromContext(r.Context())
+		if userID == "" {
+			h.respondError(w, r, http.StatusUnauthorized, ErrMsgUnauthorized, requestID)
+			return
+		}
+
+		access, err := h.workspaceService.Resolve(r.Context(), userID, r.Header.Get(WorkspaceHeader))
+		if err != nil {
+			status, message := workspaceErrorStatus(err)
+			h.respondError(w, r, status, message, requestID)
+			return
+		}
+
+		urlID := strings.TrimSpace(r.URL.Query().Get("url_id"))
+		if err := validateURLID(urlID); err != nil {
+			h.respondError(w, r, http.StatusBadRequest, ErrMsgInvalidURLID, requestID)
+			return
+		}
+
+		slog.Info("fetching variant report", "request_id", requestID, "user_id", truncateID(userID), "url_id", urlID)
+
+		stats, err := h.analyticsService.GetURLVariantStats(r.Context(), access.WorkspaceID, urlID)
+		if err != nil {
+			if errors.Is(err, utils.ErrNotFound) {
+				h.respondError(w, r, http.StatusNotFound, ErrMsgURLNotFound, requestID)
+				return
+			}
+			slog.Error("variant report fetch failed", "request_id", requestID, "user_id", truncateID(userID), "error", err)
+			h.respondError(w, r, http.StatusInternalServerError,
+				utils.SanitizeError(err, ErrMsgVariantsFetch), requestID)
+			return
+		}
+
+		response := mapper.ToVariantReportResponse(urlID, stats)
+		h.respondJSON(w, http.StatusOK, response, requestID)
+	}
+}
+
 func (h *AnalyticsHandler) HandleGetDeviceBreakdown() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		requestID := middleware.GetRequestID(r.Context())
@@ -187,9 +282,16 @@ func (h *AnalyticsHandler) HandleGetDeviceBreakdown() http.HandlerFunc {
 			return
 		}
 
+		access, err := h.workspaceService.Resolve(r.Context(), userID, r.Header.Get(WorkspaceHeader))
+		if err != nil {
+			status, message := workspaceErrorStatus(err)
+			h.respondError(w, r, status, message, requestID)
+			return
+		}
+
 		slog.Info("fetching device breakdown", "request_id", requestID, "user_id", truncateID(userID))
 
-		devices, err := h.analyticsService.GetUserDeviceBreakdown(r.Context(), userID)
+		devices, err := h.analyticsService.GetWorkspaceDeviceBreakdown(r.Context(), access.WorkspaceID)
 		if err != nil {
 			slog.Error("device breakdown fetch failed", "request_id", requestID, "user_id", truncateID(userID), "error", err)
 			h.respondError(w, r, http.StatusInternalServerError,
@@ -217,6 +319,13 @@ func (h *AnalyticsHandler) HandleGetDailyTrend() http.HandlerFunc {
 			return
 		}
 
+		access, err := h.workspaceService.Resolve(r.Context(), userID, r.Header.Get(WorkspaceHeader))
+		if err != nil {
+			status, message := workspaceErrorStatus(err)
+			h.respondError(w, r, status, message, requestID)
+			return
+		}
+
 		days, err := h.parseDays(r.URL.Query().Get("days"), DefaultTrendDays)
 		if err != nil {
 			h.respondError(w, r, http.StatusBadRequest, ErrMsgInvalidDays, requestID)
@@ -225,7 +334,7 @@ func (h *AnalyticsHandler) HandleGetDailyTrend() http.HandlerFunc {
 
 		slog.Info("fetching daily trend", "request_id", requestID, "user_id", truncateID(userID), "days", days)
 
-		trend, err := h.analyticsService.GetUserDailyTrend(r.Context(), userID, days)
+		trend, err := h.analyticsService.GetWorkspaceDailyTrend(r.Context(), access.WorkspaceID, days)
 
 		if err != nil {
 			slog.Error("daily trend fetch failed", "request_id", requestID, "user_id", truncateID(userID), "error", err)
@@ -324,21 +433,8 @@ func (h *AnalyticsHandler) parseDays(daysStr string, defaultDays int) (int, erro
 
 func (h *AnalyticsHandler) validateAnalyticsRequest(req *dto.RecordAnalyticsRequest) error {
 	req.URLID = strings.TrimSpace(req.URLID)
-	if req.URLID == "" {
-		return fmt.Errorf("url_id is required")
-	}
-
-	if len(req.URLID) > MaxURLIDLength {
-		return fmt.Errorf("url_id is too long")
-	}
-
-	for _, char := range req.URLID {
-		if !((char >= 'a' && char <= 'z') ||
-			(char >= 'A' && char <= 'Z') ||
-			(char >= '0' && char <= '9') ||
-			char == '-' || char == '_') {
-			return fmt.Errorf("url_id contains invalid characters")
-		}
+	if err := validateURLID(req.URLID); err != nil {
+		return err
 	}
 
 	req.Referrer = h.sanitizeReferrer(req.Referrer)
@@ -356,6 +452,27 @@ func (h *AnalyticsHandler) validateAnalyticsRequest(req *dto.RecordAnalyticsRequ
 	return nil
 }
 
+func validateURLID(urlID string) error {
+	if urlID == "" {
+		return fmt.Errorf("url_id is required")
+	}
+
+	if len(urlID) > MaxURLIDLength {
+		return fmt.Errorf("url_id is too long")
+	}
+
+	for _, char := range urlID {
+		if !((char >= 'a' && char <= 'z') ||
+			(char >= 'A' && char <= 'Z') ||
+			(char >= '0' && char <= '9') ||
+			char == '-' || char == '_') {
+			return fmt.Errorf("url_id contains invalid characters")
+		}
+	}
+
+	return nil
+}
+
 func (h *AnalyticsHandler) sanitizeReferrer(referrer string) string {
 	referrer = strings.TrimSpace(referrer)
 
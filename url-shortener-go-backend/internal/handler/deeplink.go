@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"html/template"
+	"net/http"
+
+	"url-shortener-go-backend/internal/deeplink"
+	"url-shortener-go-backend/internal/metrics"
+	"url-shortener-go-backend/internal/model"
+)
+
+const appBounceTimeout = 1500
+
+var appBouncePage = template.Must(template.Must(template.New("layout").Parse(pageLayout)).Parse(`
+{{define "title"}}Opening app{{end}}
+{{define "content"}}
+<h1>Opening the app&hellip;</h1>
+<p>If nothing happens, use one of the options below.</p>
+<a class="button" href="{{.AppURL}}">Open in app</a>
+<p class="muted"><a href="{{.Fallback}}" rel="noopener noreferrer">Continue to the website</a></p>
+<script>
+(function(){
+var app={{.AppURLString}},fallback={{.Fallback}};
+var left=false;
+document.addEventListener("visibilitychange",function(){if(document.hidden){left=true;}});
+window.location.replace(app);
+if(fallback){setTimeout(function(){if(!left&&!document.hidden){window.location.replace(fallback);}},{{.Timeout}});}
+})();
+</script>
+{{end}}`))
+
+type appBouncePageData struct {
+	AppURL       template.URL
+	AppURLString string
+	Fallback     string
+	Timeout      int
+}
+
+func (h *URLHandler) redirectToApp(w http.ResponseWriter, r *http.Request, platform string, target model.AppTarget, destination string, status int) {
+	fallback := target.Fallback
+	if fallback == "" {
+		fallback = destination
+	}
+
+	if target.IsWeb() {
+		metrics.URLDeepLinksTotal.WithLabelValues(platform, "universal").Inc()
+		http.Redirect(w, r, target.URL, status)
+		return
+	}
+
+	appURL, kind := target.URL, "scheme"
+	if deeplink.IsIntent(appURL) {
+		appURL, kind = deeplink.WithBrowserFallback(appURL, fallback), "intent"
+	}
+	metrics.URLDeepLinksTotal.WithLabelValues(platform, kind).Inc()
+
+	renderPage(w, http.StatusOK, appBouncePage, appBouncePageData{
+		AppURL:       template.URL(appURL),
+		AppURLString: appURL,
+		Fallback:     fallback,
+		Timeout:      appBounceTimeout,
+	})
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"url-shortener-go-backend/internal/handler/dto"
+	"url-shortener-go-backend/internal/handler/mapper"
+	"url-shortener-go-backend/internal/middleware"
+	"url-shortener-go-backend/internal/service"
+	"url-shortener-go-backend/internal/utils"
+)
+
+type DomainHandler struct {
+	domainService service.DomainService
+}
+
+func NewDomainHandler(domainService service.DomainService) *DomainHandler {
+	return &DomainHandler{domainService: domainService}
+}
+
+func (h *DomainHandler) HandleDomains() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetRequestID(r.Context())
+		userID := middleware.GetUserIDFromContext(r.Context())
+		if userID == "" {
+			utils.RespondError(w, http.StatusUnauthorized, ErrMsgUnauthorized, requestID)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			domains, err := h.domainService.ListDomains(r.Context(), userID)
+			if err != nil {
+				utils.RespondError(w, http.StatusInternalServerError, "Unable to list domains", requestID)
+				return
+			}
+			utils.RespondJSON(w, http.StatusOK, mapper.ToDomainsResponse(domains), requestID)
+		case http.MethodPost:
+			var req dto.CreateDomainRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				utils.RespondError(w, http.StatusBadRequest, ErrMsgInvalidRequest, requestID)
+				return
+			}
+			domain, err := h.domainService.AddDomain(r.Context(), userID, req.Hostname)
+			if err != nil {
+				h.respondDomainError(w, requestID, err)
+				return
+			}
+			utils.RespondJSON(w, http.StatusCreated, mapper.ToDomainResponse(*domain), requestID)
+		default:
+			utils.RespondError(w, http.StatusMethodNotAllowed, ErrMsgMethodNotAllowed, requestID)
+		}
+	}
+}
+
+func (h *DomainHandler) HandleDomain() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetRequestID(r.Context())
+		userID := middleware.GetUserIDFromContext(r.Context())
+		if userID == "" {
+			utils.RespondError(w, http.StatusUnauthorized, ErrMsgUnauthorized, requestID)
+			return
+		}
+
+		path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/domains/"), "/")
+		hostname, action, _ := strings.Cut(path, "/")
+
+		switch {
+		case action == "verify" && r.Method == http.MethodPost:
+			domain, err := h.domainService.VerifyDomain(r.Context(), userID, hostname)
+			if err != nil {
+				h.respondDomainError(w, requestID, err)
+				return
+			}
+			utils.RespondJSON(w, http.StatusOK, mapper.ToDomainResponse(*domain), requestID)
+		case action == "" && r.Method == http.MethodDelete:
+			if err := h.domainService.DeleteDomain(r.Context(), userID, hostname); err != nil {
+				h.respondDomainError(w, requestID, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case action == "" || action == "verify":
+			utils.RespondError(w, http.StatusMethodNotAllowed, ErrMsgMethodNotAllowed, requestID)
+		default:
+			http.NotFound(w, r)
+		}
+	}
+}
+
+func (h *DomainHandler) respondDomainError(w http.ResponseWriter, requestID string, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidDomain):
+		utils.RespondError(w, http.StatusBadRequest, "Invalid domain name", requestID)
+	case errors.Is(err, service.ErrDomainTaken):
+		utils.RespondError(w, http.StatusConflict, "Domain is already registered", requestID)
+	case errors.Is(err, service.ErrDomainVerificationFailed):
+		utils.RespondError(w, http.StatusUnprocessableEntity, "Verification TXT record not found", requestID)
+	case errors.Is(err, utils.ErrNotFound):
+		utils.RespondError(w, http.StatusNotFound, "Domain not found", requestID)
+	default:
+		slog.Error("domain request failed", "request_id", requestID, "error", err)
+		utils.RespondError(w, http.StatusInternalServerError, ErrMsgInternalError, requestID)
+	}
+}
@@ -18,11 +18,12 @@ type AnalyticsOverview struct {
 }
 
 type TopURLResponse struct {
-	URLID       string `json:"url_id"`
-	ShortCode   string `json:"short_code"`
-	OriginalURL string `json:"original_url"`
-	ClickCount  int64  `json:"click_count"`
-	CreatedAt   string `json:"created_at"`
+	URLID         string `json:"url_id"`
+	ShortCode     string `json:"short_code"`
+	OriginalURL   string `json:"original_url"`
+	NormalizedURL string `json:"normalized_url,omitempty"`
+	ClickCount    int64  `json:"click_count"`
+	CreatedAt     string `json:"created_at"`
 }
 
 type ReferrerResponse struct {
@@ -47,11 +48,27 @@ type AnalyticsRequest struct {
 }
 
 type TopURLsResponse struct {
-	URLs []TopURLResponse `json:"urls"`
+	URLs       []TopURLResponse `json:"urls"`
+	NextCursor string           `json:"next_cursor,omitempty"`
 }
 
 type TopReferrersResponse struct {
-	Referrers []ReferrerResponse `json:"referrers"`
+	Referrers  []ReferrerResponse `json:"referrers"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+}
+
+type VariantReportResponse struct {
+	URLID       string                 `json:"url_id"`
+	TotalClicks int64                  `json:"total_clicks"`
+	Variants    []VariantStatsResponse `json:"variants"`
+}
+
+type VariantStatsResponse struct {
+	Variant     string  `json:"variant"`
+	Destination string  `json:"destination,omitempty"`
+	Weight      int     `json:"weight,omitempty"`
+	Clicks      int64   `json:"clicks"`
+	Share       float64 `json:"share"`
 }
 
 type DeviceBreakdownResponse struct {
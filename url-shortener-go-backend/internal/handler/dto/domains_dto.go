@@ -0,0 +1,23 @@
+package dto
+
+type CreateDomainRequest struct {
+	Hostname string `json:"hostname"`
+}
+
+type DomainResponse struct {
+	Hostname     string                 `json:"hostname"`
+	Verified     bool                   `json:"verified"`
+	VerifiedAt   string                 `json:"verified_at,omitempty"`
+	CreatedAt    string                 `json:"created_at"`
+	Verification *DomainVerificationTXT `json:"verification,omitempty"`
+}
+
+type DomainVerificationTXT struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type DomainsResponse struct {
+	Domains []DomainResponse `json:"domains"`
+}
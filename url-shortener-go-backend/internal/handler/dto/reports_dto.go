@@ -0,0 +1,37 @@
+package dto
+
+type CreateReportRequest struct {
+	Reason  string `json:"reason"`
+	Details string `json:"details,omitempty"`
+}
+
+type ResolveReportRequest struct {
+	Action string `json:"action"`
+	Note   string `json:"note,omitempty"`
+}
+
+type SetURLStatusRequest struct {
+	Disabled bool   `json:"disabled"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+type ReportResponse struct {
+	ID         string `json:"id"`
+	ShortCode  string `json:"short_code"`
+	Reason     string `json:"reason"`
+	Details    string `json:"details,omitempty"`
+	Status     string `json:"status"`
+	Resolution string `json:"resolution,omitempty"`
+	CreatedAt  string `json:"created_at"`
+	ResolvedAt string `json:"resolved_at,omitempty"`
+}
+
+type ReportsResponse struct {
+	Reports []ReportResponse `json:"reports"`
+}
+
+type URLStatusResponse struct {
+	ShortCode      string `json:"short_code"`
+	Disabled       bool   `json:"disabled"`
+	DisabledReason string `json:"disabled_reason,omitempty"`
+}
@@ -1,22 +1,154 @@
 package dto
 
+import "time"
+
 type ShortenURLRequest struct {
 	OriginalURL string `json:"url" validate:"required,url"`
 	IsPublic    bool   `json:"is_public"`
 	CodeLength  int8   `json:"code_length"`
+	CodeStyle   string `json:"code_style"`
+	Alias       string `json:"alias"`
+	Domain      string `json:"domain"`
+	Dedup       string `json:"dedup"`
+
+	RequirePreview bool `json:"require_preview"`
+	RedirectStatus int  `json:"redirect_status"`
+	CacheMaxAge    int  `json:"cache_max_age"`
+	NoStore        bool `json:"no_store"`
+
+	UTM          *UTMParams     `json:"utm"`
+	ForwardQuery string         `json:"forward_query"`
+	Rules        []RedirectRule `json:"rules"`
+	Variants     []Variant      `json:"variants"`
+	DeepLinks    *DeepLinks     `json:"deep_links"`
+
+	Tags      []string   `json:"tags"`
+	Folder    string     `json:"folder"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type RedirectRule struct {
+	Name        string     `json:"name,omitempty"`
+	Devices     []string   `json:"devices,omitempty"`
+	OS          []string   `json:"os,omitempty"`
+	Countries   []string   `json:"countries,omitempty"`
+	Languages   []string   `json:"languages,omitempty"`
+	StartsAt    *time.Time `json:"starts_at,omitempty"`
+	EndsAt      *time.Time `json:"ends_at,omitempty"`
+	Destination string     `json:"destination"`
+}
+
+type Variant struct {
+	Name        string `json:"name,omitempty"`
+	Destination string `json:"destination"`
+	Weight      int    `json:"weight,omitempty"`
+}
+
+type DeepLinks struct {
+	IOS     *AppTarget `json:"ios,omitempty"`
+	Android *AppTarget `json:"android,omitempty"`
+}
+
+type AppTarget struct {
+	URL      string `json:"url"`
+	Fallback string `json:"fallback,omitempty"`
+}
+
+type UTMParams struct {
+	Source   string `json:"source"`
+	Medium   string `json:"medium"`
+	Campaign string `json:"campaign"`
+	Term     string `json:"term"`
+	Content  string `json:"content"`
+}
+
+type UpdateURLRequest struct {
+	RequirePreview *bool           `json:"require_preview"`
+	OGTitle        *string         `json:"og_title"`
+	OGDescription  *string         `json:"og_description"`
+	OGImage        *string         `json:"og_image"`
+	RedirectStatus *int            `json:"redirect_status"`
+	CacheMaxAge    *int            `json:"cache_max_age"`
+	NoStore        *bool           `json:"no_store"`
+	ForwardQuery   *string         `json:"forward_query"`
+	Rules          *[]RedirectRule `json:"rules"`
+	Variants       *[]Variant      `json:"variants"`
+	DeepLinks      *DeepLinks      `json:"deep_links"`
+	Tags           *[]string       `json:"tags"`
+	Folder         *string         `json:"folder"`
+	ExpiresAt      *string         `json:"expires_at"`
 }
 
 type ShortenURLResponse struct {
-	ID         string `json:"id"`
-	ShortCode  string `json:"short_code"`
-	ShortURL   string `json:"short_url"`
-	CreatedAt  string `json:"created_at"`
-	IsPublic   bool   `json:"is_public"`
-	ClickCount int    `json:"click_count"`
+	ID          string `json:"id"`
+	ShortCode   string `json:"short_code"`
+	ShortURL    string `json:"short_url"`
+	Domain      string `json:"domain,omitempty"`
+	WorkspaceID string `json:"workspace_id,omitempty"`
+	QRURL       string `json:"qr_url"`
+	CreatedAt   string `json:"created_at"`
+	IsPublic    bool   `json:"is_public"`
+	ClickCount  int    `json:"click_count"`
+	Reused      bool   `json:"reused,omitempty"`
+
+	RequirePreview bool   `json:"require_preview"`
+	RedirectStatus int    `json:"redirect_status"`
+	CacheMaxAge    int    `json:"cache_max_age,omitempty"`
+	NoStore        bool   `json:"no_store"`
+	ForwardQuery   string `json:"forward_query,omitempty"`
+
+	Rules    []RedirectRule `json:"rules,omitempty"`
+	Variants []Variant      `json:"variants,omitempty"`
+
+	DeepLinks *DeepLinks `json:"deep_links,omitempty"`
+
+	Tags      []string `json:"tags"`
+	Folder    string   `json:"folder,omitempty"`
+	ExpiresAt string   `json:"expires_at,omitempty"`
+	Expired   bool     `json:"expired"`
+
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	ImageURL    string `json:"image_url,omitempty"`
+	FaviconURL  string `json:"favicon_url,omitempty"`
+
+	OGTitle       string `json:"og_title,omitempty"`
+	OGDescription string `json:"og_description,omitempty"`
+	OGImage       string `json:"og_image,omitempty"`
+
+	ResolvedURL    string `json:"resolved_url,omitempty"`
+	ResolvedStatus int    `json:"resolved_status,omitempty"`
+
+	Health *LinkHealth `json:"health,omitempty"`
+
+	Host *HostForms `json:"host,omitempty"`
+}
+
+type LinkHealth struct {
+	Status      string        `json:"status"`
+	Failures    int           `json:"consecutive_failures"`
+	CheckedAt   string        `json:"checked_at,omitempty"`
+	NextCheckAt string        `json:"next_check_at,omitempty"`
+	History     []HealthCheck `json:"history,omitempty"`
+}
+
+type HealthCheck struct {
+	CheckedAt  string `json:"checked_at"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Error      string `json:"error,omitempty"`
+	LatencyMS  int64  `json:"latency_ms"`
+	Healthy    bool   `json:"healthy"`
+}
+
+type HostForms struct {
+	ASCII    string   `json:"ascii"`
+	Unicode  string   `json:"unicode"`
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 type GetUserURLsResponse struct {
-	URLs []ShortenURLResponse `json:"urls"`
+	URLs       []ShortenURLResponse `json:"urls"`
+	NextCursor string               `json:"next_cursor,omitempty"`
 }
 
 type ErrorResponse struct {
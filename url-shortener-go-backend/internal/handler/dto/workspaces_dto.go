@@ -0,0 +1,42 @@
+package dto
+
+type CreateWorkspaceRequest struct {
+	Name string `json:"name"`
+}
+
+type UpdateWorkspaceRequest struct {
+	Name        *string `json:"name"`
+	DedupPolicy *string `json:"dedup_policy"`
+}
+
+type WorkspaceResponse struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Personal    bool   `json:"personal"`
+	Role        string `json:"role,omitempty"`
+	DedupPolicy string `json:"dedup_policy"`
+	CreatedAt   string `json:"created_at"`
+}
+
+type WorkspacesResponse struct {
+	Workspaces []WorkspaceResponse `json:"workspaces"`
+}
+
+type AddWorkspaceMemberRequest struct {
+	UserID string `json:"user_id"`
+	Role   string `json:"role"`
+}
+
+type UpdateWorkspaceMemberRequest struct {
+	Role string `json:"role"`
+}
+
+type WorkspaceMemberResponse struct {
+	UserID    string `json:"user_id"`
+	Role      string `json:"role"`
+	CreatedAt string `json:"created_at"`
+}
+
+type WorkspaceMembersResponse struct {
+	Members []WorkspaceMemberResponse `json:"members"`
+}
@@ -1,6 +1,8 @@
 package mapper
 
 import (
+	"math"
+
 	"url-shortener-go-backend/internal/handler/dto"
 	"url-shortener-go-backend/internal/model"
 )
@@ -62,11 +64,12 @@ func ToTopURLResponses(urls []model.URLClickStats) []dto.TopURLResponse {
 	var responses []dto.TopURLResponse
 	for _, url := range urls {
 		responses = append(responses, dto.TopURLResponse{
-			URLID:       url.URLID,
-			ShortCode:   url.ShortCode,
-			OriginalURL: url.OriginalURL,
-			ClickCount:  url.ClickCount,
-			CreatedAt:   url.CreatedAt,
+			URLID:         url.URLID,
+			ShortCode:     url.ShortCode,
+			OriginalURL:   url.OriginalURL,
+			NormalizedURL: url.NormalizedURL,
+			ClickCount:    url.ClickCount,
+			CreatedAt:     url.CreatedAt,
 		})
 	}
 	return responses
@@ -117,16 +120,36 @@ func ToDailyTrendResponses(trend []model.DailyClickStats) []dto.DailyTrendRespon
 	return responses
 }
 
-func ToTopURLsResponse(urls []model.URLClickStats) dto.TopURLsResponse {
+func ToTopURLsResponse(page model.Page[model.URLClickStats]) dto.TopURLsResponse {
 	return dto.TopURLsResponse{
-		URLs: ToTopURLResponses(urls),
+		URLs:       ToTopURLResponses(page.Items),
+		NextCursor: page.NextCursor,
 	}
 }
 
-func ToTopReferrersResponse(referrers []model.ReferrerStats) dto.TopReferrersResponse {
+func ToTopReferrersResponse(page model.Page[model.ReferrerStats]) dto.TopReferrersResponse {
 	return dto.TopReferrersResponse{
-		Referrers: ToReferrerResponses(referrers),
+		Referrers:  ToReferrerResponses(page.Items),
+		NextCursor: page.NextCursor,
+	}
+}
+
+func ToVariantReportResponse(urlID string, stats []model.VariantStats) dto.VariantReportResponse {
+	resp := dto.VariantReportResponse{
+		URLID:    urlID,
+		Variants: make([]dto.VariantStatsResponse, 0, len(stats)),
+	}
+	for _, s := range stats {
+		resp.TotalClicks += s.Clicks
+		resp.Variants = append(resp.Variants, dto.VariantStatsResponse{
+			Variant:     s.Variant,
+			Destination: s.Destination,
+			Weight:      s.Weight,
+			Clicks:      s.Clicks,
+			Share:       math.Round(s.Share*10000) / 10000,
+		})
 	}
+	return resp
 }
 
 func ToDeviceBreakdownResponse(devices []model.DeviceStats) dto.DeviceBreakdownResponse {
@@ -163,11 +186,12 @@ func fromTopURLResponses(urls []dto.TopURLResponse) []model.URLClickStats {
 	out := make([]model.URLClickStats, len(urls))
 	for i, u := range urls {
 		out[i] = model.URLClickStats{
-			URLID:       u.URLID,
-			ShortCode:   u.ShortCode,
-			OriginalURL: u.OriginalURL,
-			ClickCount:  u.ClickCount,
-			CreatedAt:   u.CreatedAt,
+			URLID:         u.URLID,
+			ShortCode:     u.ShortCode,
+			OriginalURL:   u.OriginalURL,
+			NormalizedURL: u.NormalizedURL,
+			ClickCount:    u.ClickCount,
+			CreatedAt:     u.CreatedAt,
 		}
 	}
 	return out
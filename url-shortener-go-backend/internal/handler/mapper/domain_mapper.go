@@ -0,0 +1,34 @@
+package mapper
+
+import (
+	"time"
+
+	"url-shortener-go-backend/internal/handler/dto"
+	"url-shortener-go-backend/internal/model"
+)
+
+func ToDomainResponse(d model.Domain) dto.DomainResponse {
+	resp := dto.DomainResponse{
+		Hostname:  d.Hostname,
+		Verified:  d.IsVerified(),
+		CreatedAt: d.CreatedAt.Format(time.RFC3339),
+	}
+	if d.IsVerified() {
+		resp.VerifiedAt = d.VerifiedAt.Format(time.RFC3339)
+	} else {
+		resp.Verification = &dto.DomainVerificationTXT{
+			Type:  "TXT",
+			Name:  d.TXTRecordName(),
+			Value: d.TXTRecordValue(),
+		}
+	}
+	return resp
+}
+
+func ToDomainsResponse(domains []model.Domain) dto.DomainsResponse {
+	resp := dto.DomainsResponse{Domains: make([]dto.DomainResponse, 0, len(domains))}
+	for _, d := range domains {
+		resp.Domains = append(resp.Domains, ToDomainResponse(d))
+	}
+	return resp
+}
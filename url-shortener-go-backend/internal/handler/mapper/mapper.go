@@ -1,19 +1,95 @@
 package mapper
 
 import (
+	"strings"
+	"time"
+
 	"url-shortener-go-backend/internal/handler/dto"
+	"url-shortener-go-backend/internal/homograph"
 	"url-shortener-go-backend/internal/model"
 )
 
 func ToShortenURLResponse(url model.URL) dto.ShortenURLResponse {
-	return dto.ShortenURLResponse{
+	resp := dto.ShortenURLResponse{
 		ID:         url.ID,
 		ShortCode:  url.ShortCode,
 		ShortURL:   url.ShortURL,
+		Domain:     url.Domain,
+		QRURL:      qrURL(url),
 		CreatedAt:  url.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		IsPublic:   url.IsPublic,
 		ClickCount: url.ClickCount,
+
+		RequirePreview: url.RequirePreview,
+		RedirectStatus: url.EffectiveRedirectStatus(),
+		CacheMaxAge:    url.CacheMaxAge,
+		NoStore:        url.NoStore,
+		ForwardQuery:   url.ForwardQuery,
+		Rules:          ToRedirectRules(url.Rules),
+		Variants:       ToVariants(url.Variants),
+		DeepLinks:      ToDeepLinks(url.DeepLinks),
+
+		Tags:    url.Tags,
+		Folder:  url.Folder,
+		Expired: url.IsExpired(time.Now()),
+
+		Title:       url.Title,
+		Description: url.Description,
+		ImageURL:    url.ImageURL,
+		FaviconURL:  url.FaviconURL,
+
+		OGTitle:       url.OGTitle,
+		OGDescription: url.OGDescription,
+		OGImage:       url.OGImage,
+
+		ResolvedURL:    url.ResolvedURL,
+		ResolvedStatus: url.ResolvedStatus,
+	}
+	if url.WorkspaceID != nil {
+		resp.WorkspaceID = *url.WorkspaceID
+	}
+	if resp.Tags == nil {
+		resp.Tags = []string{}
+	}
+	if url.ExpiresAt != nil {
+		resp.ExpiresAt = url.ExpiresAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	resp.Health = ToLinkHealth(url)
+	return resp
+}
+
+func ToLinkHealth(url model.URL) *dto.LinkHealth {
+	if url.HealthStatus == "" {
+		return nil
+	}
+	health := &dto.LinkHealth{
+		Status:   url.HealthStatus,
+		Failures: url.HealthFailures,
+	}
+	if url.HealthCheckedAt != nil {
+		health.CheckedAt = url.HealthCheckedAt.Format("2006-01-02T15:04:05Z07:00")
 	}
+	if url.HealthNextCheckAt != nil {
+		health.NextCheckAt = url.HealthNextCheckAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	for _, c := range url.HealthHistory {
+		health.History = append(health.History, dto.HealthCheck{
+			CheckedAt:  c.CheckedAt.Format("2006-01-02T15:04:05Z07:00"),
+			StatusCode: c.StatusCode,
+			Error:      c.Error,
+			LatencyMS:  c.LatencyMS,
+			Healthy:    c.Healthy,
+		})
+	}
+	return health
+}
+
+func qrURL(url model.URL) string {
+	base := url.BaseURL
+	if base == "" {
+		base = strings.TrimSuffix(url.ShortURL, "/"+url.ShortCode)
+	}
+	return base + "/api/urls/" + url.Key() + "/qr"
 }
 
 func ToShortenURLResponses(urls []model.URL) []dto.ShortenURLResponse {
@@ -24,9 +100,10 @@ func ToShortenURLResponses(urls []model.URL) []dto.ShortenURLResponse {
 	return responses
 }
 
-func ToGetUrlsResponse(urls []model.URL) dto.GetUserURLsResponse {
+func ToGetUrlsResponse(page model.Page[model.URL]) dto.GetUserURLsResponse {
 	return dto.GetUserURLsResponse{
-		URLs: ToShortenURLResponses(urls),
+		URLs:       ToShortenURLResponses(page.Items),
+		NextCursor: page.NextCursor,
 	}
 }
 
@@ -36,3 +113,69 @@ func ToGetURLByShortCodeResponse(url model.URL) dto.GetURLByShortCodeResponse {
 		ClickCount:  url.ClickCount,
 	}
 }
+
+func ToHostForms(a homograph.Analysis) *dto.HostForms {
+	return &dto.HostForms{
+		ASCII:    a.ASCII,
+		Unicode:  a.Unicode,
+		Warnings: a.Warnings(),
+	}
+}
+
+func ToRedirectRules(rules []model.RedirectRule) []dto.RedirectRule {
+	if len(rules) == 0 {
+		return nil
+	}
+	out := make([]dto.RedirectRule, 0, len(rules))
+	for _, r := range rules {
+		out = append(out, dto.RedirectRule(r))
+	}
+	return out
+}
+
+func FromRedirectRules(rules []dto.RedirectRule) []model.RedirectRule {
+	out := make([]model.RedirectRule, 0, len(rules))
+	for _, r := range rules {
+		out = append(out, model.RedirectRule(r))
+	}
+	return out
+}
+
+func ToVariants(variants []model.Variant) []dto.Variant {
+	if len(variants) == 0 {
+		return nil
+	}
+	out := make([]dto.Variant, 0, len(variants))
+	for _, v := range variants {
+		out = append(out, dto.Variant(v))
+	}
+	return out
+}
+
+func FromVariants(variants []dto.Variant) []model.Variant {
+	out := make([]model.Variant, 0, len(variants))
+	for _, v := range variants {
+		out = append(out, model.Variant(v))
+	}
+	return out
+}
+
+func ToDeepLinks(d *model.DeepLinks) *dto.DeepLinks {
+	if d.IsEmpty() {
+		return nil
+	}
+	return &dto.DeepLinks{
+		IOS:     (*dto.AppTarget)(d.IOS),
+		Android: (*dto.AppTarget)(d.Android),
+	}
+}
+
+func FromDeepLinks(d *dto.DeepLinks) *model.DeepLinks {
+	if d == nil {
+		return nil
+	}
+	return &model.DeepLinks{
+		IOS:     (*model.AppTarget)(d.IOS),
+		Android: (*model.AppTarget)(d.Android),
+	}
+}
@@ -0,0 +1,38 @@
+package mapper
+
+import (
+	"url-shortener-go-backend/internal/handler/dto"
+	"url-shortener-go-backend/internal/model"
+)
+
+func ToReportResponse(report model.Report) dto.ReportResponse {
+	resp := dto.ReportResponse{
+		ID:         report.ID,
+		ShortCode:  report.ShortCode,
+		Reason:     report.Reason,
+		Details:    report.Details,
+		Status:     report.Status,
+		Resolution: report.Resolution,
+		CreatedAt:  report.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if report.ResolvedAt != nil {
+		resp.ResolvedAt = report.ResolvedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return resp
+}
+
+func ToReportsResponse(reports []model.Report) dto.ReportsResponse {
+	responses := make([]dto.ReportResponse, 0, len(reports))
+	for _, r := range reports {
+		responses = append(responses, ToReportResponse(r))
+	}
+	return dto.ReportsResponse{Reports: responses}
+}
+
+func ToURLStatusResponse(url model.URL) dto.URLStatusResponse {
+	return dto.URLStatusResponse{
+		ShortCode:      url.ShortCode,
+		Disabled:       url.IsDisabled(),
+		DisabledReason: url.DisabledReason,
+	}
+}
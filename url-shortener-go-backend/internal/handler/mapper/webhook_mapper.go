@@ -0,0 +1,62 @@
+package mapper
+
+import (
+	"time"
+
+	"url-shortener-go-backend/internal/handler/dto"
+	"url-shortener-go-backend/internal/model"
+)
+
+func ToWebhookResponse(hook model.Webhook, withSecret bool) dto.WebhookResponse {
+	resp := dto.WebhookResponse{
+		ID:          hook.ID,
+		URL:         hook.URL,
+		Description: hook.Description,
+		Events:      hook.Events,
+		Active:      hook.Active,
+		CreatedAt:   hook.CreatedAt.Format(time.RFC3339),
+	}
+	if resp.Events == nil {
+		resp.Events = []string{}
+	}
+	if withSecret {
+		resp.Secret = hook.Secret
+	}
+	return resp
+}
+
+func ToWebhooksResponse(hooks []model.Webhook) dto.WebhooksResponse {
+	resp := dto.WebhooksResponse{Webhooks: make([]dto.WebhookResponse, 0, len(hooks))}
+	for _, hook := range hooks {
+		resp.Webhooks = append(resp.Webhooks, ToWebhookResponse(hook, false))
+	}
+	return resp
+}
+
+func ToWebhookDeliveriesResponse(deliveries []model.WebhookDelivery, withPayload bool) dto.WebhookDeliveriesResponse {
+	resp := dto.WebhookDeliveriesResponse{Deliveries: make([]dto.WebhookDeliveryResponse, 0, len(deliveries))}
+	for _, d := range deliveries {
+		item := dto.WebhookDeliveryResponse{
+			ID:             d.ID,
+			EventID:        d.EventID,
+			EventType:      d.EventType,
+			Status:         d.Status,
+			Attempts:       d.Attempts,
+			ResponseStatus: d.ResponseStatus,
+			Error:          d.Error,
+			DurationMS:     d.DurationMS,
+			CreatedAt:      d.CreatedAt.Format(time.RFC3339),
+		}
+		if d.NextAttemptAt != nil && d.Status == model.DeliveryPending {
+			item.NextAttemptAt = d.NextAttemptAt.Format(time.RFC3339)
+		}
+		if d.DeliveredAt != nil {
+			item.DeliveredAt = d.DeliveredAt.Format(time.RFC3339)
+		}
+		if withPayload {
+			item.Payload = d.Payload
+		}
+		resp.Deliveries = append(resp.Deliveries, item)
+	}
+	return resp
+}
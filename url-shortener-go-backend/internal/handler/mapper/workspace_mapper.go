@@ -0,0 +1,43 @@
+package mapper
+
+import (
+	"time"
+
+	"url-shortener-go-backend/internal/handler/dto"
+	"url-shortener-go-backend/internal/model"
+)
+
+func ToWorkspaceResponse(w model.Workspace, role model.Role) dto.WorkspaceResponse {
+	return dto.WorkspaceResponse{
+		ID:          w.ID,
+		Name:        w.Name,
+		Personal:    w.IsPersonal(),
+		Role:        string(role),
+		DedupPolicy: w.EffectiveDedupPolicy(),
+		CreatedAt:   w.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func ToWorkspacesResponse(memberships []model.WorkspaceMembership) dto.WorkspacesResponse {
+	resp := dto.WorkspacesResponse{Workspaces: make([]dto.WorkspaceResponse, 0, len(memberships))}
+	for _, m := range memberships {
+		resp.Workspaces = append(resp.Workspaces, ToWorkspaceResponse(m.Workspace, m.Role))
+	}
+	return resp
+}
+
+func ToWorkspaceMemberResponse(m model.WorkspaceMember) dto.WorkspaceMemberResponse {
+	return dto.WorkspaceMemberResponse{
+		UserID:    m.UserID,
+		Role:      string(m.Role),
+		CreatedAt: m.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func ToWorkspaceMembersResponse(members []model.WorkspaceMember) dto.WorkspaceMembersResponse {
+	resp := dto.WorkspaceMembersResponse{Members: make([]dto.WorkspaceMemberResponse, 0, len(members))}
+	for _, m := range members {
+		resp.Members = append(resp.Members, ToWorkspaceMemberResponse(m))
+	}
+	return resp
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"bytes"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"url-shortener-go-backend/internal/homograph"
+	"url-shortener-go-backend/internal/model"
+)
+
+const pageLayout = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>{{template "title" .}}</title>
+<style>
+body{font-family:system-ui,-apple-system,sans-serif;background:#f6f7f9;color:#1f2933;margin:0;display:flex;min-height:100vh;align-items:center;justify-content:center}
+main{background:#fff;max-width:560px;width:100%;margin:16px;padding:32px;border-radius:12px;box-shadow:0 2px 12px rgba(0,0,0,.08)}
+h1{font-size:1.4rem;margin-top:0}
+p{line-height:1.5}
+.muted{color:#616e7c;font-size:.9rem}
+.dest{word-break:break-all;background:#f0f4f8;padding:12px;border-radius:8px;font-family:monospace}
+a.button{display:inline-block;margin-top:16px;padding:10px 18px;background:#2563eb;color:#fff;text-decoration:none;border-radius:8px}
+</style>
+</head>
+<body>
+<main>
+{{template "content" .}}
+</main>
+</body>
+</html>`
+
+var disabledPage = template.Must(template.Must(template.New("layout").Parse(pageLayout)).Parse(`
+{{define "title"}}Link disabled{{end}}
+{{define "content"}}
+<h1>This link has been disabled</h1>
+{{if .Legal}}<p>This link is unavailable for legal reasons.</p>{{else}}<p>This link was disabled after a review of reports about its destination.</p>{{end}}
+<p class="muted">Short link: {{.ShortCode}}</p>
+{{end}}`))
+
+var expiredPage = template.Must(template.Must(template.New("layout").Parse(pageLayout)).Parse(`
+{{define "title"}}Link expired{{end}}
+{{define "content"}}
+<h1>This link has expired</h1>
+<p>The owner set this link to stop working after a certain date.</p>
+<p class="muted">Short link: {{.ShortCode}}</p>
+{{end}}`))
+
+var previewPage = template.Must(template.Must(template.New("layout").Parse(pageLayout)).Parse(`
+{{define "title"}}Link preview{{end}}
+{{define "content"}}
+<h1>You are about to leave for</h1>
+<p class="dest">{{.Destination}}</p>
+{{if .Host}}<p class="muted">Domain: {{.Host}}</p>{{end}}
+<p class="muted">Created {{.CreatedAt}} &middot; {{.ClickCount}} click{{if ne .ClickCount 1}}s{{end}}</p>
+<a class="button" href="{{.ContinueURL}}" rel="noopener noreferrer">Continue</a>
+{{end}}`))
+
+type previewPageData struct {
+	Destination string
+	Host        string
+	CreatedAt   string
+	ClickCount  int
+	ContinueURL string
+}
+
+func newPreviewPageData(u *model.URL, destination string) previewPageData {
+	data := previewPageData{
+		Destination: destination,
+		CreatedAt:   u.CreatedAt.UTC().Format("January 2, 2006"),
+		ClickCount:  u.ClickCount,
+		ContinueURL: "/" + u.ShortCode + "?continue=1",
+	}
+	if parsed, err := url.Parse(destination); err == nil {
+		if uni, err := homograph.ToUnicode(parsed.Hostname()); err == nil && uni != parsed.Hostname() {
+			data.Host = uni + " (" + parsed.Hostname() + ")"
+		}
+	}
+	return data
+}
+
+type disabledPageData struct {
+	ShortCode string
+	Legal     bool
+}
+
+func renderPage(w http.ResponseWriter, status int, tmpl *template.Template, data any) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		slog.Error("failed to render page", "template", tmpl.Name(), "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if _, err := buf.WriteTo(w); err != nil {
+		slog.Error("failed to write page", "template", tmpl.Name(), "error", err)
+	}
+}
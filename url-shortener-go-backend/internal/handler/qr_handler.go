@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"url-shortener-go-backend/internal/qrcode"
+	"url-shortener-go-backend/internal/utils"
+)
+
+func (h *URLHandler) HandleGetQRCode() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		shortcode := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/urls/"), "/qr")
+		if !utils.IsValidLinkKey(shortcode) {
+			utils.RespondError(w, http.StatusBadRequest, "Invalid shortcode", "")
+			return
+		}
+
+		opts, err := parseQROptions(r)
+		if err != nil {
+			utils.RespondError(w, http.StatusBadRequest, err.Error(), "")
+			return
+		}
+
+		url, err := h.svc.GetURLByShortCode(r.Context(), shortcode)
+		if err != nil {
+			if errors.Is(err, utils.ErrNotFound) {
+				utils.RespondError(w, http.StatusNotFound, "URL not found", "")
+				return
+			}
+			utils.RespondError(w, http.StatusInternalServerError, "Could not fetch URL", "")
+			return
+		}
+		if url.IsDisabled() {
+			utils.RespondError(w, http.StatusGone, "URL has been disabled", "")
+			return
+		}
+		if url.IsExpired(time.Now()) {
+			utils.RespondError(w, http.StatusGone, "URL has expired", "")
+			return
+		}
+
+		etag := qrETag(url.ShortURL, opts)
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Cache-Control", "public, no-cache")
+		if match := r.Header.Get("If-None-Match"); match != "" && etagMatches(match, etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		body, err := qrcode.Render(url.ShortURL, opts)
+		if err != nil {
+			slog.Error("qr render failed", "shortcode", shortcode, "error", err)
+			utils.RespondError(w, http.StatusInternalServerError, "Could not render QR code", "")
+			return
+		}
+
+		w.Header().Set("Content-Type", qrcode.ContentType(opts.Format))
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodHead {
+			return
+		}
+		if _, err := w.Write(body); err != nil {
+			slog.Error("failed to write qr code", "shortcode", shortcode, "error", err)
+		}
+	}
+}
+
+func parseQROptions(r *http.Request) (qrcode.Options, error) {
+	q := r.URL.Query()
+	opts := qrcode.DefaultOptions()
+
+	if format := strings.ToLower(q.Get("format")); format != "" {
+		if format != qrcode.FormatPNG && format != qrcode.FormatSVG {
+			return opts, fmt.Errorf("format must be png or svg")
+		}
+		opts.Format = format
+	}
+
+	if size := q.Get("size"); size != "" {
+		n, err := strconv.Atoi(size)
+		if err != nil || n < qrcode.MinSize || n > qrcode.MaxSize {
+			return opts, fmt.Errorf("size must be between %d and %d", qrcode.MinSize, qrcode.MaxSize)
+		}
+		opts.Size = n
+	}
+
+	if margin := q.Get("margin"); margin != "" {
+		n, err := strconv.Atoi(margin)
+		if err != nil || n < 0 || n > qrcode.MaxMargin {
+			return opts, fmt.Errorf("margin must be between 0 and %d", qrcode.MaxMargin)
+		}
+		opts.Margin = n
+	}
+
+	if ecc := q.Get("ecc"); ecc != "" {
+		level, err := qrcode.ParseLevel(ecc)
+		if err != nil {
+			return opts, fmt.Errorf("ecc must be one of L, M, Q, H")
+		}
+		opts.Level = level
+	}
+
+	if fg := q.Get("fg"); fg != "" {
+		c, err := qrcode.ParseColor(fg)
+		if err != nil {
+			return opts, fmt.Errorf("fg must be a hex color")
+		}
+		opts.Foreground = c
+	}
+
+	if bg := q.Get("bg"); bg != "" {
+		c, err := qrcode.ParseColor(bg)
+		if err != nil {
+			return opts, fmt.Errorf("bg must be a hex color")
+		}
+		opts.Background = c
+	}
+
+	return opts, nil
+}
+
+func qrETag(shortURL string, opts qrcode.Options) string {
+	key := fmt.Sprintf("%s|%s|%d|%d|%d|%v|%v", shortURL, opts.Format, opts.Size, opts.Margin, opts.Level, opts.Foreground, opts.Background)
+	sum := sha256.Sum256([]byte(key))
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"url-shortener-go-backend/internal/handler/dto"
+	"url-shortener-go-backend/internal/handler/mapper"
+	"url-shortener-go-backend/internal/middleware"
+	"url-shortener-go-backend/internal/model"
+	"url-shortener-go-backend/internal/service"
+	"url-shortener-go-backend/internal/utils"
+)
+
+const (
+	MaxReportDetailsLength = 1000
+	MaxReportNoteLength    = 500
+	DefaultReportsLimit    = 50
+	MaxReportsLimit        = 200
+)
+
+type ReportHandler struct {
+	reportService service.ReportService
+	urlService    service.URLService
+}
+
+func NewReportHandler(reportService service.ReportService, urlService service.URLService) *ReportHandler {
+	return &ReportHandler{
+		reportService: reportService,
+		urlService:    urlService,
+	}
+}
+
+func (h *ReportHandler) HandleSubmitReport() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetRequestID(r.Context())
+
+		if r.Method != http.MethodPost {
+			utils.RespondError(w, http.StatusMethodNotAllowed, ErrMsgMethodNotAllowed, requestID)
+			return
+		}
+
+		shortcode := strings.TrimSpace(strings.TrimPrefix(r.URL.Path, "/api/reports/"))
+		if !utils.IsValidLinkKey(shortcode) {
+			utils.RespondError(w, http.StatusBadRequest, "Invalid shortcode", requestID)
+			return
+		}
+
+		var req dto.CreateReportRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			utils.RespondError(w, http.StatusBadRequest, ErrMsgInvalidRequest, requestID)
+			return
+		}
+
+		reason := strings.ToLower(strings.TrimSpace(req.Reason))
+		details := strings.TrimSpace(req.Details)
+		if len(details) > MaxReportDetailsLength {
+			details = details[:MaxReportDetailsLength]
+		}
+
+		var reporterID *string
+		if userID := middleware.GetUserIDFromContext(r.Context()); userID != "" {
+			reporterID = &userID
+		}
+
+		report, err := h.reportService.SubmitReport(r.Context(), shortcode, reason, details, middleware.ClientIP(r), reporterID)
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrInvalidReportReason):
+				utils.RespondError(w, http.StatusBadRequest, "reason must be one of spam, phishing, malware, illegal, other", requestID)
+			case errors.Is(err, utils.ErrNotFound):
+				utils.RespondError(w, http.StatusNotFound, "URL not found", requestID)
+			default:
+				slog.Error("submit report failed", "request_id", requestID, "shortcode", shortcode, "error", err)
+				utils.RespondError(w, http.StatusInternalServerError, "Unable to submit report", requestID)
+			}
+			return
+		}
+
+		utils.RespondJSON(w, http.StatusCreated, mapper.ToReportResponse(*report), requestID)
+	}
+}
+
+func (h *ReportHandler) HandleListReports() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetRequestID(r.Context())
+
+		if r.Method != http.MethodGet {
+			utils.RespondError(w, http.StatusMethodNotAllowed, ErrMsgMethodNotAllowed, requestID)
+			return
+		}
+
+		status := r.URL.Query().Get("status")
+		if status == "" {
+			status = model.ReportStatusOpen
+		}
+		if status == "all" {
+			status = ""
+		} else if status != model.ReportStatusOpen && status != model.ReportStatusActioned && status != model.ReportStatusDismissed {
+			utils.RespondError(w, http.StatusBadRequest, "Invalid status parameter", requestID)
+			return
+		}
+
+		limit := DefaultReportsLimit
+		if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+			n, err := strconv.Atoi(limitStr)
+			if err != nil || n < MinLimit || n > MaxReportsLimit {
+				utils.RespondError(w, http.StatusBadRequest, ErrMsgInvalidLimit, requestID)
+				return
+			}
+			limit = n
+		}
+
+		reports, err := h.reportService.ListReports(r.Context(), status, limit)
+		if err != nil {
+			utils.RespondError(w, http.StatusInternalServerError, "Unable to fetch reports", requestID)
+			return
+		}
+
+		utils.RespondJSON(w, http.StatusOK, mapper.ToReportsResponse(reports), requestID)
+	}
+}
+
+func (h *ReportHandler) HandleResolveReport() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetRequestID(r.Context())
+
+		if r.Method != http.MethodPost {
+			utils.RespondError(w, http.StatusMethodNotAllowed, ErrMsgMethodNotAllowed, requestID)
+			return
+		}
+
+		reportID := strings.TrimSpace(strings.TrimPrefix(r.URL.Path, "/api/admin/reports/"))
+		if reportID == "" || len(reportID) > MaxURLIDLength {
+			utils.RespondError(w, http.StatusBadRequest, "Invalid report identifier", requestID)
+			return
+		}
+
+		var req dto.ResolveReportRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			utils.RespondError(w, http.StatusBadRequest, ErrMsgInvalidRequest, requestID)
+			return
+		}
+
+		note := strings.TrimSpace(req.Note)
+		if len(note) > MaxReportNoteLength {
+			note = note[:MaxReportNoteLength]
+		}
+
+		adminID := middleware.GetUserIDFromContext(r.Context())
+		report, err := h.reportService.ResolveReport(r.Context(), reportID, adminID, strings.ToLower(strings.TrimSpace(req.Action)), note)
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrInvalidReportAction):
+				utils.RespondError(w, http.StatusBadRequest, "action must be disable or dismiss", requestID)
+			case errors.Is(err, service.ErrReportAlreadyResolved):
+				utils.RespondError(w, http.StatusConflict, "Report has already been resolved", requestID)
+			case errors.Is(err, utils.ErrNotFound):
+				utils.RespondError(w, http.StatusNotFound, "Report not found", requestID)
+			default:
+				slog.Error("resolve report failed", "request_id", requestID, "report_id", reportID, "error", err)
+				utils.RespondError(w, http.StatusInternalServerError, "Unable to resolve report", requestID)
+			}
+			return
+		}
+
+		utils.RespondJSON(w, http.StatusOK, mapper.ToReportResponse(*report), requestID)
+	}
+}
+
+func (h *ReportHandler) HandleSetURLStatus() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetRequestID(r.Context())
+
+		if r.Method != http.MethodPost {
+			utils.RespondError(w, http.StatusMethodNotAllowed, ErrMsgMethodNotAllowed, requestID)
+			return
+		}
+
+		shortcode := strings.TrimSpace(strings.TrimPrefix(r.URL.Path, "/api/admin/urls/"))
+		if !utils.IsValidLinkKey(shortcode) {
+			utils.RespondError(w, http.StatusBadRequest, "Invalid shortcode", requestID)
+			return
+		}
+
+		var req dto.SetURLStatusRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			utils.RespondError(w, http.StatusBadRequest, ErrMsgInvalidRequest, requestID)
+			return
+		}
+
+		reason := strings.ToLower(strings.TrimSpace(req.Reason))
+		if req.Disabled && !model.ReportReasons[reason] {
+			utils.RespondError(w, http.StatusBadRequest, "reason must be one of spam, phishing, malware, illegal, other", requestID)
+			return
+		}
+
+		url, err := h.urlService.SetURLDisabled(r.Context(), shortcode, reason, req.Disabled)
+		if err != nil {
+			if errors.Is(err, utils.ErrNotFound) {
+				utils.RespondError(w, http.StatusNotFound, "URL not found", requestID)
+				return
+			}
+			utils.RespondError(w, http.StatusInternalServerError, "Unable to update URL status", requestID)
+			return
+		}
+
+		slog.Info("url status changed by admin", "request_id", requestID, "shortcode", shortcode, "disabled", req.Disabled, "admin_id", truncateID(middleware.GetUserIDFromContext(r.Context())))
+		utils.RespondJSON(w, http.StatusOK, mapper.ToURLStatusResponse(*url), requestID)
+	}
+}
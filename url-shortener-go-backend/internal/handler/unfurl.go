@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"html/template"
+	"strings"
+
+	"url-shortener-go-backend/internal/model"
+)
+
+var unfurlBotAgents = []string{
+	"slackbot",
+	"twitterbot",
+	"facebookexternalhit",
+	"facebot",
+	"linkedinbot",
+	"discordbot",
+	"telegrambot",
+	"whatsapp",
+	"skypeuripreview",
+	"microsoft teams",
+	"pinterestbot",
+	"redditbot",
+	"embedly",
+	"iframely",
+	"mastodon",
+	"bluesky",
+	"vkshare",
+	"applebot",
+	"google-pagerenderer",
+}
+
+func isUnfurlBot(userAgent string) bool {
+	ua := strings.ToLower(userAgent)
+	if ua == "" {
+		return false
+	}
+	for _, bot := range unfurlBotAgents {
+		if strings.Contains(ua, bot) {
+			return true
+		}
+	}
+	return false
+}
+
+var unfurlPage = template.Must(template.New("unfurl").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<meta property="og:type" content="website">
+<meta property="og:url" content="{{.ShortURL}}">
+<meta property="og:title" content="{{.Title}}">
+{{if .Description}}<meta property="og:description" content="{{.Description}}">
+<meta name="description" content="{{.Description}}">{{end}}
+{{if .Image}}<meta property="og:image" content="{{.Image}}">
+<meta name="twitter:card" content="summary_large_image">
+<meta name="twitter:image" content="{{.Image}}">{{else}}<meta name="twitter:card" content="summary">{{end}}
+<meta name="twitter:title" content="{{.Title}}">
+{{if .Description}}<meta name="twitter:description" content="{{.Description}}">{{end}}
+<meta http-equiv="refresh" content="0;url={{.Destination}}">
+</head>
+<body><a href="{{.Destination}}">{{.Destination}}</a></body>
+</html>`))
+
+type unfurlPageData struct {
+	ShortURL    string
+	Destination string
+	Title       string
+	Description string
+	Image       string
+}
+
+func newUnfurlPageData(u *model.URL) unfurlPageData {
+	data := unfurlPageData{
+		ShortURL:    u.ShortURL,
+		Destination: u.OriginalURL,
+		Title:       firstNonEmpty(u.OGTitle, u.Title, u.OriginalURL),
+		Description: firstNonEmpty(u.OGDescription, u.Description),
+		Image:       firstNonEmpty(u.OGImage, u.ImageURL),
+	}
+	return data
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
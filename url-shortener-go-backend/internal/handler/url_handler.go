@@ -4,25 +4,57 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"url-shortener-go-backend/internal/handler/dto"
 	"url-shortener-go-backend/internal/handler/mapper"
 	"url-shortener-go-backend/internal/metrics"
 	"url-shortener-go-backend/internal/middleware"
+	"url-shortener-go-backend/internal/model"
+	"url-shortener-go-backend/internal/reputation"
 	"url-shortener-go-backend/internal/service"
+	"url-shortener-go-backend/internal/shortcode"
+	"url-shortener-go-backend/internal/targeting"
+	"url-shortener-go-backend/internal/urlnorm"
 	"url-shortener-go-backend/internal/utils"
 )
 
+const (
+	MaxOGTitleLength       = 200
+	MaxOGDescriptionLength = 500
+	MaxOGImageLength       = 2048
+	MaxUTMValueLength      = 200
+	VariantCookiePrefix    = "sv_"
+	VariantCookieTTL       = 30 * 24 * time.Hour
+)
+
 type URLHandler struct {
-	svc service.URLService
+	svc        service.URLService
+	analytics  service.AnalyticsService
+	domains    service.DomainService
+	workspaces service.WorkspaceService
+	validator  *middleware.URLValidator
+	detector   *targeting.Detector
+	events     service.LinkEvents
 }
 
-func NewURLHandler(svc service.URLService) *URLHandler {
-	return &URLHandler{svc: svc}
+func NewURLHandler(svc service.URLService, analytics service.AnalyticsService, domains service.DomainService, workspaces service.WorkspaceService, validator *middleware.URLValidator, detector *targeting.Detector, events service.LinkEvents) *URLHandler {
+	return &URLHandler{
+		svc:        svc,
+		analytics:  analytics,
+		domains:    domains,
+		workspaces: workspaces,
+		validator:  validator,
+		detector:   detector,
+		events:     events,
+	}
 }
 
 func (h *URLHandler) HandleShorten() http.HandlerFunc {
@@ -45,18 +77,219 @@ func (h *URLHandler) HandleShorten() http.HandlerFunc {
 			userIDPtr = &userID
 		}
 
-		urlModel, err := h.svc.CreateShortURL(ctx, strings.TrimSpace(req.OriginalURL), req.IsPublic, userIDPtr, int(req.CodeLength))
+		input := model.CreateURLInput{
+			OriginalURL: strings.TrimSpace(req.OriginalURL),
+			IsPublic:    req.IsPublic,
+			UserID:      userIDPtr,
+			CodeLength:  int(req.CodeLength),
+			CodeStyle:   strings.ToLower(strings.TrimSpace(req.CodeStyle)),
+			Alias:       strings.TrimSpace(req.Alias),
+			Dedup:       strings.ToLower(strings.TrimSpace(req.Dedup)),
+
+			RequirePreview: req.RequirePreview,
+			RedirectStatus: req.RedirectStatus,
+			CacheMaxAge:    req.CacheMaxAge,
+			NoStore:        req.NoStore,
+			ForwardQuery:   strings.TrimSpace(req.ForwardQuery),
+		}
+
+		if userID != "" {
+			access, err := h.workspaces.Resolve(ctx, userID, r.Header.Get(WorkspaceHeader))
+			if err == nil && !access.Can(model.RoleEditor) {
+				err = service.ErrWorkspaceForbidden
+			}
+			if err != nil {
+				status, message := workspaceErrorStatus(err)
+				if status == http.StatusInternalServerError {
+					slog.Error("workspace lookup failed", "error", err)
+					message = "Failed to shorten URL"
+				}
+				utils.RespondError(w, status, message, "")
+				return
+			}
+			input.WorkspaceID = &access.WorkspaceID
+
+			if input.Dedup == "" {
+				workspace, err := h.workspaces.GetWorkspace(ctx, *access)
+				if err != nil {
+					slog.Error("workspace settings lookup failed", "workspace_id", access.WorkspaceID, "error", err)
+					utils.RespondError(w, http.StatusInternalServerError, "Failed to shorten URL", "")
+					return
+				}
+				input.Dedup = workspace.EffectiveDedupPolicy()
+			}
+		}
+
+		if req.Domain != "" {
+			if userID == "" {
+				utils.RespondError(w, http.StatusUnauthorized, "Authentication required to use a custom domain", "")
+				return
+			}
+			domain, err := h.domains.GetVerifiedDomain(ctx, userID, req.Domain)
+			if err != nil {
+				switch {
+				case errors.Is(err, service.ErrInvalidDomain), errors.Is(err, utils.ErrNotFound):
+					utils.RespondError(w, http.StatusBadRequest, "Unknown domain", "")
+				case errors.Is(err, service.ErrDomainNotVerified):
+					utils.RespondError(w, http.StatusBadRequest, "Domain is not verified yet", "")
+				default:
+					slog.Error("domain lookup failed", "domain", req.Domain, "error", err)
+					utils.RespondError(w, http.StatusInternalServerError, "Failed to shorten URL", "")
+				}
+				return
+			}
+			input.Domain = domain.Hostname
+		}
+
+		if err := model.ValidateRedirectOptions(input.RedirectStatus, input.CacheMaxAge); err != nil {
+			utils.RespondError(w, http.StatusBadRequest, err.Error(), "")
+			return
+		}
+		if err := model.ValidateForwardQuery(input.ForwardQuery); err != nil {
+			utils.RespondError(w, http.StatusBadRequest, err.Error(), "")
+			return
+		}
+		if err := model.ValidateDedupPolicy(input.Dedup); err != nil {
+			utils.RespondError(w, http.StatusBadRequest, err.Error(), "")
+			return
+		}
+		if err := shortcode.ValidateStyle(input.CodeStyle); err != nil {
+			utils.RespondError(w, http.StatusBadRequest, err.Error(), "")
+			return
+		}
+
+		tags, err := model.NormalizeTags(req.Tags)
+		if err != nil {
+			utils.RespondError(w, http.StatusBadRequest, err.Error(), "")
+			return
+		}
+		folder, err := model.NormalizeFolder(req.Folder)
+		if err != nil {
+			utils.RespondError(w, http.StatusBadRequest, err.Error(), "")
+			return
+		}
+		if err := model.ValidateExpiry(req.ExpiresAt, time.Now()); err != nil {
+			utils.RespondError(w, http.StatusBadRequest, err.Error(), "")
+			return
+		}
+		input.Tags, input.Folder, input.ExpiresAt = tags, folder, req.ExpiresAt
+
+		rules, err := h.prepareRules(ctx, req.Rules)
 		if err != nil {
+			utils.RespondError(w, http.StatusBadRequest, err.Error(), "")
+			return
+		}
+		input.Rules = rules
+
+		variants, err := h.prepareVariants(ctx, req.Variants)
+		if err != nil {
+			utils.RespondError(w, http.StatusBadRequest, err.Error(), "")
+			return
+		}
+		input.Variants = variants
+
+		deepLinks, err := h.prepareDeepLinks(ctx, req.DeepLinks)
+		if err != nil {
+			utils.RespondError(w, http.StatusBadRequest, err.Error(), "")
+			return
+		}
+		input.DeepLinks = deepLinks
+
+		if req.UTM != nil {
+			params, err := utmValues(*req.UTM)
+			if err != nil {
+				utils.RespondError(w, http.StatusBadRequest, err.Error(), "")
+				return
+			}
+			merged, err := utils.MergeQuery(input.OriginalURL, params, true)
+			if err != nil {
+				utils.RespondError(w, http.StatusBadRequest, "Invalid or missing URL", "")
+				return
+			}
+			input.OriginalURL = merged
+		}
+
+		if err := h.validator.ValidateURLContext(ctx, input.OriginalURL); err != nil {
+			utils.RespondError(w, http.StatusBadRequest, err.Error(), "")
+			return
+		}
+
+		hostAnalysis, err := h.validator.AnalyzeHost(input.OriginalURL)
+		if err != nil {
+			utils.RespondError(w, http.StatusBadRequest, err.Error(), "")
+			return
+		}
+		if hostAnalysis.Suspicious() {
+			slog.Warn("possible homograph destination", "host", hostAnalysis.ASCII, "unicode", hostAnalysis.Unicode, "imitates", hostAnalysis.ImitatedBrand)
+		}
+
+		if h.validator.PreflightEnabled() {
+			result, err := h.validator.Preflight(ctx, input.OriginalURL)
+			if err != nil {
+				slog.Warn("destination preflight failed", "error", err)
+				utils.RespondError(w, http.StatusUnprocessableEntity, err.Error(), "")
+				return
+			}
+			input.ResolvedURL = result.FinalURL
+			input.ResolvedStatus = result.StatusCode
+		}
+
+		urlModel, reused, err := h.svc.CreateShortURL(ctx, input)
+		if err != nil {
+			if errors.Is(err, urlnorm.ErrInvalidURL) {
+				utils.RespondError(w, http.StatusBadRequest, "Invalid or missing URL", "")
+				return
+			}
+			if errors.Is(err, shortcode.ErrInvalidLength) || errors.Is(err, shortcode.ErrInvalidAlias) {
+				utils.RespondError(w, http.StatusBadRequest, err.Error(), "")
+				return
+			}
+			if errors.Is(err, shortcode.ErrReservedCode) || errors.Is(err, shortcode.ErrOffensiveCode) {
+				utils.RespondError(w, http.StatusBadRequest, "Alias is not allowed", "")
+				return
+			}
+			if errors.Is(err, service.ErrAliasTaken) {
+				utils.RespondError(w, http.StatusConflict, "Alias is already taken", "")
+				return
+			}
+			if errors.Is(err, shortcode.ErrUnavailable) || errors.Is(err, shortcode.ErrExhausted) {
+				slog.Error("short code allocation failed", "error", err)
+				utils.RespondError(w, http.StatusServiceUnavailable, "Could not allocate a short code, please try again later", "")
+				return
+			}
+			if errors.Is(err, service.ErrUnsafeURL) {
+				utils.RespondError(w, http.StatusUnprocessableEntity, "URL failed reputation check", "")
+				return
+			}
+			if errors.Is(err, reputation.ErrUnavailable) {
+				utils.RespondError(w, http.StatusServiceUnavailable, "URL reputation check unavailable, please try again later", "")
+				return
+			}
 			slog.Error("create short url failed", "error", err)
 			utils.RespondError(w, http.StatusInternalServerError, "Failed to shorten URL", "")
 			return
 		}
 
-		utils.RespondJSON(w, http.StatusCreated, mapper.ToShortenURLResponse(*urlModel), "")
+		resp := mapper.ToShortenURLResponse(*urlModel)
+		resp.Host = mapper.ToHostForms(hostAnalysis)
+		if reused {
+			resp.Reused = true
+			utils.RespondJSON(w, http.StatusOK, resp, "")
+			return
+		}
+		utils.RespondJSON(w, http.StatusCreated, resp, "")
 	}
 }
 
 func (h *URLHandler) HandleGetUserUrls() http.HandlerFunc {
+	return h.listURLs("")
+}
+
+func (h *URLHandler) HandleBrokenURLs() http.HandlerFunc {
+	return h.listURLs(model.HealthBroken)
+}
+
+func (h *URLHandler) listURLs(health string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
 			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
@@ -69,9 +302,33 @@ func (h *URLHandler) HandleGetUserUrls() http.HandlerFunc {
 			return
 		}
 
-		urls, err := h.svc.GetUserUrls(r.Context(), userID)
+		access, err := h.workspaces.Resolve(r.Context(), userID, r.Header.Get(WorkspaceHeader))
+		if err != nil {
+			status, message := workspaceErrorStatus(err)
+			if status == http.StatusInternalServerError {
+				slog.Error("workspace lookup failed", "user_id", userID, "error", err)
+				message = "Could not fetch URLs"
+			}
+			utils.RespondError(w, status, message, "")
+			return
+		}
+
+		query, err := parseURLQuery(r.URL.Query())
 		if err != nil {
-			slog.Error("get user urls failed", "user_id", userID, "error", err)
+			utils.RespondError(w, http.StatusBadRequest, err.Error(), "")
+			return
+		}
+		if health != "" {
+			query.Health = health
+		}
+
+		urls, err := h.svc.GetWorkspaceURLs(r.Context(), access.WorkspaceID, query)
+		if errors.Is(err, model.ErrInvalidCursor) {
+			utils.RespondError(w, http.StatusBadRequest, "Invalid cursor", "")
+			return
+		}
+		if err != nil {
+			slog.Error("get workspace urls failed", "workspace_id", access.WorkspaceID, "error", err)
 			utils.RespondError(w, http.StatusInternalServerError, "Could not fetch URLs", "")
 			return
 		}
@@ -111,18 +368,253 @@ func (h *URLHandler) HandleGetUrlByShortCode() http.HandlerFunc {
 	}
 }
 
-func (h *URLHandler) ShortCodeHandler() http.HandlerFunc {
+func (h *URLHandler) HandleUpdateURL() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		shortcode := strings.Trim(r.URL.Path, "/")
+		if r.Method != http.MethodPatch {
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID := middleware.GetUserIDFromContext(r.Context())
+		if userID == "" {
+			utils.RespondError(w, http.StatusUnauthorized, "Unauthorized", "")
+			return
+		}
+
+		shortcode := strings.TrimSpace(strings.TrimPrefix(r.URL.Path, "/api/urls/"))
+		if !utils.IsValidLinkKey(shortcode) {
+			utils.RespondError(w, http.StatusBadRequest, "Invalid shortcode", "")
+			return
+		}
+
+		var req dto.UpdateURLRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			utils.RespondError(w, http.StatusBadRequest, "Invalid request body", "")
+			return
+		}
+
+		update := model.URLUpdate{
+			RequirePreview: req.RequirePreview,
+			OGTitle:        trimmed(req.OGTitle),
+			OGDescription:  trimmed(req.OGDescription),
+			OGImage:        trimmed(req.OGImage),
+			RedirectStatus: req.RedirectStatus,
+			CacheMaxAge:    req.CacheMaxAge,
+			NoStore:        req.NoStore,
+			ForwardQuery:   trimmed(req.ForwardQuery),
+		}
+
+		if err := model.ValidateRedirectOptions(deref(update.RedirectStatus), deref(update.CacheMaxAge)); err != nil {
+			utils.RespondError(w, http.StatusBadRequest, err.Error(), "")
+			return
+		}
+		if err := model.ValidateForwardQuery(deref(update.ForwardQuery)); err != nil {
+			utils.RespondError(w, http.StatusBadRequest, err.Error(), "")
+			return
+		}
+		if req.Tags != nil {
+			tags, err := model.NormalizeTags(*req.Tags)
+			if err != nil {
+				utils.RespondError(w, http.StatusBadRequest, err.Error(), "")
+				return
+			}
+			update.Tags = &tags
+		}
+		if req.Folder != nil {
+			folder, err := model.NormalizeFolder(*req.Folder)
+			if err != nil {
+				utils.RespondError(w, http.StatusBadRequest, err.Error(), "")
+				return
+			}
+			update.Folder = &folder
+		}
+		if expiresAt := trimmed(req.ExpiresAt); expiresAt != nil {
+			if *expiresAt == "" {
+				update.ClearExpiry = true
+			} else {
+				t, err := time.Parse(time.RFC3339, *expiresAt)
+				if err == nil {
+					err = model.ValidateExpiry(&t, time.Now())
+				} else {
+					err = fmt.Errorf("expires_at must be an RFC 3339 timestamp")
+				}
+				if err != nil {
+					utils.RespondError(w, http.StatusBadRequest, err.Error(), "")
+					return
+				}
+				update.ExpiresAt = &t
+			}
+		}
+		if req.Rules != nil {
+			rules, err := h.prepareRules(r.Context(), *req.Rules)
+			if err != nil {
+				utils.RespondError(w, http.StatusBadRequest, err.Error(), "")
+				return
+			}
+			update.Rules = &rules
+		}
+		if req.Variants != nil {
+			variants, err := h.prepareVariants(r.Context(), *req.Variants)
+			if err != nil {
+				utils.RespondError(w, http.StatusBadRequest, err.Error(), "")
+				return
+			}
+			update.Variants = &variants
+		}
+		if req.DeepLinks != nil {
+			deepLinks, err := h.prepareDeepLinks(r.Context(), req.DeepLinks)
+			if err != nil {
+				utils.RespondError(w, http.StatusBadRequest, err.Error(), "")
+				return
+			}
+			if deepLinks == nil {
+				deepLinks = &model.DeepLinks{}
+			}
+			update.DeepLinks = deepLinks
+		}
+
+		if err := validateOGOverrides(update); err != nil {
+			utils.RespondError(w, http.StatusBadRequest, err.Error(), "")
+			return
+		}
+
+		var url *model.URL
+		access, err := h.linkAccess(r.Context(), shortcode, userID, model.RoleEditor)
+		if err == nil {
+			url, err = h.svc.UpdateURL(r.Context(), shortcode, *access, update)
+		}
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrEmptyUpdate):
+				utils.RespondError(w, http.StatusBadRequest, "No fields to update", "")
+			case errors.Is(err, utils.ErrNotFound), errors.Is(err, service.ErrNotURLOwner):
+				utils.RespondError(w, http.StatusNotFound, "URL not found", "")
+			case errors.Is(err, service.ErrWorkspaceForbidden):
+				utils.RespondError(w, http.StatusForbidden, "Your workspace role does not allow editing this URL", "")
+			default:
+				slog.Error("update url failed", "shortcode", shortcode, "error", err)
+				utils.RespondError(w, http.StatusInternalServerError, "Could not update URL", "")
+			}
+			return
+		}
+
+		utils.RespondJSON(w, http.StatusOK, mapper.ToShortenURLResponse(*url), "")
+	}
+}
+
+func (h *URLHandler) HandleDeleteURL() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID := middleware.GetUserIDFromContext(r.Context())
+		if userID == "" {
+			utils.RespondError(w, http.StatusUnauthorized, "Unauthorized", "")
+			return
+		}
+
+		shortcode := strings.TrimSpace(strings.TrimPrefix(r.URL.Path, "/api/urls/"))
+		if !utils.IsValidLinkKey(shortcode) {
+			utils.RespondError(w, http.StatusBadRequest, "Invalid shortcode", "")
+			return
+		}
+
+		access, err := h.linkAccess(r.Context(), shortcode, userID, model.RoleEditor)
+		if err == nil {
+			err = h.svc.DeleteURL(r.Context(), shortcode, *access)
+		}
+		if err != nil {
+			switch {
+			case errors.Is(err, utils.ErrNotFound), errors.Is(err, service.ErrNotURLOwner):
+				utils.RespondError(w, http.StatusNotFound, "URL not found", "")
+			case errors.Is(err, service.ErrWorkspaceForbidden):
+				utils.RespondError(w, http.StatusForbidden, "Your workspace role does not allow deleting this URL", "")
+			default:
+				slog.Error("delete url failed", "shortcode", shortcode, "error", err)
+				utils.RespondError(w, http.StatusInternalServerError, "Could not delete URL", "")
+			}
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (h *URLHandler) HandleRefreshMetadata() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID := middleware.GetUserIDFromContext(r.Context())
+		if userID == "" {
+			utils.RespondError(w, http.StatusUnauthorized, "Unauthorized", "")
+			return
+		}
+
+		shortcode := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/urls/"), "/metadata")
+		if !utils.IsValidLinkKey(shortcode) {
+			utils.RespondError(w, http.StatusBadRequest, "Invalid shortcode", "")
+			return
+		}
+
+		var url *model.URL
+		access, err := h.linkAccess(r.Context(), shortcode, userID, model.RoleEditor)
+		if err == nil {
+			url, err = h.svc.RefreshMetadata(r.Context(), shortcode, *access)
+		}
+		if err != nil {
+			switch {
+			case errors.Is(err, utils.ErrNotFound), errors.Is(err, service.ErrNotURLOwner):
+				utils.RespondError(w, http.StatusNotFound, "URL not found", "")
+			case errors.Is(err, service.ErrWorkspaceForbidden):
+				utils.RespondError(w, http.StatusForbidden, "Your workspace role does not allow editing this URL", "")
+			case errors.Is(err, service.ErrMetadataDisabled):
+				utils.RespondError(w, http.StatusServiceUnavailable, "Metadata fetching is disabled", "")
+			case errors.Is(err, service.ErrMetadataFetch):
+				utils.RespondError(w, http.StatusBadGateway, "Could not fetch destination metadata", "")
+			default:
+				slog.Error("refresh metadata failed", "shortcode", shortcode, "error", err)
+				utils.RespondError(w, http.StatusInternalServerError, "Could not refresh metadata", "")
+			}
+			return
+		}
+
+		utils.RespondJSON(w, http.StatusOK, mapper.ToShortenURLResponse(*url), "")
+	}
+}
+
+func (h *URLHandler) linkAccess(ctx context.Context, shortcode, userID string, required model.Role) (*model.WorkspaceAccess, error) {
+	url, err := h.svc.GetURLByShortCode(ctx, shortcode)
+	if err != nil {
+		return nil, err
+	}
+	if url.WorkspaceID == nil {
+		return nil, service.ErrNotURLOwner
+	}
+
+	access, err := h.workspaces.Authorize(ctx, userID, *url.WorkspaceID, required)
+	if errors.Is(err, service.ErrNotWorkspaceMember) || errors.Is(err, service.ErrInvalidWorkspace) {
+		return nil, service.ErrNotURLOwner
+	}
+	return access, err
+}
+
+func (h *URLHandler) ShortCodeHandler(domain string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		shortcode := strings.TrimSuffix(strings.Trim(r.URL.Path, "/"), "+")
 		if utils.IsValidShortCode(shortcode) {
-			h.HandleRedirect()(w, r)
+			h.HandleRedirect(domain)(w, r)
 			return
 		}
 		http.NotFound(w, r)
 	}
 }
 
-func (h *URLHandler) HandleRedirect() http.HandlerFunc {
+func (h *URLHandler) HandleRedirect(domain string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet && r.Method != http.MethodHead {
 			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
@@ -130,13 +622,17 @@ func (h *URLHandler) HandleRedirect() http.HandlerFunc {
 		}
 
 		shortcode := strings.Trim(r.URL.Path, "/")
+		query := r.URL.Query()
+		preview := strings.HasSuffix(shortcode, "+") || query.Get("preview") == "1"
+		shortcode = strings.TrimSuffix(shortcode, "+")
 		if shortcode == "" {
 			utils.RespondError(w, http.StatusBadRequest, "Missing shortcode", "")
 			return
 		}
 
 		ctx := r.Context()
-		urlEntry, err := h.svc.GetURLByShortCode(ctx, shortcode)
+		key := model.LinkKey(domain, shortcode)
+		urlEntry, err := h.svc.GetURLByShortCode(ctx, key)
 		if err != nil {
 			if errors.Is(err, utils.ErrNotFound) {
 				utils.RespondError(w, http.StatusNotFound, "URL not found", "")
@@ -150,16 +646,312 @@ func (h *URLHandler) HandleRedirect() http.HandlerFunc {
 			return
 		}
 
+		if urlEntry.IsDisabled() {
+			legal := urlEntry.DisabledReason == model.ReportReasonIllegal
+			status := http.StatusGone
+			if legal {
+				status = http.StatusUnavailableForLegalReasons
+			}
+			renderPage(w, status, disabledPage, disabledPageData{ShortCode: urlEntry.ShortCode, Legal: legal})
+			return
+		}
+
+		if urlEntry.IsExpired(time.Now()) {
+			renderPage(w, http.StatusGone, expiredPage, disabledPageData{ShortCode: urlEntry.ShortCode})
+			return
+		}
+
+		w.Header().Add("Vary", "User-Agent")
+		if urlEntry.HasOGOverrides() && isUnfurlBot(r.UserAgent()) {
+			metrics.URLUnfurlsTotal.Inc()
+			renderPage(w, http.StatusOK, unfurlPage, newUnfurlPageData(urlEntry))
+			return
+		}
+
+		client := h.detector.Detect(r)
+		destination := urlEntry.OriginalURL
+		matchedRule, variant := "", ""
+		if idx, rule := targeting.Match(urlEntry.Rules, client); rule != nil {
+			destination = rule.Destination
+			matchedRule = rule.Label(idx)
+		} else if v := h.assignVariant(w, r, urlEntry); v != nil {
+			destination = v.Destination
+			variant = v.Name
+		}
+
+		if preview || (urlEntry.RequirePreview && query.Get("continue") != "1") {
+			renderPage(w, http.StatusOK, previewPage, newPreviewPageData(urlEntry, destination))
+			return
+		}
+
 		go func() {
 			bgCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 			defer cancel()
-			if err := h.svc.IncrementClickCount(bgCtx, shortcode); err != nil {
-				slog.Error("click count increment failed", "shortcode", shortcode, "error", err)
+			if err := h.svc.IncrementClickCount(bgCtx, key); err != nil {
+				slog.Error("click count increment failed", "shortcode", key, "error", err)
 			}
 		}()
 
+		if cacheControl := urlEntry.CacheControl(); cacheControl != "" {
+			w.Header().Set("Cache-Control", cacheControl)
+		}
+
+		event := model.ClickEvent{
+			URLID:       urlEntry.ID,
+			Referrer:    r.Referer(),
+			DeviceType:  client.DeviceType,
+			OS:          client.OS,
+			Country:     client.Country,
+			MatchedRule: matchedRule,
+			Variant:     variant,
+		}
+		if urlEntry.UserID != nil {
+			event.UserID = *urlEntry.UserID
+		}
+		if urlEntry.WorkspaceID != nil {
+			event.WorkspaceID = *urlEntry.WorkspaceID
+		}
+		_ = h.analytics.RecordClick(ctx, event)
+		if h.events != nil {
+			h.events.LinkClicked(urlEntry)
+		}
+
+		if urlEntry.ForwardQuery != model.ForwardQueryOff {
+			destination = forwardQuery(destination, query, urlEntry.ForwardQuery == model.ForwardQueryOverride)
+		}
+
+		if target := urlEntry.DeepLinks.For(client.OS); target != nil && matchedRule == "" {
+			metrics.URLRedirectsTotal.Inc()
+			h.redirectToApp(w, r, client.OS, *target, destination, urlEntry.EffectiveRedirectStatus())
+			return
+		}
+
 		metrics.URLRedirectsTotal.Inc()
-		http.Redirect(w, r, urlEntry.OriginalURL, http.StatusFound)
+		http.Redirect(w, r, destination, urlEntry.EffectiveRedirectStatus())
+	}
+}
+
+func (h *URLHandler) prepareRules(ctx context.Context, in []dto.RedirectRule) ([]model.RedirectRule, error) {
+	rules, err := model.NormalizeRules(mapper.FromRedirectRules(in))
+	if err != nil {
+		return nil, err
+	}
+	for i, rule := range rules {
+		if err := h.validator.ValidateURLContext(ctx, rule.Destination); err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i+1, err)
+		}
+	}
+	return rules, nil
+}
+
+func (h *URLHandler) prepareVariants(ctx context.Context, in []dto.Variant) ([]model.Variant, error) {
+	variants, err := model.NormalizeVariants(mapper.FromVariants(in))
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range variants {
+		if err := h.validator.ValidateURLContext(ctx, v.Destination); err != nil {
+			return nil, fmt.Errorf("variant %q: %w", v.Name, err)
+		}
+	}
+	return variants, nil
+}
+
+func (h *URLHandler) prepareDeepLinks(ctx context.Context, in *dto.DeepLinks) (*model.DeepLinks, error) {
+	deepLinks, err := model.NormalizeDeepLinks(mapper.FromDeepLinks(in))
+	if err != nil {
+		return nil, err
+	}
+	for _, destination := range deepLinks.WebURLs() {
+		if err := h.validator.ValidateURLContext(ctx, destination); err != nil {
+			return nil, fmt.Errorf("deep_links: %w", err)
+		}
+	}
+	return deepLinks, nil
+}
+
+func (h *URLHandler) assignVariant(w http.ResponseWriter, r *http.Request, urlEntry *model.URL) *model.Variant {
+	if len(urlEntry.Variants) == 0 {
+		return nil
+	}
+
+	cookieName := VariantCookiePrefix + urlEntry.ShortCode
+	idx := -1
+	if c, err := r.Cookie(cookieName); err == nil {
+		idx = targeting.VariantByName(urlEntry.Variants, c.Value)
+	}
+	if idx < 0 {
+		idx = targeting.PickVariant(urlEntry.Variants, middleware.ClientIP(r)+"|"+r.UserAgent()+"|"+urlEntry.ShortCode)
+	}
+	if idx < 0 {
+		return nil
+	}
+
+	v := &urlEntry.Variants[idx]
+	http.SetCookie(w, &http.Cookie{
+		Name:     cookieName,
+		Value:    v.Name,
+		Path:     "/" + urlEntry.ShortCode,
+		MaxAge:   int(VariantCookieTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return v
+}
+
+func utmValues(utm dto.UTMParams) (url.Values, error) {
+	fields := []struct{ key, value string }{
+		{"utm_source", utm.Source},
+		{"utm_medium", utm.Medium},
+		{"utm_campaign", utm.Campaign},
+		{"utm_term", utm.Term},
+		{"utm_content", utm.Content},
+	}
+
+	params := url.Values{}
+	for _, f := range fields {
+		v := strings.TrimSpace(f.value)
+		if v == "" {
+			continue
+		}
+		if utf8.RuneCountInString(v) > MaxUTMValueLength {
+			return nil, fmt.Errorf("%s must be at most %d characters", f.key, MaxUTMValueLength)
+		}
+		params.Set(f.key, v)
+	}
+	return params, nil
+}
+
+func forwardQuery(destination string, query url.Values, override bool) string {
+	forwarded := url.Values{}
+	for key, values := range query {
+		if !model.ReservedQueryParams[key] {
+			forwarded[key] = values
+		}
+	}
+
+	merged, err := utils.MergeQuery(destination, forwarded, override)
+	if err != nil {
+		slog.Warn("query forwarding failed", "error", err)
+		return destination
 	}
+	return merged
 }
 
+func parseURLQuery(values url.Values) (model.URLQuery, error) {
+	q := model.URLQuery{
+		Search:     model.NormalizeSearch(values.Get("q")),
+		Visibility: strings.ToLower(strings.TrimSpace(values.Get("visibility"))),
+		Health:     strings.ToLower(strings.TrimSpace(values.Get("health"))),
+		Sort:       strings.ToLower(strings.TrimSpace(values.Get("sort"))),
+	}
+
+	var rawTags []string
+	for _, v := range values["tag"] {
+		rawTags = append(rawTags, strings.Split(v, ",")...)
+	}
+	tags, err := model.NormalizeTags(rawTags)
+	if err != nil {
+		return q, err
+	}
+	q.Tags = tags
+
+	if q.Folder, err = model.NormalizeFolder(values.Get("folder")); err != nil {
+		return q, err
+	}
+
+	if q.CreatedFrom, err = parseTimeParam(values.Get("created_from"), false); err != nil {
+		return q, fmt.Errorf("created_from %w", err)
+	}
+	if q.CreatedTo, err = parseTimeParam(values.Get("created_to"), true); err != nil {
+		return q, fmt.Errorf("created_to %w", err)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(values.Get("expired"))) {
+	case "":
+	case "true", "1":
+		expired := true
+		q.Expired = &expired
+	case "false", "0":
+		expired := false
+		q.Expired = &expired
+	default:
+		return q, fmt.Errorf("expired must be true or false")
+	}
+
+	switch strings.ToLower(strings.TrimSpace(values.Get("order"))) {
+	case "", "desc":
+	case "asc":
+		q.Ascending = true
+	default:
+		return q, fmt.Errorf("order must be asc or desc")
+	}
+
+	if limitStr := values.Get("limit"); limitStr != "" {
+		n, err := strconv.Atoi(limitStr)
+		if err != nil || n < MinLimit {
+			return q, fmt.Errorf("limit must be between %d and %d", MinLimit, model.MaxURLListLimit)
+		}
+		q.Limit = n
+	}
+
+	if q.Cursor, err = model.DecodePageCursor(values.Get("cursor")); err != nil {
+		return q, fmt.Errorf("invalid cursor")
+	}
+
+	return q, q.Validate()
+}
+
+func parseTimeParam(raw string, endOfDay bool) (*time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse(time.DateOnly, raw)
+	if err != nil {
+		return nil, fmt.Errorf("must be a date (YYYY-MM-DD) or an RFC 3339 timestamp")
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Second)
+	}
+	return &t, nil
+}
+
+func deref[T any](p *T) T {
+	var zero T
+	if p == nil {
+		return zero
+	}
+	return *p
+}
+
+func trimmed(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	v := strings.TrimSpace(*s)
+	return &v
+}
+
+func validateOGOverrides(update model.URLUpdate) error {
+	if update.OGTitle != nil && utf8.RuneCountInString(*update.OGTitle) > MaxOGTitleLength {
+		return fmt.Errorf("og_title must be at most %d characters", MaxOGTitleLength)
+	}
+	if update.OGDescription != nil && utf8.RuneCountInString(*update.OGDescription) > MaxOGDescriptionLength {
+		return fmt.Errorf("og_description must be at most %d characters", MaxOGDescriptionLength)
+	}
+	if update.OGImage != nil && *update.OGImage != "" {
+		if len(*update.OGImage) > MaxOGImageLength {
+			return fmt.Errorf("og_image must be at most %d characters", MaxOGImageLength)
+		}
+		parsed, err := url.Parse(*update.OGImage)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("og_image must be an absolute http(s) URL")
+		}
+	}
+	return nil
+}
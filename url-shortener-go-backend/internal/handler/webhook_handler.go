@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"url-shortener-go-backend/internal/handler/dto"
+	"url-shortener-go-backend/internal/handler/mapper"
+	"url-shortener-go-backend/internal/middleware"
+	"url-shortener-go-backend/internal/model"
+	"url-shortener-go-backend/internal/service"
+	"url-shortener-go-backend/internal/utils"
+	"url-shortener-go-backend/internal/webhook"
+)
+
+type WebhookHandler struct {
+	webhookService   service.WebhookService
+	workspaceService service.WorkspaceService
+}
+
+func NewWebhookHandler(webhookService service.WebhookService, workspaceService service.WorkspaceService) *WebhookHandler {
+	return &WebhookHandler{webhookService: webhookService, workspaceService: workspaceService}
+}
+
+func (h *WebhookHandler) HandleWebhooks() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetRequestID(r.Context())
+		userID := middleware.GetUserIDFromContext(r.Context())
+		if userID == "" {
+			utils.RespondError(w, http.StatusUnauthorized, ErrMsgUnauthorized, requestID)
+			return
+		}
+
+		access, err := h.workspaceService.Resolve(r.Context(), userID, r.Header.Get(WorkspaceHeader))
+		if err != nil {
+			respondWorkspaceError(w, requestID, err)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			hooks, err := h.webhookService.ListWebhooks(r.Context(), *access)
+			if err != nil {
+				respondWebhookError(w, requestID, err)
+				return
+			}
+			utils.RespondJSON(w, http.StatusOK, mapper.ToWebhooksResponse(hooks), requestID)
+		case http.MethodPost:
+			var req dto.CreateWebhookRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				utils.RespondError(w, http.StatusBadRequest, ErrMsgInvalidRequest, requestID)
+				return
+			}
+			events, err := model.NormalizeWebhookEvents(req.Events)
+			if err != nil {
+				utils.RespondError(w, http.StatusBadRequest, err.Error(), requestID)
+				return
+			}
+			description := strings.TrimSpace(req.Description)
+			if utf8.RuneCountInString(description) > model.MaxWebhookDescription {
+				utils.RespondError(w, http.StatusBadRequest, "Description is too long", requestID)
+				return
+			}
+			hook, err := h.webhookService.CreateWebhook(r.Context(), *access, model.WebhookInput{
+				URL:         req.URL,
+				Description: description,
+				Events:      events,
+			})
+			if err != nil {
+				respondWebhookError(w, requestID, err)
+				return
+			}
+			utils.RespondJSON(w, http.StatusCreated, mapper.ToWebhookResponse(*hook, true), requestID)
+		default:
+			utils.RespondError(w, http.StatusMethodNotAllowed, ErrMsgMethodNotAllowed, requestID)
+		}
+	}
+}
+
+func (h *WebhookHandler) HandleWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetRequestID(r.Context())
+		userID := middleware.GetUserIDFromContext(r.Context())
+		if userID == "" {
+			utils.RespondError(w, http.StatusUnauthorized, ErrMsgUnauthorized, requestID)
+			return
+		}
+
+		path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/webhooks/"), "/")
+		id, action, _ := strings.Cut(path, "/")
+		if !utils.IsValidUUID(id) {
+			utils.RespondError(w, http.StatusBadRequest, "Invalid webhook id", requestID)
+			return
+		}
+
+		switch {
+		case action == "" && (r.Method == http.MethodGet || r.Method == http.MethodPatch || r.Method == http.MethodDelete):
+		case action == "deliveries" && r.Method == http.MethodGet:
+		case action == "rotate-secret" && r.Method == http.MethodPost:
+		case action == "" || action == "deliveries" || action == "rotate-secret":
+			utils.RespondError(w, http.StatusMethodNotAllowed, ErrMsgMethodNotAllowed, requestID)
+			return
+		default:
+			http.NotFound(w, r)
+			return
+		}
+
+		access, err := h.workspaceService.Resolve(r.Context(), userID, r.Header.Get(WorkspaceHeader))
+		if err != nil {
+			respondWorkspaceError(w, requestID, err)
+			return
+		}
+
+		switch {
+		case action == "deliveries":
+			query := r.URL.Query()
+			status := strings.ToLower(query.Get("status"))
+			if err := model.ValidateDeliveryStatus(status); err != nil {
+				utils.RespondError(w, http.StatusBadRequest, err.Error(), requestID)
+				return
+			}
+			limit := service.DefaultDeliveryPageSize
+			if raw := query.Get("limit"); raw != "" {
+				n, err := strconv.Atoi(raw)
+				if err != nil || n < 1 || n > service.MaxDeliveryPageSize {
+					utils.RespondError(w, http.StatusBadRequest, ErrMsgInvalidLimit, requestID)
+					return
+				}
+				limit = n
+			}
+			deliveries, err := h.webhookService.ListDeliveries(r.Context(), *access, id, status, limit)
+			if err != nil {
+				respondWebhookError(w, requestID, err)
+				return
+			}
+			utils.RespondJSON(w, http.StatusOK, mapper.ToWebhookDeliveriesResponse(deliveries, query.Get("payload") == "1"), requestID)
+		case action == "rotate-secret":
+			hook, err := h.webhookService.RotateSecret(r.Context(), *access, id)
+			if err != nil {
+				respondWebhookError(w, requestID, err)
+				return
+			}
+			utils.RespondJSON(w, http.StatusOK, mapper.ToWebhookResponse(*hook, true), requestID)
+		case r.Method == http.MethodGet:
+			hook, err := h.webhookService.GetWebhook(r.Context(), *access, id)
+			if err != nil {
+				respondWebhookError(w, requestID, err)
+				return
+			}
+			utils.RespondJSON(w, http.StatusOK, mapper.ToWebhookResponse(*hook, false), requestID)
+		case r.Method == http.MethodPatch:
+			var req dto.UpdateWebhookRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				utils.RespondError(w, http.StatusBadRequest, ErrMsgInvalidRequest, requestID)
+				return
+			}
+			update := model.WebhookUpdate{URL: req.URL, Active: req.Active}
+			if req.Events != nil {
+				events, err := model.NormalizeWebhookEvents(*req.Events)
+				if err != nil {
+					utils.RespondError(w, http.StatusBadRequest, err.Error(), requestID)
+					return
+				}
+				update.Events = &events
+			}
+			if req.Description != nil {
+				description := strings.TrimSpace(*req.Description)
+				if utf8.RuneCountInString(description) > model.MaxWebhookDescription {
+					utils.RespondError(w, http.StatusBadRequest, "Description is too long", requestID)
+					return
+				}
+				update.Description = &description
+			}
+			hook, err := h.webhookService.UpdateWebhook(r.Context(), *access, id, update)
+			if err != nil {
+				respondWebhookError(w, requestID, err)
+				return
+			}
+			utils.RespondJSON(w, http.StatusOK, mapper.ToWebhookResponse(*hook, false), requestID)
+		case r.Method == http.MethodDelete:
+			if err := h.webhookService.DeleteWebhook(r.Context(), *access, id); err != nil {
+				respondWebhookError(w, requestID, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}
+}
+
+func respondWebhookError(w http.ResponseWriter, requestID string, err error) {
+	switch {
+	case errors.Is(err, webhook.ErrInvalidEndpoint):
+		utils.RespondError(w, http.StatusBadRequest, "Webhook URL must be an absolute http(s) URL without credentials", requestID)
+	case errors.Is(err, service.ErrWebhookLimit):
+		utils.RespondError(w, http.StatusConflict, "Workspace webhook limit reached", requestID)
+	case errors.Is(err, utils.ErrNotFound):
+		utils.RespondError(w, http.StatusNotFound, "Webhook not found", requestID)
+	case errors.Is(err, service.ErrWorkspaceForbidden), errors.Is(err, service.ErrEmptyUpdate):
+		respondWorkspaceError(w, requestID, err)
+	default:
+		slog.Error("webhook request failed", "request_id", requestID, "error", err)
+		utils.RespondError(w, http.StatusInternalServerError, ErrMsgInternalError, requestID)
+	}
+}
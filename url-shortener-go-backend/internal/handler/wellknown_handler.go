@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"url-shortener-go-backend/internal/deeplink"
+)
+
+type WellKnownHandler struct {
+	aasa       []byte
+	assetLinks []byte
+}
+
+func NewWellKnownHandler(cfg deeplink.Config) (*WellKnownHandler, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	aasa, err := deeplink.AppleAppSiteAssociation(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build apple-app-site-association: %w", err)
+	}
+	assetLinks, err := deeplink.AssetLinks(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build assetlinks.json: %w", err)
+	}
+	return &WellKnownHandler{aasa: aasa, assetLinks: assetLinks}, nil
+}
+
+func (h *WellKnownHandler) HandleAppleAppSiteAssociation() http.HandlerFunc {
+	return serveWellKnown(func() []byte { return h.aasa })
+}
+
+func (h *WellKnownHandler) HandleAssetLinks() http.HandlerFunc {
+	return serveWellKnown(func() []byte { return h.assetLinks })
+}
+
+func serveWellKnown(body func() []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		doc := body()
+		if doc == nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+		_, _ = w.Write(doc)
+	}
+}
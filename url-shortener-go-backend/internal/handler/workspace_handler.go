@@ -0,0 +1,266 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"url-shortener-go-backend/internal/handler/dto"
+	"url-shortener-go-backend/internal/handler/mapper"
+	"url-shortener-go-backend/internal/middleware"
+	"url-shortener-go-backend/internal/model"
+	"url-shortener-go-backend/internal/service"
+	"url-shortener-go-backend/internal/utils"
+)
+
+const WorkspaceHeader = "X-Workspace-ID"
+
+type WorkspaceHandler struct {
+	workspaceService service.WorkspaceService
+}
+
+func NewWorkspaceHandler(workspaceService service.WorkspaceService) *WorkspaceHandler {
+	return &WorkspaceHandler{workspaceService: workspaceService}
+}
+
+func (h *WorkspaceHandler) HandleWorkspaces() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetRequestID(r.Context())
+		userID := middleware.GetUserIDFromContext(r.Context())
+		if userID == "" {
+			utils.RespondError(w, http.StatusUnauthorized, ErrMsgUnauthorized, requestID)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			workspaces, err := h.workspaceService.ListWorkspaces(r.Context(), userID)
+			if err != nil {
+				utils.RespondError(w, http.StatusInternalServerError, "Unable to list workspaces", requestID)
+				return
+			}
+			utils.RespondJSON(w, http.StatusOK, mapper.ToWorkspacesResponse(workspaces), requestID)
+		case http.MethodPost:
+			var req dto.CreateWorkspaceRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				utils.RespondError(w, http.StatusBadRequest, ErrMsgInvalidRequest, requestID)
+				return
+			}
+			name, err := model.NormalizeWorkspaceName(req.Name)
+			if err != nil {
+				utils.RespondError(w, http.StatusBadRequest, err.Error(), requestID)
+				return
+			}
+			workspace, err := h.workspaceService.CreateWorkspace(r.Context(), userID, name)
+			if err != nil {
+				respondWorkspaceError(w, requestID, err)
+				return
+			}
+			utils.RespondJSON(w, http.StatusCreated, mapper.ToWorkspaceResponse(*workspace, model.RoleOwner), requestID)
+		default:
+			utils.RespondError(w, http.StatusMethodNotAllowed, ErrMsgMethodNotAllowed, requestID)
+		}
+	}
+}
+
+func (h *WorkspaceHandler) HandleWorkspace() http.HandlerFunc {
+	members := h.HandleWorkspaceMembers()
+	return func(w http.ResponseWriter, r *http.Request) {
+		workspaceID := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/workspaces/"), "/")
+		if strings.Contains(workspaceID, "/") {
+			members(w, r)
+			return
+		}
+
+		requestID := middleware.GetRequestID(r.Context())
+		userID := middleware.GetUserIDFromContext(r.Context())
+		if userID == "" {
+			utils.RespondError(w, http.StatusUnauthorized, ErrMsgUnauthorized, requestID)
+			return
+		}
+
+		required := model.RoleViewer
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPatch:
+			required = model.RoleOwner
+		default:
+			utils.RespondError(w, http.StatusMethodNotAllowed, ErrMsgMethodNotAllowed, requestID)
+			return
+		}
+
+		access, err := h.workspaceService.Authorize(r.Context(), userID, workspaceID, required)
+		if err != nil {
+			respondWorkspaceError(w, requestID, err)
+			return
+		}
+
+		if r.Method == http.MethodGet {
+			workspace, err := h.workspaceService.GetWorkspace(r.Context(), *access)
+			if err != nil {
+				respondWorkspaceError(w, requestID, err)
+				return
+			}
+			utils.RespondJSON(w, http.StatusOK, mapper.ToWorkspaceResponse(*workspace, access.Role), requestID)
+			return
+		}
+
+		var req dto.UpdateWorkspaceRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			utils.RespondError(w, http.StatusBadRequest, ErrMsgInvalidRequest, requestID)
+			return
+		}
+
+		var update model.WorkspaceUpdate
+		if req.Name != nil {
+			name, err := model.NormalizeWorkspaceName(*req.Name)
+			if err != nil {
+				utils.RespondError(w, http.StatusBadRequest, err.Error(), requestID)
+				return
+			}
+			update.Name = &name
+		}
+		if req.DedupPolicy != nil {
+			policy := strings.ToLower(strings.TrimSpace(*req.DedupPolicy))
+			if err := model.ValidateDedupPolicy(policy); err != nil || policy == "" {
+				utils.RespondError(w, http.StatusBadRequest, "dedup_policy must be \"reuse\" or \"always_new\"", requestID)
+				return
+			}
+			update.DedupPolicy = &policy
+		}
+
+		workspace, err := h.workspaceService.UpdateWorkspace(r.Context(), *access, update)
+		if err != nil {
+			respondWorkspaceError(w, requestID, err)
+			return
+		}
+		utils.RespondJSON(w, http.StatusOK, mapper.ToWorkspaceResponse(*workspace, access.Role), requestID)
+	}
+}
+
+func (h *WorkspaceHandler) HandleWorkspaceMembers() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := middleware.GetRequestID(r.Context())
+		userID := middleware.GetUserIDFromContext(r.Context())
+		if userID == "" {
+			utils.RespondError(w, http.StatusUnauthorized, ErrMsgUnauthorized, requestID)
+			return
+		}
+
+		path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/workspaces/"), "/")
+		parts := strings.Split(path, "/")
+		if len(parts) < 2 || len(parts) > 3 || parts[1] != "members" {
+			http.NotFound(w, r)
+			return
+		}
+		workspaceID, memberID := parts[0], ""
+		if len(parts) == 3 {
+			memberID = parts[2]
+			if !utils.IsValidUUID(memberID) {
+				utils.RespondError(w, http.StatusBadRequest, "Invalid user id", requestID)
+				return
+			}
+		}
+
+		required := model.RoleOwner
+		switch {
+		case memberID == "" && r.Method == http.MethodGet, memberID != "" && r.Method == http.MethodDelete:
+			required = model.RoleViewer
+		case memberID == "" && r.Method == http.MethodPost, memberID != "" && r.Method == http.MethodPatch:
+		default:
+			utils.RespondError(w, http.StatusMethodNotAllowed, ErrMsgMethodNotAllowed, requestID)
+			return
+		}
+
+		access, err := h.workspaceService.Authorize(r.Context(), userID, workspaceID, required)
+		if err != nil {
+			respondWorkspaceError(w, requestID, err)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			members, err := h.workspaceService.ListMembers(r.Context(), *access)
+			if err != nil {
+				respondWorkspaceError(w, requestID, err)
+				return
+			}
+			utils.RespondJSON(w, http.StatusOK, mapper.ToWorkspaceMembersResponse(members), requestID)
+		case http.MethodPost:
+			var req dto.AddWorkspaceMemberRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				utils.RespondError(w, http.StatusBadRequest, ErrMsgInvalidRequest, requestID)
+				return
+			}
+			role, err := model.ParseRole(req.Role)
+			if err != nil {
+				utils.RespondError(w, http.StatusBadRequest, err.Error(), requestID)
+				return
+			}
+			if !utils.IsValidUUID(req.UserID) {
+				utils.RespondError(w, http.StatusBadRequest, "Invalid user id", requestID)
+				return
+			}
+			member, err := h.workspaceService.AddMember(r.Context(), *access, req.UserID, role)
+			if err != nil {
+				respondWorkspaceError(w, requestID, err)
+				return
+			}
+			utils.RespondJSON(w, http.StatusCreated, mapper.ToWorkspaceMemberResponse(*member), requestID)
+		case http.MethodPatch:
+			var req dto.UpdateWorkspaceMemberRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				utils.RespondError(w, http.StatusBadRequest, ErrMsgInvalidRequest, requestID)
+				return
+			}
+			role, err := model.ParseRole(req.Role)
+			if err != nil {
+				utils.RespondError(w, http.StatusBadRequest, err.Error(), requestID)
+				return
+			}
+			member, err := h.workspaceService.UpdateMemberRole(r.Context(), *access, memberID, role)
+			if err != nil {
+				respondWorkspaceError(w, requestID, err)
+				return
+			}
+			utils.RespondJSON(w, http.StatusOK, mapper.ToWorkspaceMemberResponse(*member), requestID)
+		case http.MethodDelete:
+			if err := h.workspaceService.RemoveMember(r.Context(), *access, memberID); err != nil {
+				respondWorkspaceError(w, requestID, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}
+}
+
+func workspaceErrorStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidWorkspace):
+		return http.StatusBadRequest, "Invalid workspace"
+	case errors.Is(err, service.ErrNotWorkspaceMember):
+		return http.StatusNotFound, "Workspace not found"
+	case errors.Is(err, service.ErrWorkspaceForbidden):
+		return http.StatusForbidden, "Your workspace role does not allow this action"
+	case errors.Is(err, service.ErrLastWorkspaceOwner):
+		return http.StatusConflict, "Workspace must keep at least one owner"
+	case errors.Is(err, service.ErrWorkspaceMemberExists):
+		return http.StatusConflict, "User is already a member of this workspace"
+	case errors.Is(err, service.ErrEmptyUpdate):
+		return http.StatusBadRequest, "No fields to update"
+	case errors.Is(err, utils.ErrNotFound):
+		return http.StatusNotFound, "Member not found"
+	default:
+		return http.StatusInternalServerError, ErrMsgInternalError
+	}
+}
+
+func respondWorkspaceError(w http.ResponseWriter, requestID string, err error) {
+	status, message := workspaceErrorStatus(err)
+	if status == http.StatusInternalServerError {
+		slog.Error("workspace request failed", "request_id", requestID, "error", err)
+	}
+	utils.RespondError(w, status, message, requestID)
+}
@@ -0,0 +1,221 @@
+package homograph
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/text/unicode/norm"
+)
+
+type Analysis struct {
+	ASCII         string
+	Unicode       string
+	IsIDN         bool
+	MixedScript   bool
+	Scripts       []string
+	ImitatedBrand string
+}
+
+func (a Analysis) Suspicious() bool {
+	return a.MixedScript || a.ImitatedBrand != ""
+}
+
+func (a Analysis) Warnings() []string {
+	var warnings []string
+	if a.MixedScript {
+		warnings = append(warnings, fmt.Sprintf("domain mixes scripts: %s", strings.Join(a.Scripts, ", ")))
+	}
+	if a.ImitatedBrand != "" {
+		warnings = append(warnings, fmt.Sprintf("domain resembles protected domain '%s'", a.ImitatedBrand))
+	}
+	return warnings
+}
+
+var profile = idna.New(
+	idna.MapForLookup(),
+	idna.StrictDomainName(false),
+	idna.BidiRule(),
+	idna.Transitional(false),
+)
+
+func ToASCII(host string) (string, error) {
+	return profile.ToASCII(strings.TrimSuffix(host, "."))
+}
+
+func ToUnicode(host string) (string, error) {
+	ascii, err := ToASCII(host)
+	if err != nil {
+		return "", err
+	}
+	return profile.ToUnicode(ascii)
+}
+
+func Analyze(host string, brands []string) (Analysis, error) {
+	if host == "" || net.ParseIP(host) != nil {
+		return Analysis{ASCII: host, Unicode: host}, nil
+	}
+
+	ascii, err := ToASCII(host)
+	if err != nil {
+		return Analysis{}, fmt.Errorf("invalid internationalized domain name: %w", err)
+	}
+	uni, err := profile.ToUnicode(ascii)
+	if err != nil {
+		return Analysis{}, fmt.Errorf("invalid internationalized domain name: %w", err)
+	}
+
+	a := Analysis{
+		ASCII:   ascii,
+		Unicode: uni,
+		IsIDN:   ascii != uni,
+	}
+
+	seen := make(map[string]bool)
+	for _, label := range strings.Split(uni, ".") {
+		scripts := labelScripts(label)
+		for _, s := range scripts {
+			seen[s] = true
+		}
+		if !allowedCombination(scripts) {
+			a.MixedScript = true
+		}
+	}
+	for s := range seen {
+		a.Scripts = append(a.Scripts, s)
+	}
+	sort.Strings(a.Scripts)
+
+	a.ImitatedBrand = imitatedBrand(uni, brands)
+
+	return a, nil
+}
+
+func imitatedBrand(host string, brands []string) string {
+	labels := strings.Split(host, ".")
+	for _, brand := range brands {
+		brand, err := ToUnicode(strings.ToLower(strings.TrimSpace(brand)))
+		if err != nil || brand == "" {
+			continue
+		}
+		if host == brand || strings.HasSuffix(host, "."+brand) {
+			continue
+		}
+
+		name := strings.SplitN(brand, ".", 2)[0]
+		target := Skeleton(name)
+		for _, label := range labels {
+			if label != name && Skeleton(label) == target {
+				return brand
+			}
+		}
+
+		if Skeleton(host) == Skeleton(brand) {
+			return brand
+		}
+	}
+	return ""
+}
+
+func labelScripts(label string) []string {
+	set := make(map[string]bool)
+	for _, r := range label {
+		if s := scriptOf(r); s != "" {
+			set[s] = true
+		}
+	}
+	scripts := make([]string, 0, len(set))
+	for s := range set {
+		scripts = append(scripts, s)
+	}
+	sort.Strings(scripts)
+	return scripts
+}
+
+func scriptOf(r rune) string {
+	if r < unicode.MaxASCII {
+		if unicode.IsLetter(r) {
+			return "Latin"
+		}
+		return ""
+	}
+	if unicode.Is(unicode.Latin, r) {
+		return "Latin"
+	}
+	for name, table := range unicode.Scripts {
+		if name == "Common" || name == "Inherited" {
+			continue
+		}
+		if unicode.Is(table, r) {
+			return name
+		}
+	}
+	return ""
+}
+
+var allowedSets = [][]string{
+	{"Latin", "Han", "Hiragana", "Katakana"},
+	{"Latin", "Han", "Bopomofo"},
+	{"Latin", "Han", "Hangul"},
+}
+
+func allowedCombination(scripts []string) bool {
+	if len(scripts) <= 1 {
+		return true
+	}
+	for _, set := range allowedSets {
+		if subset(scripts, set) {
+			return true
+		}
+	}
+	return false
+}
+
+func subset(scripts, set []string) bool {
+	for _, s := range scripts {
+		found := false
+		for _, allowed := range set {
+			if s == allowed {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+var confusables = map[rune]string{
+	'а': "a", 'в': "b", 'е': "e", 'ё': "e", 'һ': "h", 'і': "i", 'ї': "i", 'ј': "j",
+	'к': "k", 'ӏ': "l", 'м': "m", 'н': "h", 'о': "o", 'р': "p", 'с': "c", 'т': "t",
+	'у': "y", 'х': "x", 'ѕ': "s", 'ԁ': "d", 'ԛ': "q", 'ԝ': "w", 'ь': "b", 'ɡ': "g",
+	'α': "a", 'β': "b", 'ε': "e", 'η': "n", 'ι': "i", 'κ': "k", 'ν': "v", 'ο': "o",
+	'ρ': "p", 'τ': "t", 'υ': "u", 'χ': "x", 'ϲ': "c", 'ϳ': "j",
+	'օ': "o", 'ս': "u", 'հ': "h", 'ո': "n", 'ց': "g", 'զ': "q",
+	'ı': "i", 'ɩ': "i", 'ɑ': "a", 'ɒ': "a", 'ʟ': "l", 'ȷ': "j", 'ł': "l", 'ø': "o",
+	'0': "o", '1': "l", '3': "e", '5': "s",
+}
+
+func Skeleton(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if mapped, ok := confusables[r]; ok {
+			b.WriteString(mapped)
+			continue
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	out := b.String()
+	out = strings.ReplaceAll(out, "rn", "m")
+	out = strings.ReplaceAll(out, "vv", "w")
+	out = strings.ReplaceAll(out, "cl", "d")
+	return out
+}
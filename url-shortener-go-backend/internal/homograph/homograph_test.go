@@ -0,0 +1,84 @@
+package homograph
+
+import (
+	"reflect"
+	"testing"
+)
+
+var testBrands = []string{"paypal.com", "apple.com", "google.com", "microsoft.com"}
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		host        string
+		wantASCII   string
+		wantUnicode string
+		wantIDN     bool
+		wantMixed   bool
+		wantScripts []string
+		wantBrand   string
+	}{
+		{host: "раураl.com", wantASCII: "xn--l-7sba6dbr.com", wantUnicode: "раураl.com", wantIDN: true, wantMixed: true, wantScripts: []string{"Cyrillic", "Latin"}, wantBrand: "paypal.com"},
+		{host: "xn--l-7sba6dbr.com", wantASCII: "xn--l-7sba6dbr.com", wantUnicode: "раураl.com", wantIDN: true, wantMixed: true, wantScripts: []string{"Cyrillic", "Latin"}, wantBrand: "paypal.com"},
+		{host: "XN--L-7SBA6DBR.COM.", wantASCII: "xn--l-7sba6dbr.com", wantUnicode: "раураl.com", wantIDN: true, wantMixed: true, wantScripts: []string{"Cyrillic", "Latin"}, wantBrand: "paypal.com"},
+		{host: "аррӏе.com", wantASCII: "xn--80ak6aa92e.com", wantUnicode: "аррӏе.com", wantIDN: true, wantScripts: []string{"Cyrillic", "Latin"}, wantBrand: "apple.com"},
+		{host: "пример.рф", wantASCII: "xn--e1afmkfd.xn--p1ai", wantUnicode: "пример.рф", wantIDN: true, wantScripts: []string{"Cyrillic"}},
+		{host: "xn--d1acpjx3f.xn--p1ai", wantASCII: "xn--d1acpjx3f.xn--p1ai", wantUnicode: "яндекс.рф", wantIDN: true, wantScripts: []string{"Cyrillic"}},
+		{host: "bücher.de", wantASCII: "xn--bcher-kva.de", wantUnicode: "bücher.de", wantIDN: true, wantScripts: []string{"Latin"}},
+		{host: "東京tokyo.jp", wantASCII: "xn--tokyo-w91hq39l.jp", wantUnicode: "東京tokyo.jp", wantIDN: true, wantScripts: []string{"Han", "Latin"}},
+		{host: "g00gle.com", wantASCII: "g00gle.com", wantUnicode: "g00gle.com", wantScripts: []string{"Latin"}, wantBrand: "google.com"},
+		{host: "rnicrosoft.com", wantASCII: "rnicrosoft.com", wantUnicode: "rnicrosoft.com", wantScripts: []string{"Latin"}, wantBrand: "microsoft.com"},
+		{host: "login.paypal.com", wantASCII: "login.paypal.com", wantUnicode: "login.paypal.com", wantScripts: []string{"Latin"}},
+		{host: "Example.COM", wantASCII: "example.com", wantUnicode: "example.com", wantScripts: []string{"Latin"}},
+		{host: "93.184.216.34", wantASCII: "93.184.216.34", wantUnicode: "93.184.216.34"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			a, err := Analyze(tt.host, testBrands)
+			if err != nil {
+				t.Fatalf("Analyze(%q): %v", tt.host, err)
+			}
+			if a.ASCII != tt.wantASCII || a.Unicode != tt.wantUnicode || a.IsIDN != tt.wantIDN {
+				t.Fatalf("Analyze(%q) = ascii %q unicode %q idn %v, want %q %q %v", tt.host, a.ASCII, a.Unicode, a.IsIDN, tt.wantASCII, tt.wantUnicode, tt.wantIDN)
+			}
+			if a.MixedScript != tt.wantMixed || !reflect.DeepEqual(a.Scripts, tt.wantScripts) {
+				t.Fatalf("Analyze(%q) scripts = %v mixed %v, want %v mixed %v", tt.host, a.Scripts, a.MixedScript, tt.wantScripts, tt.wantMixed)
+			}
+			if a.ImitatedBrand != tt.wantBrand {
+				t.Fatalf("Analyze(%q) imitates %q, want %q", tt.host, a.ImitatedBrand, tt.wantBrand)
+			}
+			if a.Suspicious() != (tt.wantMixed || tt.wantBrand != "") {
+				t.Fatalf("Analyze(%q).Suspicious() = %v", tt.host, a.Suspicious())
+			}
+		})
+	}
+}
+
+func TestAnalyzeRejectsInvalidIDN(t *testing.T) {
+	if _, err := Analyze("xn--a.com", testBrands); err == nil {
+		t.Fatal("Analyze(xn--a.com) = nil error, want invalid IDN")
+	}
+}
+
+func TestSkeleton(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "раураl", want: "paypal"},
+		{in: "аррӏе", want: "apple"},
+		{in: "PayPal", want: "paypal"},
+		{in: "g00gle", want: "google"},
+		{in: "rnicrosoft", want: "microsoft"},
+		{in: "vvikipedia", want: "wikipedia"},
+		{in: "paypál", want: "paypal"},
+		{in: "οрenаі", want: "openai"},
+		{in: "пример", want: "пpиmep"},
+	}
+
+	for _, tt := range tests {
+		if got := Skeleton(tt.in); got != tt.want {
+			t.Fatalf("Skeleton(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package linkhealth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"url-shortener-go-backend/internal/model"
+	"url-shortener-go-backend/internal/netguard"
+)
+
+const (
+	maxRedirects     = 5
+	maxErrorLength   = 200
+	maxDrainBytes    = 4 << 10
+	defaultUserAgent = "url-shortener-linkcheck/1.0"
+	defaultTimeout   = 10 * time.Second
+)
+
+type Prober struct {
+	client  *http.Client
+	timeout time.Duration
+}
+
+func NewProber(timeout time.Duration, policy netguard.Policy) *Prober {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	client := &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:                 nil,
+			DialContext:           policy.Dialer(timeout).DialContext,
+			TLSHandshakeTimeout:   timeout,
+			ResponseHeaderTimeout: timeout,
+			MaxIdleConns:          20,
+			IdleConnTimeout:       30 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return fmt.Errorf("redirect to unsupported scheme %q", req.URL.Scheme)
+			}
+			return nil
+		},
+	}
+
+	return &Prober{client: client, timeout: timeout}
+}
+
+func (p *Prober) Probe(ctx context.Context, rawURL string) model.HealthCheck {
+	start := time.Now()
+	check := model.HealthCheck{CheckedAt: start.UTC()}
+
+	status, err := p.do(ctx, http.MethodHead, rawURL)
+	if err == nil && (status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented || status == http.StatusForbidden) {
+		status, err = p.do(ctx, http.MethodGet, rawURL)
+	}
+
+	check.LatencyMS = time.Since(start).Milliseconds()
+	check.StatusCode = status
+	if err != nil {
+		check.Error = describe(err)
+		return check
+	}
+	check.Healthy = status < http.StatusBadRequest
+	return check
+}
+
+func (p *Prober) do(ctx context.Context, method, rawURL string) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return 0, fmt.Errorf("invalid destination: %w", err)
+	}
+	req.Header.Set("User-Agent", defaultUserAgent)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrainBytes))
+
+	return resp.StatusCode, nil
+}
+
+func describe(err error) string {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	msg := err.Error()
+	if errors.Is(err, context.DeadlineExceeded) || strings.Contains(msg, "Client.Timeout") {
+		msg = "timeout"
+	}
+	if len(msg) > maxErrorLength {
+		msg = msg[:maxErrorLength]
+	}
+	return msg
+}
+
+type Runner struct {
+	prober      *Prober
+	concurrency int
+	perHost     int
+}
+
+func NewRunner(prober *Prober, concurrency, perHost int) *Runner {
+	return &Runner{prober: prober, concurrency: max(concurrency, 1), perHost: max(perHost, 1)}
+}
+
+func (r *Runner) Run(ctx context.Context, targets []string, fn func(i int, check model.HealthCheck)) {
+	var (
+		mu    sync.Mutex
+		hosts = make(map[string]chan struct{})
+		jobs  = make(chan int)
+		wg    sync.WaitGroup
+	)
+
+	hostSlot := func(host string) chan struct{} {
+		mu.Lock()
+		defer mu.Unlock()
+		slot, ok := hosts[host]
+		if !ok {
+			slot = make(chan struct{}, r.perHost)
+			hosts[host] = slot
+		}
+		return slot
+	}
+
+	for w := 0; w < r.concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				host := ""
+				if u, err := url.Parse(targets[i]); err == nil {
+					host = strings.ToLower(u.Hostname())
+				}
+
+				slot := hostSlot(host)
+				select {
+				case slot <- struct{}{}:
+				case <-ctx.Done():
+					continue
+				}
+				check := r.prober.Probe(ctx, targets[i])
+				<-slot
+
+				if ctx.Err() != nil {
+					continue
+				}
+				fn(i, check)
+			}
+		}()
+	}
+
+	for i := range targets {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(jobs)
+	wg.Wait()
+}
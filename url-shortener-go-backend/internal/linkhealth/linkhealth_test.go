@@ -0,0 +1,132 @@
+package linkhealth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"url-shortener-go-backend/internal/model"
+	"url-shortener-go-backend/internal/netguard"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/no-head", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("/error", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/ok", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusFound)
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(2 * time.Second):
+		case <-r.Context().Done():
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestProbe(t *testing.T) {
+	srv := newTestServer(t)
+	prober := NewProber(200*time.Millisecond, netguard.Policy{AllowLoopback: true})
+
+	tests := []struct {
+		path        string
+		wantHealthy bool
+		wantStatus  int
+		wantError   string
+	}{
+		{path: "/ok", wantHealthy: true, wantStatus: http.StatusOK},
+		{path: "/no-head", wantHealthy: true, wantStatus: http.StatusOK},
+		{path: "/moved", wantHealthy: true, wantStatus: http.StatusOK},
+		{path: "/missing", wantStatus: http.StatusNotFound},
+		{path: "/error", wantStatus: http.StatusInternalServerError},
+		{path: "/loop", wantError: "stopped after 5 redirects"},
+		{path: "/slow", wantError: "timeout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			check := prober.Probe(context.Background(), srv.URL+tt.path)
+			if check.Healthy != tt.wantHealthy || check.StatusCode != tt.wantStatus {
+				t.Fatalf("Probe(%s) = %+v, want healthy=%v status=%d", tt.path, check, tt.wantHealthy, tt.wantStatus)
+			}
+			if !strings.Contains(check.Error, tt.wantError) || (tt.wantError == "" && check.Error != "") {
+				t.Fatalf("Probe(%s) error = %q, want %q", tt.path, check.Error, tt.wantError)
+			}
+			if check.CheckedAt.IsZero() {
+				t.Fatal("CheckedAt not set")
+			}
+		})
+	}
+}
+
+func TestProbeRefusesLoopbackByDefault(t *testing.T) {
+	srv := newTestServer(t)
+	check := NewProber(time.Second, netguard.Policy{}).Probe(context.Background(), srv.URL+"/ok")
+	if check.Healthy || check.Error == "" {
+		t.Fatalf("Probe of loopback = %+v, want a refused connection", check)
+	}
+}
+
+func TestRunnerPerHostLimit(t *testing.T) {
+	var inFlight, peak int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+	}))
+	t.Cleanup(srv.Close)
+
+	runner := NewRunner(NewProber(time.Second, netguard.Policy{AllowLoopback: true}), 8, 2)
+	targets := make([]string, 10)
+	for i := range targets {
+		targets[i] = srv.URL + "/"
+	}
+
+	var mu sync.Mutex
+	seen := make(map[int]bool)
+	runner.Run(context.Background(), targets, func(i int, check model.HealthCheck) {
+		mu.Lock()
+		seen[i] = check.Healthy
+		mu.Unlock()
+	})
+
+	if len(seen) != len(targets) {
+		t.Fatalf("callbacks = %d, want %d", len(seen), len(targets))
+	}
+	if p := atomic.LoadInt32(&peak); p > 2 {
+		t.Fatalf("peak concurrent probes per host = %d, want <= 2", p)
+	}
+}
@@ -0,0 +1,227 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"url-shortener-go-backend/internal/netguard"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+const (
+	maxBodyBytes      = 512 << 10
+	maxRedirects      = 5
+	maxTitleLength    = 300
+	maxDescLength     = 1000
+	maxLinkLength     = 2048
+	defaultUserAgent  = "url-shortener-metadata/1.0"
+	defaultFetchLimit = 5 * time.Second
+)
+
+var ErrNotHTML = errors.New("destination is not an HTML document")
+
+type Metadata struct {
+	Title       string
+	Description string
+	ImageURL    string
+	FaviconURL  string
+}
+
+type Fetcher interface {
+	Fetch(ctx context.Context, rawURL string) (*Metadata, error)
+}
+
+type HTTPFetcher struct {
+	client  *http.Client
+	timeout time.Duration
+}
+
+func NewHTTPFetcher(timeout time.Duration) *HTTPFetcher {
+	if timeout <= 0 {
+		timeout = defaultFetchLimit
+	}
+
+	policy := netguard.Policy{}
+	client := &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:                 nil,
+			DialContext:           policy.Dialer(timeout).DialContext,
+			TLSHandshakeTimeout:   timeout,
+			ResponseHeaderTimeout: timeout,
+			MaxIdleConns:          10,
+			IdleConnTimeout:       30 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return fmt.Errorf("redirect to unsupported scheme %q", req.URL.Scheme)
+			}
+			return nil
+		},
+	}
+
+	return &HTTPFetcher{client: client, timeout: timeout}
+}
+
+func (f *HTTPFetcher) Fetch(ctx context.Context, rawURL string) (*Metadata, error) {
+	ctx, cancel := context.WithTimeout(ctx, f.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid destination: %w", err)
+	}
+	req.Header.Set("User-Agent", defaultUserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.1")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch destination: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("destination returned status %d", resp.StatusCode)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil &&
+		mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, ErrNotHTML
+	}
+
+	body, err := charset.NewReader(io.LimitReader(resp.Body, maxBodyBytes), contentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode destination body: %w", err)
+	}
+
+	return Parse(body, resp.Request.URL)
+}
+
+func Parse(r io.Reader, base *url.URL) (*Metadata, error) {
+	var (
+		title, ogTitle, desc, ogDesc, image, icon string
+		inTitle                                   bool
+	)
+
+	z := html.NewTokenizer(r)
+loop:
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			if errors.Is(z.Err(), io.EOF) {
+				break loop
+			}
+			return nil, fmt.Errorf("failed to parse destination html: %w", z.Err())
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+			switch tok.Data {
+			case "title":
+				inTitle = title == ""
+			case "meta":
+				key := strings.ToLower(attr(tok, "property"))
+				if key == "" {
+					key = strings.ToLower(attr(tok, "name"))
+				}
+				content := attr(tok, "content")
+				switch key {
+				case "og:title":
+					ogTitle = content
+				case "og:description":
+					ogDesc = content
+				case "description":
+					desc = content
+				case "og:image", "og:image:url", "og:image:secure_url", "twitter:image":
+					if image == "" {
+						image = content
+					}
+				}
+			case "link":
+				rels := strings.Fields(strings.ToLower(attr(tok, "rel")))
+				for _, rel := range rels {
+					if rel == "icon" || (rel == "apple-touch-icon" && icon == "") {
+						icon = attr(tok, "href")
+					}
+				}
+			case "body":
+				break loop
+			}
+		case html.TextToken:
+			if inTitle {
+				title += string(z.Text())
+			}
+		case html.EndTagToken:
+			if tok := z.Token(); tok.Data == "title" {
+				inTitle = false
+			} else if tok.Data == "head" {
+				break loop
+			}
+		}
+	}
+
+	m := &Metadata{
+		Title:       clean(firstNonEmpty(ogTitle, title), maxTitleLength),
+		Description: clean(firstNonEmpty(ogDesc, desc), maxDescLength),
+		ImageURL:    resolve(base, image),
+		FaviconURL:  resolve(base, firstNonEmpty(icon, "/favicon.ico")),
+	}
+	return m, nil
+}
+
+func attr(tok html.Token, name string) string {
+	for _, a := range tok.Attr {
+		if a.Key == name {
+			return strings.TrimSpace(a.Val)
+		}
+	}
+	return ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func clean(s string, limit int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if len(s) <= limit {
+		return s
+	}
+	s = s[:limit]
+	for !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+func resolve(base *url.URL, ref string) string {
+	if ref == "" || base == nil {
+		return ""
+	}
+	u, err := base.Parse(ref)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	s := u.String()
+	if len(s) > maxLinkLength {
+		return ""
+	}
+	return s
+}
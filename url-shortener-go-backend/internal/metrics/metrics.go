@@ -36,11 +36,32 @@ var (
 		Help: "Total shorten operations",
 	})
 
+	ShortCodeCollisionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "short_code_collisions_total",
+			Help: "Generated short codes that were already taken, by generator",
+		},
+		[]string{"generator"},
+	)
+
 	URLRedirectsTotal = prometheus.NewCounter(prometheus.CounterOpts{
 		Name: "url_redirects_total",
 		Help: "Total redirect operations",
 	})
 
+	URLUnfurlsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "url_unfurls_total",
+		Help: "Total unfurl pages served to link preview bots",
+	})
+
+	URLDeepLinksTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "url_deep_links_total",
+			Help: "Redirects sent to a mobile app deep link",
+		},
+		[]string{"platform", "kind"},
+	)
+
 	CacheHitsTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "cache_hits_total",
@@ -78,6 +99,38 @@ var (
 		Name: "analytics_records_total",
 		Help: "Analytics events recorded",
 	})
+
+	LinkHealthChecksTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "link_health_checks_total",
+			Help: "Destination health probes by resulting link status",
+		},
+		[]string{"status"},
+	)
+
+	ReputationChecksTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "reputation_checks_total",
+			Help: "URL reputation checks by result",
+		},
+		[]string{"result"},
+	)
+
+	WebhookEventsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "webhook_events_total",
+			Help: "Webhook events emitted by type and outcome",
+		},
+		[]string{"type", "outcome"},
+	)
+
+	WebhookDeliveriesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "webhook_deliveries_total",
+			Help: "Webhook delivery attempts by resulting delivery status",
+		},
+		[]string{"status"},
+	)
 )
 
 var once sync.Once
@@ -89,12 +142,19 @@ func Init() {
 			HTTPRequestDuration,
 			HTTPRequestsInFlight,
 			URLShortensTotal,
+			ShortCodeCollisionsTotal,
 			URLRedirectsTotal,
+			URLUnfurlsTotal,
+			URLDeepLinksTotal,
 			CacheHitsTotal,
 			CacheMissesTotal,
 			DBQueryDuration,
 			RateLimitExceededTotal,
 			AnalyticsRecordsTotal,
+			ReputationChecksTotal,
+			LinkHealthChecksTotal,
+			WebhookEventsTotal,
+			WebhookDeliveriesTotal,
 		)
 	})
 }
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"url-shortener-go-backend/internal/utils"
+)
+
+func RequireAdmin(adminUserIDs []string) func(http.Handler) http.Handler {
+	admins := make(map[string]bool, len(adminUserIDs))
+	for _, id := range adminUserIDs {
+		if id = strings.TrimSpace(id); id != "" {
+			admins[id] = true
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID := GetUserIDFromContext(r.Context())
+			if userID == "" {
+				utils.RespondError(w, http.StatusUnauthorized, "Authentication required", GetRequestID(r.Context()))
+				return
+			}
+
+			if !admins[userID] {
+				utils.RespondError(w, http.StatusForbidden, "Admin access required", GetRequestID(r.Context()))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	_, proxies, _ := net.ParseCIDR("10.0.0.0/8")
+	trusted := []*net.IPNet{proxies}
+
+	tests := []struct {
+		name    string
+		remote  string
+		headers map[string]string
+		trusted []*net.IPNet
+		want    string
+	}{
+		{name: "no proxy", remote: "203.0.113.7:5000", want: "203.0.113.7"},
+		{name: "spoofed without trusted proxies", remote: "203.0.113.7:5000", headers: map[string]string{"X-Forwarded-For": "1.2.3.4"}, want: "203.0.113.7"},
+		{name: "spoofed from untrusted peer", remote: "203.0.113.7:5000", headers: map[string]string{"X-Forwarded-For": "1.2.3.4", "X-Real-IP": "1.2.3.4"}, trusted: trusted, want: "203.0.113.7"},
+		{name: "trusted proxy", remote: "10.0.0.2:5000", headers: map[string]string{"X-Forwarded-For": "198.51.100.9"}, trusted: trusted, want: "198.51.100.9"},
+		{name: "spoofed first hop", remote: "10.0.0.2:5000", headers: map[string]string{"X-Forwarded-For": "1.2.3.4, 198.51.100.9"}, trusted: trusted, want: "198.51.100.9"},
+		{name: "proxy chain", remote: "10.0.0.2:5000", headers: map[string]string{"X-Forwarded-For": "1.2.3.4, 198.51.100.9, 10.0.0.5"}, trusted: trusted, want: "198.51.100.9"},
+		{name: "all hops trusted", remote: "10.0.0.2:5000", headers: map[string]string{"X-Forwarded-For": "10.0.0.9, 10.0.0.5"}, trusted: trusted, want: "10.0.0.9"},
+		{name: "garbage hop", remote: "10.0.0.2:5000", headers: map[string]string{"X-Forwarded-For": "1.2.3.4, junk, 10.0.0.5"}, trusted: trusted, want: "10.0.0.5"},
+		{name: "real ip from trusted proxy", remote: "10.0.0.2:5000", headers: map[string]string{"X-Real-IP": "198.51.100.9"}, trusted: trusted, want: "198.51.100.9"},
+		{name: "cloudflare from trusted proxy", remote: "10.0.0.2:5000", headers: map[string]string{"CF-Connecting-IP": "2001:db8::1"}, trusted: trusted, want: "2001:db8::1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.remote
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+
+			var got string
+			ClientIPMiddleware(tt.trusted)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = ClientIP(r)
+			})).ServeHTTP(httptest.NewRecorder(), r)
+			if got != tt.want {
+				t.Fatalf("ClientIP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientIPWithoutMiddlewareUsesPeer(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "203.0.113.7:5000"
+	r.Header.Set("X-Forwarded-For", "1.2.3.4")
+	if got := ClientIP(r); got != "203.0.113.7" {
+		t.Fatalf("ClientIP = %q, want the peer address", got)
+	}
+}
@@ -4,9 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net"
 	"net/http"
-	"strings"
 	"sync"
 	"time"
 
@@ -131,12 +129,20 @@ func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
 }
 
 func (rl *RateLimiter) CustomMiddleware(limit int, window time.Duration) func(http.Handler) http.Handler {
+	return rl.bucketMiddleware(limit, window, func(r *http.Request) string { return r.URL.Path })
+}
+
+func (rl *RateLimiter) BucketMiddleware(bucket string, limit int, window time.Duration) func(http.Handler) http.Handler {
+	return rl.bucketMiddleware(limit, window, func(*http.Request) string { return bucket })
+}
+
+func (rl *RateLimiter) bucketMiddleware(limit int, window time.Duration, bucket func(*http.Request) string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			ctx := r.Context()
 			identifier := rl.getIdentifier(ctx, r)
 
-			key := fmt.Sprintf("%s:custom:%s", rl.buildKey(identifier), r.URL.Path)
+			key := fmt.Sprintf("%s:custom:%s", rl.buildKey(identifier), bucket(r))
 
 			result, err := rl.checkRateLimitWithCustom(ctx, key, limit, window)
 			if err != nil {
@@ -174,7 +180,7 @@ type RateLimitResult struct {
 
 func (rl *RateLimiter) getIdentifier(ctx context.Context, r *http.Request) Identifier {
 	userID := GetUserIDFromContext(ctx)
-	ip := rl.extractIP(r)
+	ip := ClientIP(r)
 
 	tier := "anonymous"
 	if userID != "" {
@@ -188,26 +194,6 @@ func (rl *RateLimiter) getIdentifier(ctx context.Context, r *http.Request) Ident
 	}
 }
 
-func (rl *RateLimiter) extractIP(r *http.Request) string {
-	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
-		ips := strings.Split(xff, ",")
-		if len(ips) > 0 {
-			return strings.TrimSpace(ips[0])
-		}
-	}
-
-	if xri := r.Header.Get("X-Real-IP"); xri != "" {
-		return strings.TrimSpace(xri)
-	}
-
-	if cfIP := r.Header.Get("CF-Connecting-IP"); cfIP != "" {
-		return strings.TrimSpace(cfIP)
-	}
-
-	ip, _, _ := net.SplitHostPort(r.RemoteAddr)
-	return ip
-}
-
 func (rl *RateLimiter) getLimit(identifier Identifier) int {
 	switch identifier.Tier {
 	case "premium":
@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"url-shortener-go-backend/internal/netguard"
+)
+
+var (
+	ErrPreflightDeadLink         = errors.New("destination is not reachable")
+	ErrPreflightTLS              = errors.New("destination has an invalid TLS certificate")
+	ErrPreflightTooManyRedirects = errors.New("destination redirects too many times")
+	ErrPreflightBlockedHop       = errors.New("destination redirects to a disallowed URL")
+)
+
+type PreflightResult struct {
+	FinalURL   string
+	StatusCode int
+	Redirects  int
+}
+
+func (v *URLValidator) PreflightEnabled() bool {
+	return v.config.PreflightEnabled
+}
+
+func (v *URLValidator) Preflight(ctx context.Context, rawURL string) (*PreflightResult, error) {
+	timeout := v.config.PreflightTimeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			Proxy:                 nil,
+			DialContext:           v.policy().Dialer(timeout).DialContext,
+			TLSHandshakeTimeout:   timeout,
+			ResponseHeaderTimeout: timeout,
+			DisableKeepAlives:     true,
+			TLSClientConfig:       &tls.Config{MinVersion: tls.VersionTLS12},
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	current := rawURL
+	for redirects := 0; ; redirects++ {
+		if redirects > 0 {
+			if err := v.ValidateURLContext(ctx, current); err != nil {
+				return nil, fmt.Errorf("%w: hop %d: %v", ErrPreflightBlockedHop, redirects, err)
+			}
+		}
+
+		status, location, err := v.probe(ctx, client, current)
+		if err != nil {
+			if errors.Is(err, netguard.ErrLoopbackAddress) || errors.Is(err, netguard.ErrReservedAddress) {
+				return nil, fmt.Errorf("%w: %v", ErrPreflightBlockedHop, err)
+			}
+			if isTLSError(err) {
+				if !v.config.ValidateSSL {
+					return &PreflightResult{FinalURL: current, Redirects: redirects}, nil
+				}
+				return nil, fmt.Errorf("%w: %v", ErrPreflightTLS, err)
+			}
+			return nil, fmt.Errorf("%w: %v", ErrPreflightDeadLink, err)
+		}
+
+		if status >= 300 && status < 400 && location != "" {
+			if redirects >= v.config.MaxRedirects {
+				return nil, fmt.Errorf("%w: more than %d redirects", ErrPreflightTooManyRedirects, v.config.MaxRedirects)
+			}
+			next, err := resolveLocation(current, location)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid redirect location: %v", ErrPreflightBlockedHop, err)
+			}
+			current = next
+			continue
+		}
+
+		if status >= 400 {
+			return nil, fmt.Errorf("%w: status %d", ErrPreflightDeadLink, status)
+		}
+
+		return &PreflightResult{FinalURL: current, StatusCode: status, Redirects: redirects}, nil
+	}
+}
+
+func (v *URLValidator) probe(ctx context.Context, client *http.Client, target string) (int, string, error) {
+	status, location, err := v.doProbe(ctx, client, http.MethodHead, target)
+	if err != nil {
+		return 0, "", err
+	}
+
+	if status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented || status == http.StatusForbidden {
+		return v.doProbe(ctx, client, http.MethodGet, target)
+	}
+
+	return status, location, nil
+}
+
+func (v *URLValidator) doProbe(ctx context.Context, client *http.Client, method, target string) (int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, nil)
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("User-Agent", "url-shortener-preflight/1.0")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	return resp.StatusCode, resp.Header.Get("Location"), nil
+}
+
+func resolveLocation(base, location string) (string, error) {
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	ref, err := url.Parse(location)
+	if err != nil {
+		return "", err
+	}
+	return baseURL.ResolveReference(ref).String(), nil
+}
+
+func isTLSError(err error) bool {
+	var certErr *tls.CertificateVerificationError
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	var recordErr tls.RecordHeaderError
+
+	return errors.As(err, &certErr) ||
+		errors.As(err, &unknownAuthority) ||
+		errors.As(err, &hostnameErr) ||
+		errors.As(err, &invalidErr) ||
+		errors.As(err, &recordErr)
+}
@@ -1,12 +1,25 @@
 package middleware
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"net/url"
 	"regexp"
 	"strings"
 	"sync"
+	"time"
+
+	"url-shortener-go-backend/internal/homograph"
+	"url-shortener-go-backend/internal/netguard"
+)
+
+type HomographPolicy string
+
+const (
+	HomographOff   HomographPolicy = "off"
+	HomographWarn  HomographPolicy = "warn"
+	HomographBlock HomographPolicy = "block"
 )
 
 type URLValidator struct {
@@ -29,12 +42,22 @@ type URLValidatorConfig struct {
 	AllowedProtocols  []string
 	BlockedExtensions []string
 	MaxURLLength      int
+	PreflightEnabled  bool
+	PreflightTimeout  time.Duration
+	ResolveHostnames  bool
+	DNSTimeout        time.Duration
+	Resolver          netguard.Resolver
+	HomographPolicy   HomographPolicy
+	ProtectedDomains  []string
 }
 
 func NewURLValidator(config *URLValidatorConfig) *URLValidator {
 	if config == nil {
 		config = DefaultConfig()
 	}
+	if config.Resolver == nil {
+		config.Resolver = net.DefaultResolver
+	}
 
 	v := &URLValidator{
 		whitelist: make(map[string]bool),
@@ -43,11 +66,11 @@ func NewURLValidator(config *URLValidatorConfig) *URLValidator {
 	}
 
 	for _, domain := range config.AllowedDomains {
-		v.whitelist[strings.ToLower(domain)] = true
+		v.whitelist[normalizeDomain(domain)] = true
 	}
 
 	for _, domain := range config.BlockedDomains {
-		v.blacklist[strings.ToLower(domain)] = true
+		v.blacklist[normalizeDomain(domain)] = true
 	}
 
 	for _, pattern := range config.BlockedPatterns {
@@ -93,11 +116,28 @@ func DefaultConfig() *URLValidatorConfig {
 			".msi", ".msp",
 			".zip", ".rar", ".7z",
 		},
-		MaxURLLength: 2048,
+		MaxURLLength:     2048,
+		PreflightEnabled: false,
+		PreflightTimeout: 5 * time.Second,
+		ResolveHostnames: true,
+		DNSTimeout:       2 * time.Second,
+		HomographPolicy:  HomographWarn,
+		ProtectedDomains: []string{
+			"paypal.com",
+			"google.com",
+			"apple.com",
+			"microsoft.com",
+			"amazon.com",
+			"facebook.com",
+		},
 	}
 }
 
 func (v *URLValidator) ValidateURL(rawURL string) error {
+	return v.ValidateURLContext(context.Background(), rawURL)
+}
+
+func (v *URLValidator) ValidateURLContext(ctx context.Context, rawURL string) error {
 	if v.config.MaxURLLength > 0 && len(rawURL) > v.config.MaxURLLength {
 		return fmt.Errorf("URL exceeds maximum length of %d characters", v.config.MaxURLLength)
 	}
@@ -123,7 +163,7 @@ func (v *URLValidator) ValidateURL(rawURL string) error {
 		return err
 	}
 
-	if err := v.validateIPRestrictions(parsedURL.Host); err != nil {
+	if err := v.validateIPRestrictions(ctx, parsedURL.Host); err != nil {
 		return err
 	}
 
@@ -159,8 +199,49 @@ func (v *URLValidator) validateProtocol(u *url.URL) error {
 	return nil
 }
 
+func normalizeDomain(domain string) string {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	if ascii, err := homograph.ToASCII(domain); err == nil {
+		return ascii
+	}
+	return domain
+}
+
+func (v *URLValidator) AnalyzeHost(rawURL string) (homograph.Analysis, error) {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return homograph.Analysis{}, fmt.Errorf("invalid URL format: %w", err)
+	}
+	analysis, err := homograph.Analyze(parsedURL.Hostname(), v.config.ProtectedDomains)
+	if err != nil || v.config.HomographPolicy != HomographOff {
+		return analysis, err
+	}
+	return homograph.Analysis{ASCII: analysis.ASCII, Unicode: analysis.Unicode, IsIDN: analysis.IsIDN}, nil
+}
+
+func (v *URLValidator) checkHomograph(analysis homograph.Analysis) error {
+	if v.config.HomographPolicy != HomographBlock {
+		return nil
+	}
+	if analysis.ImitatedBrand != "" {
+		return fmt.Errorf("domain '%s' resembles protected domain '%s'", analysis.Unicode, analysis.ImitatedBrand)
+	}
+	if analysis.MixedScript {
+		return fmt.Errorf("domain '%s' mixes scripts (%s)", analysis.Unicode, strings.Join(analysis.Scripts, ", "))
+	}
+	return nil
+}
+
 func (v *URLValidator) validateDomain(u *url.URL) error {
-	domain := strings.ToLower(u.Hostname())
+	analysis, err := homograph.Analyze(u.Hostname(), v.config.ProtectedDomains)
+	if err != nil {
+		return err
+	}
+	domain := analysis.ASCII
+
+	if err := v.checkHomograph(analysis); err != nil {
+		return err
+	}
 
 	v.mu.RLock()
 	defer v.mu.RUnlock()
@@ -222,32 +303,77 @@ func (v *URLValidator) checkBlockedExtensions(path string) error {
 	return nil
 }
 
-func (v *URLValidator) validateIPRestrictions(host string) error {
+func (v *URLValidator) policy() netguard.Policy {
+	return netguard.Policy{
+		AllowLoopback: v.config.AllowLocalhost,
+		AllowPrivate:  v.config.AllowPrivateIPs,
+	}
+}
+
+func (v *URLValidator) validateIPRestrictions(ctx context.Context, host string) error {
 	hostname, _, _ := net.SplitHostPort(host)
 	if hostname == "" {
 		hostname = host
 	}
+	hostname = strings.TrimSuffix(strings.ToLower(hostname), ".")
+
+	if !v.config.AllowLocalhost && (hostname == "localhost" || strings.HasSuffix(hostname, ".localhost")) {
+		return fmt.Errorf("localhost URLs are not allowed")
+	}
 
-	ip := net.ParseIP(hostname)
-	if ip == nil {
+	if ip := netguard.ParseHostIP(hostname); ip != nil {
+		if err := v.checkIP(ip); err != nil {
+			return err
+		}
+		if !v.config.AllowPrivateIPs && !v.config.AllowLocalhost {
+			return fmt.Errorf("direct IP addresses are not allowed")
+		}
 		return nil
 	}
 
-	if !v.config.AllowLocalhost && (ip.IsLoopback() || hostname == "localhost") {
-		return fmt.Errorf("localhost URLs are not allowed")
+	if !v.config.ResolveHostnames {
+		return nil
 	}
 
-	if !v.config.AllowPrivateIPs && isPrivateIP(ip) {
-		return fmt.Errorf("private IP addresses are not allowed")
+	return v.validateResolvedIPs(ctx, hostname)
+}
+
+func (v *URLValidator) validateResolvedIPs(ctx context.Context, hostname string) error {
+	timeout := v.config.DNSTimeout
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	addrs, err := v.config.Resolver.LookupIPAddr(ctx, hostname)
+	if err != nil {
+		return fmt.Errorf("could not resolve host '%s'", hostname)
+	}
+	if len(addrs) == 0 {
+		return fmt.Errorf("host '%s' has no addresses", hostname)
 	}
 
-	if !v.config.AllowPrivateIPs && !v.config.AllowLocalhost {
-		return fmt.Errorf("direct IP addresses are not allowed")
+	for _, addr := range addrs {
+		if err := v.checkIP(addr.IP); err != nil {
+			return fmt.Errorf("host '%s' resolves to a disallowed address: %w", hostname, err)
+		}
 	}
 
 	return nil
 }
 
+func (v *URLValidator) checkIP(ip net.IP) error {
+	switch err := v.policy().Check(ip); err {
+	case nil:
+		return nil
+	case netguard.ErrLoopbackAddress:
+		return fmt.Errorf("localhost URLs are not allowed")
+	default:
+		return fmt.Errorf("private IP addresses are not allowed")
+	}
+}
+
 func (v *URLValidator) checkURLShortenerChain(host string) error {
 	knownShorteners := []string{
 		"bit.ly", "tinyurl.com", "goo.gl", "ow.ly", "is.gd",
@@ -314,25 +440,3 @@ func (v *URLValidator) RemoveFromBlacklist(domain string) {
 	defer v.mu.Unlock()
 	delete(v.blacklist, strings.ToLower(domain))
 }
-
-func isPrivateIP(ip net.IP) bool {
-	privateRanges := []string{
-		"10.0.0.0/8",
-		"172.16.0.0/12",
-		"192.168.0.0/16",
-		"fc00::/7",
-		"fe80::/10",
-	}
-
-	for _, cidr := range privateRanges {
-		_, network, err := net.ParseCIDR(cidr)
-		if err != nil {
-			continue
-		}
-		if network.Contains(ip) {
-			return true
-		}
-	}
-
-	return false
-}
@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+)
+
+type staticResolver []string
+
+func (r staticResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	addrs := make([]net.IPAddr, 0, len(r))
+	for _, ip := range r {
+		addrs = append(addrs, net.IPAddr{IP: net.ParseIP(ip)})
+	}
+	return addrs, nil
+}
+
+func newTestValidator(resolver staticResolver) *URLValidator {
+	config := DefaultConfig()
+	config.Resolver = resolver
+	return NewURLValidator(config)
+}
+
+func TestValidateURLChecksResolvedAddresses(t *testing.T) {
+	tests := []struct {
+		name     string
+		resolver staticResolver
+		wantErr  string
+	}{
+		{name: "public", resolver: staticResolver{"93.184.216.34"}},
+		{name: "private", resolver: staticResolver{"10.0.0.1"}, wantErr: "resolves to a disallowed address"},
+		{name: "metadata", resolver: staticResolver{"169.254.169.254"}, wantErr: "resolves to a disallowed address"},
+		{name: "loopback", resolver: staticResolver{"127.0.0.1"}, wantErr: "localhost URLs are not allowed"},
+		{name: "mixed", resolver: staticResolver{"93.184.216.34", "100.64.0.1"}, wantErr: "resolves to a disallowed address"},
+		{name: "mapped", resolver: staticResolver{"::ffff:10.0.0.1"}, wantErr: "resolves to a disallowed address"},
+		{name: "empty", resolver: staticResolver{}, wantErr: "has no addresses"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newTestValidator(tt.resolver).ValidateURLContext(context.Background(), "https://internal.example.com/path")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("ValidateURLContext = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ValidateURLContext = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateURLRejectsObfuscatedIPs(t *testing.T) {
+	validator := newTestValidator(staticResolver{"93.184.216.34"})
+
+	for _, raw := range []string{
+		"http://0x7f.1/",
+		"http://2130706433/",
+		"http://0177.0.0.1/",
+		"http://[::ffff:169.254.169.254]/",
+		"http://[::1]:8080/",
+		"http://localhost/",
+	} {
+		if err := validator.ValidateURLContext(context.Background(), raw); err == nil {
+			t.Fatalf("ValidateURLContext(%q) = nil, want an error", raw)
+		}
+	}
+}
+
+func TestHomographPolicy(t *testing.T) {
+	tests := []struct {
+		policy         HomographPolicy
+		host           string
+		wantErr        string
+		wantSuspicious bool
+	}{
+		{policy: HomographBlock, host: "раураl.com", wantErr: "resembles protected domain 'paypal.com'"},
+		{policy: HomographBlock, host: "xn--l-7sba6dbr.com", wantErr: "resembles protected domain 'paypal.com'"},
+		{policy: HomographBlock, host: "раураl-shop.net", wantErr: "mixes scripts"},
+		{policy: HomographBlock, host: "пример.рф"},
+		{policy: HomographWarn, host: "раураl.com", wantSuspicious: true},
+		{policy: HomographWarn, host: "пример.рф"},
+		{policy: HomographOff, host: "раураl.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy)+"/"+tt.host, func(t *testing.T) {
+			config := DefaultConfig()
+			config.Resolver = staticResolver{"93.184.216.34"}
+			config.HomographPolicy = tt.policy
+			validator := NewURLValidator(config)
+			raw := "https://" + tt.host + "/login"
+
+			err := validator.ValidateURLContext(context.Background(), raw)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("ValidateURLContext(%q) = %v, want nil", raw, err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("ValidateURLContext(%q) = %v, want %q", raw, err, tt.wantErr)
+			}
+
+			analysis, err := validator.AnalyzeHost(raw)
+			if err != nil {
+				t.Fatalf("AnalyzeHost(%q): %v", raw, err)
+			}
+			if tt.policy != HomographBlock && analysis.Suspicious() != tt.wantSuspicious {
+				t.Fatalf("AnalyzeHost(%q).Suspicious() = %v, want %v", raw, analysis.Suspicious(), tt.wantSuspicious)
+			}
+		})
+	}
+}
@@ -20,6 +20,18 @@ type DailyAnalytics struct {
 	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
 }
 
+type ClickEvent struct {
+	UserID      string
+	WorkspaceID string
+	URLID       string
+	Referrer    string
+	DeviceType  string
+	OS          string
+	Country     string
+	MatchedRule string
+	Variant     string
+}
+
 type UserAnalyticsSummary struct {
 	TotalURLs       int64             `json:"total_urls"`
 	TotalClicks     int64             `json:"total_clicks"`
@@ -33,21 +45,24 @@ type UserAnalyticsSummary struct {
 }
 
 type URLClickStats struct {
-	URLID       string `json:"url_id"`
-	ShortCode   string `json:"short_code"`
-	OriginalURL string `json:"original_url"`
-	ClickCount  int64  `json:"click_count"`
-	CreatedAt   string `json:"created_at"`
+	URLID         string `json:"url_id"`
+	ShortCode     string `json:"short_code"`
+	OriginalURL   string `json:"original_url"`
+	NormalizedURL string `json:"normalized_url,omitempty"`
+	ClickCount    int64  `json:"click_count"`
+	CreatedAt     string `json:"created_at"`
 }
 
 func (u *URLClickStats) UnmarshalJSON(data []byte) error {
 
 	var temp struct {
-		ID          string `json:"id"`
-		ShortCode   string `json:"short_code"`
-		OriginalURL string `json:"original_url"`
-		ClickCount  int64  `json:"click_count"`
-		CreatedAt   string `json:"created_at"`
+		ID            string `json:"id"`
+		URLID         string `json:"url_id"`
+		ShortCode     string `json:"short_code"`
+		OriginalURL   string `json:"original_url"`
+		NormalizedURL string `json:"normalized_url"`
+		ClickCount    int64  `json:"click_count"`
+		CreatedAt     string `json:"created_at"`
 	}
 
 	if err := json.Unmarshal(data, &temp); err != nil {
@@ -55,8 +70,12 @@ func (u *URLClickStats) UnmarshalJSON(data []byte) error {
 	}
 
 	u.URLID = temp.ID
+	if u.URLID == "" {
+		u.URLID = temp.URLID
+	}
 	u.ShortCode = temp.ShortCode
 	u.OriginalURL = temp.OriginalURL
+	u.NormalizedURL = temp.NormalizedURL
 	u.ClickCount = temp.ClickCount
 	u.CreatedAt = temp.CreatedAt
 
@@ -0,0 +1,129 @@
+package model
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const MaxDeepLinkLength = 2048
+
+var blockedAppSchemes = map[string]bool{
+	"javascript": true,
+	"data":       true,
+	"vbscript":   true,
+	"file":       true,
+	"blob":       true,
+	"about":      true,
+	"http":       true,
+}
+
+type AppTarget struct {
+	URL      string `json:"url"`
+	Fallback string `json:"fallback,omitempty"`
+}
+
+type DeepLinks struct {
+	IOS     *AppTarget `json:"ios,omitempty"`
+	Android *AppTarget `json:"android,omitempty"`
+}
+
+func (d *DeepLinks) IsEmpty() bool {
+	return d == nil || (d.IOS == nil && d.Android == nil)
+}
+
+func (d *DeepLinks) For(os string) *AppTarget {
+	if d == nil {
+		return nil
+	}
+	switch os {
+	case OSiOS:
+		return d.IOS
+	case OSAndroid:
+		return d.Android
+	}
+	return nil
+}
+
+func (t AppTarget) IsWeb() bool {
+	return strings.HasPrefix(strings.ToLower(t.URL), "https://")
+}
+
+func NormalizeDeepLinks(d *DeepLinks) (*DeepLinks, error) {
+	if d == nil {
+		return nil, nil
+	}
+	ios, err := normalizeAppTarget("ios", d.IOS, false)
+	if err != nil {
+		return nil, err
+	}
+	android, err := normalizeAppTarget("android", d.Android, true)
+	if err != nil {
+		return nil, err
+	}
+	out := &DeepLinks{IOS: ios, Android: android}
+	if out.IsEmpty() {
+		return nil, nil
+	}
+	return out, nil
+}
+
+func normalizeAppTarget(platform string, t *AppTarget, allowIntent bool) (*AppTarget, error) {
+	if t == nil {
+		return nil, nil
+	}
+	target := AppTarget{
+		URL:      strings.TrimSpace(t.URL),
+		Fallback: strings.TrimSpace(t.Fallback),
+	}
+	if target.URL == "" {
+		return nil, fmt.Errorf("deep_links.%s.url is required", platform)
+	}
+	if len(target.URL) > MaxDeepLinkLength || len(target.Fallback) > MaxDeepLinkLength {
+		return nil, fmt.Errorf("deep_links.%s: URLs must be at most %d characters", platform, MaxDeepLinkLength)
+	}
+
+	parsed, err := url.Parse(target.URL)
+	if err != nil || parsed.Scheme == "" {
+		return nil, fmt.Errorf("deep_links.%s.url must be an absolute URL", platform)
+	}
+	scheme := strings.ToLower(parsed.Scheme)
+	if blockedAppSchemes[scheme] {
+		return nil, fmt.Errorf("deep_links.%s.url must use https or an app scheme", platform)
+	}
+	if scheme == "intent" {
+		if !allowIntent {
+			return nil, fmt.Errorf("deep_links.%s.url cannot be an intent URL", platform)
+		}
+		if !strings.Contains(target.URL, "#Intent;") || !strings.HasSuffix(target.URL, ";end") {
+			return nil, fmt.Errorf("deep_links.%s.url must be of the form intent://...#Intent;...;end", platform)
+		}
+	}
+
+	if target.Fallback != "" {
+		fallback, err := url.Parse(target.Fallback)
+		if err != nil || (fallback.Scheme != "http" && fallback.Scheme != "https") || fallback.Host == "" {
+			return nil, fmt.Errorf("deep_links.%s.fallback must be an http(s) URL", platform)
+		}
+	}
+	return &target, nil
+}
+
+func (d *DeepLinks) WebURLs() []string {
+	if d == nil {
+		return nil
+	}
+	var urls []string
+	for _, t := range []*AppTarget{d.IOS, d.Android} {
+		if t == nil {
+			continue
+		}
+		if t.IsWeb() {
+			urls = append(urls, t.URL)
+		}
+		if t.Fallback != "" {
+			urls = append(urls, t.Fallback)
+		}
+	}
+	return urls
+}
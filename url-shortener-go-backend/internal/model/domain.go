@@ -0,0 +1,46 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	DomainVerificationPrefix = "_shortlink-verify."
+	DomainVerificationValue  = "shortlink-verify="
+)
+
+type Domain struct {
+	ID                string     `json:"id"`
+	UserID            string     `json:"user_id"`
+	Hostname          string     `json:"hostname"`
+	VerificationToken string     `json:"verification_token"`
+	VerifiedAt        *time.Time `json:"verified_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+func (d *Domain) IsVerified() bool {
+	return d.VerifiedAt != nil
+}
+
+func (d *Domain) TXTRecordName() string {
+	return DomainVerificationPrefix + d.Hostname
+}
+
+func (d *Domain) TXTRecordValue() string {
+	return DomainVerificationValue + d.VerificationToken
+}
+
+func LinkKey(domain, code string) string {
+	if domain == "" {
+		return code
+	}
+	return domain + "/" + code
+}
+
+func SplitLinkKey(key string) (domain, code string) {
+	if i := strings.LastIndex(key, "/"); i >= 0 {
+		return key[:i], key[i+1:]
+	}
+	return "", key
+}
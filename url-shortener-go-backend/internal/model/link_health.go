@@ -0,0 +1,72 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	HealthHealthy = "healthy"
+	HealthFailing = "failing"
+	HealthBroken  = "broken"
+
+	MaxHealthHistory = 20
+)
+
+type HealthCheck struct {
+	CheckedAt  time.Time `json:"checked_at"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	LatencyMS  int64     `json:"latency_ms"`
+	Healthy    bool      `json:"healthy"`
+}
+
+type HealthPolicy struct {
+	Interval         time.Duration
+	MaxBackoff       time.Duration
+	FailureThreshold int
+}
+
+type LinkHealth struct {
+	Status      string
+	Failures    int
+	CheckedAt   time.Time
+	NextCheckAt time.Time
+	History     []HealthCheck
+}
+
+func ValidateHealthStatus(status string) error {
+	switch status {
+	case "", HealthHealthy, HealthFailing, HealthBroken:
+		return nil
+	default:
+		return fmt.Errorf("health must be %q, %q or %q", HealthHealthy, HealthFailing, HealthBroken)
+	}
+}
+
+func (u *URL) NextHealth(check HealthCheck, p HealthPolicy) LinkHealth {
+	h := LinkHealth{Status: HealthHealthy, CheckedAt: check.CheckedAt}
+
+	delay := p.Interval
+	if !check.Healthy {
+		h.Failures = u.HealthFailures + 1
+		h.Status = HealthFailing
+		if h.Failures >= p.FailureThreshold {
+			h.Status = HealthBroken
+		}
+		for i := 1; i < h.Failures && delay < p.MaxBackoff; i++ {
+			delay *= 2
+		}
+		if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+			delay = p.MaxBackoff
+		}
+	}
+	h.NextCheckAt = check.CheckedAt.Add(delay)
+
+	h.History = append(h.History, u.HealthHistory...)
+	h.History = append(h.History, check)
+	if len(h.History) > MaxHealthHistory {
+		h.History = h.History[len(h.History)-MaxHealthHistory:]
+	}
+	return h
+}
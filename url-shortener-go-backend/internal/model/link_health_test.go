@@ -0,0 +1,54 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextHealthBackoff(t *testing.T) {
+	policy := HealthPolicy{Interval: 24 * time.Hour, MaxBackoff: 7 * 24 * time.Hour, FailureThreshold: 3}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		failures   int
+		wantStatus string
+		wantDelay  time.Duration
+	}{
+		{failures: 1, wantStatus: HealthFailing, wantDelay: 24 * time.Hour},
+		{failures: 2, wantStatus: HealthFailing, wantDelay: 48 * time.Hour},
+		{failures: 3, wantStatus: HealthBroken, wantDelay: 96 * time.Hour},
+		{failures: 4, wantStatus: HealthBroken, wantDelay: 7 * 24 * time.Hour},
+		{failures: 10, wantStatus: HealthBroken, wantDelay: 7 * 24 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		u := &URL{HealthFailures: tt.failures - 1}
+		h := u.NextHealth(HealthCheck{CheckedAt: start, StatusCode: 503}, policy)
+		if h.Failures != tt.failures || h.Status != tt.wantStatus {
+			t.Fatalf("failure %d: status=%s failures=%d, want %s", tt.failures, h.Status, h.Failures, tt.wantStatus)
+		}
+		if got := h.NextCheckAt.Sub(start); got != tt.wantDelay {
+			t.Fatalf("failure %d: next check in %s, want %s", tt.failures, got, tt.wantDelay)
+		}
+	}
+}
+
+func TestNextHealthRecovers(t *testing.T) {
+	policy := HealthPolicy{Interval: time.Hour, MaxBackoff: 24 * time.Hour, FailureThreshold: 3}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	u := &URL{HealthStatus: HealthBroken, HealthFailures: 5}
+	h := u.NextHealth(HealthCheck{CheckedAt: now, StatusCode: 200, Healthy: true}, policy)
+	if h.Status != HealthHealthy || h.Failures != 0 || !h.NextCheckAt.Equal(now.Add(time.Hour)) {
+		t.Fatalf("NextHealth after recovery = %+v", h)
+	}
+}
+
+func TestNextHealthTrimsHistory(t *testing.T) {
+	u := &URL{HealthHistory: make([]HealthCheck, MaxHealthHistory)}
+	last := HealthCheck{CheckedAt: time.Now(), StatusCode: 200, Healthy: true}
+	h := u.NextHealth(last, HealthPolicy{Interval: time.Hour, FailureThreshold: 1})
+	if len(h.History) != MaxHealthHistory || h.History[len(h.History)-1] != last {
+		t.Fatalf("history length = %d, want %d ending with the latest probe", len(h.History), MaxHealthHistory)
+	}
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+const MaxCursorLength = 512
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+type PageCursor struct {
+	Sort  string `json:"s,omitempty"`
+	Value string `json:"v"`
+	ID    string `json:"k"`
+}
+
+func (c PageCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func (c *PageCursor) String() string {
+	if c == nil {
+		return ""
+	}
+	return c.Encode()
+}
+
+func DecodePageCursor(raw string) (*PageCursor, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	if len(raw) > MaxCursorLength {
+		return nil, ErrInvalidCursor
+	}
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c PageCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Value == "" || c.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
@@ -0,0 +1,114 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	DeviceDesktop = "desktop"
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceBot     = "bot"
+	DeviceUnknown = "unknown"
+
+	OSiOS      = "ios"
+	OSAndroid  = "android"
+	OSWindows  = "windows"
+	OSMacOS    = "macos"
+	OSLinux    = "linux"
+	OSChromeOS = "chromeos"
+
+	MaxRedirectRules = 20
+)
+
+var (
+	RuleDevices = map[string]bool{
+		DeviceDesktop: true,
+		DeviceMobile:  true,
+		DeviceTablet:  true,
+		DeviceBot:     true,
+	}
+
+	RuleOperatingSystems = map[string]bool{
+		OSiOS:      true,
+		OSAndroid:  true,
+		OSWindows:  true,
+		OSMacOS:    true,
+		OSLinux:    true,
+		OSChromeOS: true,
+	}
+)
+
+type RedirectRule struct {
+	Name        string     `json:"name,omitempty"`
+	Devices     []string   `json:"devices,omitempty"`
+	OS          []string   `json:"os,omitempty"`
+	Countries   []string   `json:"countries,omitempty"`
+	Languages   []string   `json:"languages,omitempty"`
+	StartsAt    *time.Time `json:"starts_at,omitempty"`
+	EndsAt      *time.Time `json:"ends_at,omitempty"`
+	Destination string     `json:"destination"`
+}
+
+func (r RedirectRule) Label(index int) string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return fmt.Sprintf("rule_%d", index+1)
+}
+
+func NormalizeRules(rules []RedirectRule) ([]RedirectRule, error) {
+	if len(rules) > MaxRedirectRules {
+		return nil, fmt.Errorf("at most %d rules are allowed", MaxRedirectRules)
+	}
+
+	out := make([]RedirectRule, 0, len(rules))
+	for i, rule := range rules {
+		rule.Name = strings.TrimSpace(rule.Name)
+		rule.Destination = strings.TrimSpace(rule.Destination)
+		if rule.Destination == "" {
+			return nil, fmt.Errorf("rule %d: destination is required", i+1)
+		}
+
+		for j, d := range rule.Devices {
+			d = strings.ToLower(strings.TrimSpace(d))
+			if !RuleDevices[d] {
+				return nil, fmt.Errorf("rule %d: unknown device %q", i+1, d)
+			}
+			rule.Devices[j] = d
+		}
+
+		for j, os := range rule.OS {
+			os = strings.ToLower(strings.TrimSpace(os))
+			if !RuleOperatingSystems[os] {
+				return nil, fmt.Errorf("rule %d: unknown os %q", i+1, os)
+			}
+			rule.OS[j] = os
+		}
+
+		for j, c := range rule.Countries {
+			c = strings.ToUpper(strings.TrimSpace(c))
+			if len(c) != 2 || c[0] < 'A' || c[0] > 'Z' || c[1] < 'A' || c[1] > 'Z' {
+				return nil, fmt.Errorf("rule %d: country %q must be an ISO 3166-1 alpha-2 code", i+1, c)
+			}
+			rule.Countries[j] = c
+		}
+
+		for j, l := range rule.Languages {
+			l = strings.ToLower(strings.TrimSpace(l))
+			if l == "" || len(l) > 35 {
+				return nil, fmt.Errorf("rule %d: invalid language %q", i+1, l)
+			}
+			rule.Languages[j] = l
+		}
+
+		if rule.StartsAt != nil && rule.EndsAt != nil && !rule.EndsAt.After(*rule.StartsAt) {
+			return nil, fmt.Errorf("rule %d: ends_at must be after starts_at", i+1)
+		}
+
+		out = append(out, rule)
+	}
+	return out, nil
+}
@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+const (
+	ReportStatusOpen      = "open"
+	ReportStatusActioned  = "actioned"
+	ReportStatusDismissed = "dismissed"
+
+	ReportReasonSpam     = "spam"
+	ReportReasonPhishing = "phishing"
+	ReportReasonMalware  = "malware"
+	ReportReasonIllegal  = "illegal"
+	ReportReasonOther    = "other"
+)
+
+var ReportReasons = map[string]bool{
+	ReportReasonSpam:     true,
+	ReportReasonPhishing: true,
+	ReportReasonMalware:  true,
+	ReportReasonIllegal:  true,
+	ReportReasonOther:    true,
+}
+
+type Report struct {
+	ID             string     `json:"id"`
+	URLID          string     `json:"url_id"`
+	ShortCode      string     `json:"short_code"`
+	Reason         string     `json:"reason"`
+	Details        string     `json:"details,omitempty"`
+	ReporterIPHash string     `json:"reporter_ip_hash,omitempty"`
+	ReporterUserID *string    `json:"reporter_user_id,omitempty"`
+	Status         string     `json:"status"`
+	Resolution     string     `json:"resolution,omitempty"`
+	ResolvedBy     *string    `json:"resolved_by,omitempty"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
@@ -2,19 +2,211 @@ package model
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
+const (
+	DefaultRedirectStatus = http.StatusFound
+	MaxCacheMaxAge        = 365 * 24 * 60 * 60
+	MaxTags               = 20
+	MaxTagLength          = 32
+	MaxFolderLength       = 100
+)
+
+const (
+	DedupReuse         = "reuse"
+	DedupAlwaysNew     = "always_new"
+	DefaultDedupPolicy = DedupReuse
+)
+
+const (
+	ForwardQueryOff      = ""
+	ForwardQueryPreserve = "preserve"
+	ForwardQueryOverride = "override"
+)
+
+var ReservedQueryParams = map[string]bool{
+	"preview":  true,
+	"continue": true,
+}
+
+var RedirectStatuses = map[int]bool{
+	http.StatusMovedPermanently:  true,
+	http.StatusFound:             true,
+	http.StatusTemporaryRedirect: true,
+	http.StatusPermanentRedirect: true,
+}
+
 type URL struct {
-	ID          string    `json:"id"`
-	UserID      *string   `json:"user_id,omitempty"`
-	OriginalURL string    `json:"original_url"`
-	ShortCode   string    `json:"short_code"`
-	IsPublic    bool      `json:"is_public"`
-	ClickCount  int       `json:"click_count"`
-	CreatedAt   time.Time `json:"created_at"`
-	ShortURL    string    `json:"short_url"`
+	ID              string     `json:"id"`
+	UserID          *string    `json:"user_id,omitempty"`
+	WorkspaceID     *string    `json:"workspace_id,omitempty"`
+	OriginalURL     string     `json:"original_url"`
+	NormalizedURL   string     `json:"normalized_url,omitempty"`
+	DestinationHash string     `json:"destination_hash,omitempty"`
+	ShortCode       string     `json:"short_code"`
+	IsPublic        bool       `json:"is_public"`
+	ClickCount      int        `json:"click_count"`
+	CreatedAt       time.Time  `json:"created_at"`
+	ShortURL        string     `json:"short_url"`
+	Domain          string     `json:"domain,omitempty"`
+	BaseURL         string     `json:"base_url,omitempty"`
+	DisabledAt      *time.Time `json:"disabled_at,omitempty"`
+	DisabledReason  string     `json:"disabled_reason,omitempty"`
+	ResolvedURL     string     `json:"resolved_url,omitempty"`
+	ResolvedStatus  int        `json:"resolved_status,omitempty"`
+	RequirePreview  bool       `json:"require_preview"`
+
+	Title             string     `json:"title,omitempty"`
+	Description       string     `json:"description,omitempty"`
+	ImageURL          string     `json:"image_url,omitempty"`
+	FaviconURL        string     `json:"favicon_url,omitempty"`
+	MetadataFetchedAt *time.Time `json:"metadata_fetched_at,omitempty"`
+
+	OGTitle       string `json:"og_title,omitempty"`
+	OGDescription string `json:"og_description,omitempty"`
+	OGImage       string `json:"og_image,omitempty"`
+
+	RedirectStatus int  `json:"redirect_status,omitempty"`
+	CacheMaxAge    int  `json:"cache_max_age,omitempty"`
+	NoStore        bool `json:"no_store"`
+
+	ForwardQuery string `json:"forward_query,omitempty"`
+
+	Rules    []RedirectRule `json:"rules,omitempty"`
+	Variants []Variant      `json:"variants,omitempty"`
+
+	DeepLinks *DeepLinks `json:"deep_links,omitempty"`
+
+	Tags      []string   `json:"tags,omitempty"`
+	Folder    string     `json:"folder,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	HealthStatus      string        `json:"health_status,omitempty"`
+	HealthFailures    int           `json:"health_failures,omitempty"`
+	HealthCheckedAt   *time.Time    `json:"health_checked_at,omitempty"`
+	HealthNextCheckAt *time.Time    `json:"health_next_check_at,omitempty"`
+	HealthHistory     []HealthCheck `json:"health_history,omitempty"`
+}
+
+type CreateURLInput struct {
+	OriginalURL    string
+	IsPublic       bool
+	UserID         *string
+	WorkspaceID    *string
+	CodeLength     int
+	CodeStyle      string
+	Alias          string
+	Domain         string
+	ResolvedURL    string
+	ResolvedStatus int
+	RequirePreview bool
+	RedirectStatus int
+	CacheMaxAge    int
+	NoStore        bool
+	ForwardQuery   string
+	Rules          []RedirectRule
+	Variants       []Variant
+	DeepLinks      *DeepLinks
+	Tags           []string
+	Folder         string
+	ExpiresAt      *time.Time
+	Dedup          string
+}
+
+func (in CreateURLInput) HasLinkOptions() bool {
+	return in.RequirePreview || in.RedirectStatus != 0 || in.CacheMaxAge != 0 || in.NoStore ||
+		in.ForwardQuery != ForwardQueryOff || len(in.Rules) > 0 || len(in.Variants) > 0 ||
+		in.DeepLinks != nil || len(in.Tags) > 0 || in.Folder != "" || in.ExpiresAt != nil
+}
+
+type URLUpdate struct {
+	RequirePreview *bool
+	OGTitle        *string
+	OGDescription  *string
+	OGImage        *string
+	RedirectStatus *int
+	CacheMaxAge    *int
+	NoStore        *bool
+	ForwardQuery   *string
+	Rules          *[]RedirectRule
+	Variants       *[]Variant
+	DeepLinks      *DeepLinks
+	Tags           *[]string
+	Folder         *string
+	ExpiresAt      *time.Time
+	ClearExpiry    bool
+}
+
+func (u URLUpdate) IsEmpty() bool {
+	return u.RequirePreview == nil && u.OGTitle == nil && u.OGDescription == nil && u.OGImage == nil &&
+		u.RedirectStatus == nil && u.CacheMaxAge == nil && u.NoStore == nil && u.ForwardQuery == nil &&
+		u.Rules == nil && u.Variants == nil && u.DeepLinks == nil &&
+		u.Tags == nil && u.Folder == nil && u.ExpiresAt == nil && !u.ClearExpiry
+}
+
+func ValidateDedupPolicy(policy string) error {
+	switch policy {
+	case "", DedupReuse, DedupAlwaysNew:
+		return nil
+	}
+	return fmt.Errorf("dedup must be %q or %q", DedupReuse, DedupAlwaysNew)
+}
+
+func ValidateForwardQuery(mode string) error {
+	switch mode {
+	case ForwardQueryOff, ForwardQueryPreserve, ForwardQueryOverride:
+		return nil
+	}
+	return fmt.Errorf("forward_query must be empty, %q or %q", ForwardQueryPreserve, ForwardQueryOverride)
+}
+
+func ValidateRedirectOptions(status, maxAge int) error {
+	if status != 0 && !RedirectStatuses[status] {
+		return fmt.Errorf("redirect_status must be one of 301, 302, 307, 308")
+	}
+	if maxAge < 0 || maxAge > MaxCacheMaxAge {
+		return fmt.Errorf("cache_max_age must be between 0 and %d seconds", MaxCacheMaxAge)
+	}
+	return nil
+}
+
+func NormalizeTags(raw []string) ([]string, error) {
+	tags := make([]string, 0, len(raw))
+	seen := make(map[string]bool, len(raw))
+	for _, t := range raw {
+		tag := strings.ToLower(strings.Join(strings.Fields(t), " "))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > MaxTagLength || strings.ContainsAny(tag, ",{}\"") {
+			return nil, fmt.Errorf("tags must be at most %d characters and must not contain commas, braces or quotes", MaxTagLength)
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	if len(tags) > MaxTags {
+		return nil, fmt.Errorf("a link can have at most %d tags", MaxTags)
+	}
+	return tags, nil
+}
+
+func NormalizeFolder(raw string) (string, error) {
+	folder := strings.Trim(strings.Join(strings.Fields(raw), " "), "/")
+	if utf8.RuneCountInString(folder) > MaxFolderLength {
+		return "", fmt.Errorf("folder must be at most %d characters", MaxFolderLength)
+	}
+	return folder, nil
+}
+
+func ValidateExpiry(expiresAt *time.Time, now time.Time) error {
+	if expiresAt != nil && !expiresAt.After(now) {
+		return fmt.Errorf("expires_at must be in the future")
+	}
+	return nil
 }
 
 type URLSubset struct {
@@ -28,5 +220,60 @@ func (u *URL) PopulateShortURL(baseDomain string) {
 	if baseDomain == "" {
 		baseDomain = "http://localhost:8080"
 	}
-	u.ShortURL = fmt.Sprintf("%s/%s", strings.TrimSuffix(baseDomain, "/"), u.ShortCode)
+	baseDomain = strings.TrimSuffix(baseDomain, "/")
+	u.BaseURL = baseDomain
+	if u.Domain == "" {
+		u.ShortURL = fmt.Sprintf("%s/%s", baseDomain, u.ShortCode)
+		return
+	}
+	scheme := "https"
+	if strings.HasPrefix(baseDomain, "http://") {
+		scheme = "http"
+	}
+	u.ShortURL = fmt.Sprintf("%s://%s/%s", scheme, u.Domain, u.ShortCode)
+}
+
+func (u *URL) Key() string {
+	return LinkKey(u.Domain, u.ShortCode)
+}
+
+func (u *URL) HasOGOverrides() bool {
+	return u.OGTitle != "" || u.OGDescription != "" || u.OGImage != ""
+}
+
+func (u *URL) EffectiveRedirectStatus() int {
+	if RedirectStatuses[u.RedirectStatus] {
+		return u.RedirectStatus
+	}
+	return DefaultRedirectStatus
+}
+
+func (u *URL) CacheControl() string {
+	switch {
+	case u.NoStore:
+		return "no-store"
+	case u.CacheMaxAge > 0:
+		return fmt.Sprintf("private, max-age=%d", u.CacheMaxAge)
+	default:
+		return ""
+	}
+}
+
+func (u *URL) Destinations() []string {
+	destinations := []string{u.OriginalURL}
+	for _, rule := range u.Rules {
+		destinations = append(destinations, rule.Destination)
+	}
+	for _, v := range u.Variants {
+		destinations = append(destinations, v.Destination)
+	}
+	return append(destinations, u.DeepLinks.WebURLs()...)
+}
+
+func (u *URL) IsDisabled() bool {
+	return u.DisabledAt != nil
+}
+
+func (u *URL) IsExpired(now time.Time) bool {
+	return u.ExpiresAt != nil && !u.ExpiresAt.After(now)
 }
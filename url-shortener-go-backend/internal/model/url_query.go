@@ -0,0 +1,98 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	URLSortCreated = "created"
+	URLSortClicks  = "clicks"
+
+	VisibilityPublic  = "public"
+	VisibilityPrivate = "private"
+
+	DefaultURLListLimit = 100
+	MaxURLListLimit     = 500
+	MaxURLSearchLength  = 200
+)
+
+type URLQuery struct {
+	Search      string
+	Tags        []string
+	Folder      string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Visibility  string
+	Expired     *bool
+	Health      string
+	Sort        string
+	Ascending   bool
+	Limit       int
+	Cursor      *PageCursor
+}
+
+func (q URLQuery) HasFilters() bool {
+	return q.Search != "" || len(q.Tags) > 0 || q.Folder != "" || q.CreatedFrom != nil || q.CreatedTo != nil ||
+		q.Visibility != "" || q.Expired != nil || q.Health != ""
+}
+
+func (q URLQuery) Validate() error {
+	if utf8.RuneCountInString(q.Search) > MaxURLSearchLength {
+		return fmt.Errorf("q must be at most %d characters", MaxURLSearchLength)
+	}
+	switch q.Sort {
+	case "", URLSortCreated, URLSortClicks:
+	default:
+		return fmt.Errorf("sort must be %q or %q", URLSortCreated, URLSortClicks)
+	}
+	switch q.Visibility {
+	case "", VisibilityPublic, VisibilityPrivate:
+	default:
+		return fmt.Errorf("visibility must be %q or %q", VisibilityPublic, VisibilityPrivate)
+	}
+	if err := ValidateHealthStatus(q.Health); err != nil {
+		return err
+	}
+	if q.CreatedFrom != nil && q.CreatedTo != nil && q.CreatedTo.Before(*q.CreatedFrom) {
+		return fmt.Errorf("created_to must not be before created_from")
+	}
+	if q.Limit < 0 || q.Limit > MaxURLListLimit {
+		return fmt.Errorf("limit must be between 1 and %d", MaxURLListLimit)
+	}
+	if q.Cursor != nil && q.Cursor.Sort != q.SortKey() {
+		return fmt.Errorf("cursor does not match sort and order")
+	}
+	return nil
+}
+
+func (q URLQuery) SortKey() string {
+	sort := q.Sort
+	if sort == "" {
+		sort = URLSortCreated
+	}
+	if q.Ascending {
+		return sort + ":asc"
+	}
+	return sort + ":desc"
+}
+
+func (q URLQuery) EffectiveLimit() int {
+	if q.Limit <= 0 {
+		return DefaultURLListLimit
+	}
+	return q.Limit
+}
+
+func (q URLQuery) SortColumn() string {
+	if q.Sort == URLSortClicks {
+		return "click_count"
+	}
+	return "created_at"
+}
+
+func NormalizeSearch(raw string) string {
+	return strings.Join(strings.Fields(raw), " ")
+}
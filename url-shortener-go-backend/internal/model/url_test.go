@@ -0,0 +1,37 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCreateURLInputHasLinkOptions(t *testing.T) {
+	expires := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name  string
+		input CreateURLInput
+		want  bool
+	}{
+		{name: "plain", input: CreateURLInput{OriginalURL: "https://example.com", IsPublic: true, Domain: "go.example.com", Dedup: DedupReuse}},
+		{name: "rules", input: CreateURLInput{Rules: []RedirectRule{{Destination: "https://example.com/de"}}}, want: true},
+		{name: "variants", input: CreateURLInput{Variants: []Variant{{Destination: "https://example.com/b"}}}, want: true},
+		{name: "deep links", input: CreateURLInput{DeepLinks: &DeepLinks{}}, want: true},
+		{name: "expires at", input: CreateURLInput{ExpiresAt: &expires}, want: true},
+		{name: "require preview", input: CreateURLInput{RequirePreview: true}, want: true},
+		{name: "redirect status", input: CreateURLInput{RedirectStatus: 301}, want: true},
+		{name: "cache max age", input: CreateURLInput{CacheMaxAge: 60}, want: true},
+		{name: "no store", input: CreateURLInput{NoStore: true}, want: true},
+		{name: "forward query", input: CreateURLInput{ForwardQuery: ForwardQueryPreserve}, want: true},
+		{name: "tags", input: CreateURLInput{Tags: []string{"launch"}}, want: true},
+		{name: "folder", input: CreateURLInput{Folder: "campaigns"}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.input.HasLinkOptions(); got != tt.want {
+				t.Fatalf("HasLinkOptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	MaxVariants      = 10
+	MaxVariantWeight = 1000
+	MaxVariantName   = 50
+)
+
+type Variant struct {
+	Name        string `json:"name"`
+	Destination string `json:"destination"`
+	Weight      int    `json:"weight"`
+}
+
+type VariantStats struct {
+	Variant     string  `json:"variant"`
+	Destination string  `json:"destination,omitempty"`
+	Weight      int     `json:"weight,omitempty"`
+	Clicks      int64   `json:"clicks"`
+	Share       float64 `json:"share"`
+}
+
+func NormalizeVariants(variants []Variant) ([]Variant, error) {
+	if len(variants) == 0 {
+		return nil, nil
+	}
+	if len(variants) < 2 || len(variants) > MaxVariants {
+		return nil, fmt.Errorf("between 2 and %d variants are required", MaxVariants)
+	}
+
+	seen := make(map[string]bool, len(variants))
+	out := make([]Variant, 0, len(variants))
+	for i, v := range variants {
+		v.Name = strings.TrimSpace(v.Name)
+		v.Destination = strings.TrimSpace(v.Destination)
+		if v.Name == "" {
+			v.Name = fmt.Sprintf("v%d", i+1)
+		}
+		if len(v.Name) > MaxVariantName {
+			return nil, fmt.Errorf("variant %d: name must be at most %d characters", i+1, MaxVariantName)
+		}
+		if seen[v.Name] {
+			return nil, fmt.Errorf("variant %d: duplicate name %q", i+1, v.Name)
+		}
+		seen[v.Name] = true
+		if v.Destination == "" {
+			return nil, fmt.Errorf("variant %d: destination is required", i+1)
+		}
+		if v.Weight == 0 {
+			v.Weight = 1
+		}
+		if v.Weight < 0 || v.Weight > MaxVariantWeight {
+			return nil, fmt.Errorf("variant %d: weight must be between 1 and %d", i+1, MaxVariantWeight)
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
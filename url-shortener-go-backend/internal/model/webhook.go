@@ -0,0 +1,221 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	EventLinkCreated = "link.created"
+	EventLinkUpdated = "link.updated"
+	EventLinkDeleted = "link.deleted"
+	EventLinkClicked = "link.clicked"
+	EventLinkExpired = "link.expired"
+
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+
+	MaxWebhooksPerWorkspace = 10
+	MaxWebhookDescription   = 200
+)
+
+var WebhookEventTypes = []string{
+	EventLinkCreated,
+	EventLinkUpdated,
+	EventLinkDeleted,
+	EventLinkClicked,
+	EventLinkExpired,
+}
+
+type Webhook struct {
+	ID          string    `json:"id"`
+	WorkspaceID string    `json:"workspace_id"`
+	URL         string    `json:"url"`
+	Description string    `json:"description,omitempty"`
+	Secret      string    `json:"-"`
+	Events      []string  `json:"events"`
+	Active      bool      `json:"active"`
+	CreatedBy   string    `json:"created_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (w *Webhook) Subscribes(eventType string) bool {
+	for _, e := range w.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookInput struct {
+	URL         string
+	Description string
+	Events      []string
+}
+
+type WebhookUpdate struct {
+	URL         *string
+	Description *string
+	Events      *[]string
+	Active      *bool
+	Secret      *string
+}
+
+func (u WebhookUpdate) IsEmpty() bool {
+	return u.URL == nil && u.Description == nil && u.Events == nil && u.Active == nil && u.Secret == nil
+}
+
+func NormalizeWebhookEvents(raw []string) ([]string, error) {
+	seen := make(map[string]bool, len(raw))
+	events := make([]string, 0, len(raw))
+	for _, e := range raw {
+		e = strings.ToLower(strings.TrimSpace(e))
+		if e == "*" {
+			return append([]string{}, WebhookEventTypes...), nil
+		}
+		if !isWebhookEventType(e) {
+			return nil, fmt.Errorf("unknown event %q, must be one of %s", e, strings.Join(WebhookEventTypes, ", "))
+		}
+		if !seen[e] {
+			seen[e] = true
+			events = append(events, e)
+		}
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("at least one event is required")
+	}
+	sort.Strings(events)
+	return events, nil
+}
+
+func isWebhookEventType(e string) bool {
+	for _, t := range WebhookEventTypes {
+		if t == e {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookEvent struct {
+	ID          string           `json:"id"`
+	Type        string           `json:"type"`
+	WorkspaceID string           `json:"workspace_id"`
+	CreatedAt   time.Time        `json:"created_at"`
+	Data        WebhookEventData `json:"data"`
+}
+
+type WebhookEventData struct {
+	Link   WebhookLink    `json:"link"`
+	Clicks *WebhookClicks `json:"clicks,omitempty"`
+}
+
+type WebhookLink struct {
+	ID          string     `json:"id"`
+	ShortCode   string     `json:"short_code"`
+	ShortURL    string     `json:"short_url,omitempty"`
+	Domain      string     `json:"domain,omitempty"`
+	OriginalURL string     `json:"original_url"`
+	Tags        []string   `json:"tags,omitempty"`
+	Folder      string     `json:"folder,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	DisabledAt  *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type WebhookClicks struct {
+	Count int64     `json:"count"`
+	From  time.Time `json:"from"`
+	To    time.Time `json:"to"`
+}
+
+func NewWebhookLink(u *URL) WebhookLink {
+	return WebhookLink{
+		ID:          u.ID,
+		ShortCode:   u.ShortCode,
+		ShortURL:    u.ShortURL,
+		Domain:      u.Domain,
+		OriginalURL: u.OriginalURL,
+		Tags:        u.Tags,
+		Folder:      u.Folder,
+		ExpiresAt:   u.ExpiresAt,
+		DisabledAt:  u.DisabledAt,
+		CreatedAt:   u.CreatedAt,
+	}
+}
+
+type WebhookDelivery struct {
+	ID             string          `json:"id"`
+	WebhookID      string          `json:"webhook_id"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	ResponseStatus int             `json:"response_status,omitempty"`
+	Error          string          `json:"error,omitempty"`
+	DurationMS     int64           `json:"duration_ms,omitempty"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      *time.Time      `json:"updated_at,omitempty"`
+}
+
+type DeliveryAttempt struct {
+	StatusCode int
+	Error      string
+	DurationMS int64
+}
+
+func (a DeliveryAttempt) Succeeded() bool {
+	return a.Error == "" && a.StatusCode >= 200 && a.StatusCode < 300
+}
+
+type DeliveryPolicy struct {
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+func (d *WebhookDelivery) Record(attempt DeliveryAttempt, policy DeliveryPolicy, now time.Time) {
+	d.Attempts++
+	d.ResponseStatus = attempt.StatusCode
+	d.Error = attempt.Error
+	d.DurationMS = attempt.DurationMS
+	d.UpdatedAt = &now
+
+	switch {
+	case attempt.Succeeded():
+		d.Status = DeliverySucceeded
+		d.DeliveredAt = &now
+		d.NextAttemptAt = nil
+	case d.Attempts >= policy.MaxAttempts:
+		d.Status = DeliveryFailed
+		d.NextAttemptAt = nil
+	default:
+		backoff := policy.BaseBackoff
+		for i := 1; i < d.Attempts && backoff < policy.MaxBackoff; i++ {
+			backoff *= 2
+		}
+		if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+		next := now.Add(backoff)
+		d.Status = DeliveryPending
+		d.NextAttemptAt = &next
+	}
+}
+
+func ValidateDeliveryStatus(status string) error {
+	switch status {
+	case "", DeliveryPending, DeliverySucceeded, DeliveryFailed:
+		return nil
+	default:
+		return fmt.Errorf("status must be pending, succeeded or failed")
+	}
+}
@@ -0,0 +1,101 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	MaxWorkspaceName      = 100
+	PersonalWorkspaceName = "Personal"
+)
+
+type Role string
+
+const (
+	RoleOwner  Role = "owner"
+	RoleEditor Role = "editor"
+	RoleViewer Role = "viewer"
+)
+
+var roleRank = map[Role]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleOwner:  3,
+}
+
+func ParseRole(raw string) (Role, error) {
+	role := Role(strings.ToLower(strings.TrimSpace(raw)))
+	if _, ok := roleRank[role]; !ok {
+		return "", fmt.Errorf("role must be owner, editor or viewer")
+	}
+	return role, nil
+}
+
+func (r Role) Allows(required Role) bool {
+	return roleRank[r] >= roleRank[required] && roleRank[required] > 0
+}
+
+type Workspace struct {
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	PersonalOwner *string   `json:"personal_owner,omitempty"`
+	CreatedBy     string    `json:"created_by"`
+	DedupPolicy   string    `json:"dedup_policy,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func (w *Workspace) IsPersonal() bool {
+	return w.PersonalOwner != nil
+}
+
+func (w *Workspace) EffectiveDedupPolicy() string {
+	if w.DedupPolicy == "" {
+		return DefaultDedupPolicy
+	}
+	return w.DedupPolicy
+}
+
+type WorkspaceUpdate struct {
+	Name        *string
+	DedupPolicy *string
+}
+
+func (u WorkspaceUpdate) IsEmpty() bool {
+	return u.Name == nil && u.DedupPolicy == nil
+}
+
+type WorkspaceMember struct {
+	WorkspaceID string    `json:"workspace_id"`
+	UserID      string    `json:"user_id"`
+	Role        Role      `json:"role"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type WorkspaceMembership struct {
+	Workspace Workspace `json:"workspace"`
+	Role      Role      `json:"role"`
+}
+
+type WorkspaceAccess struct {
+	WorkspaceID string
+	UserID      string
+	Role        Role
+}
+
+func (a WorkspaceAccess) Can(required Role) bool {
+	return a.Role.Allows(required)
+}
+
+func NormalizeWorkspaceName(raw string) (string, error) {
+	name := strings.Join(strings.Fields(raw), " ")
+	if name == "" {
+		return "", fmt.Errorf("workspace name is required")
+	}
+	if utf8.RuneCountInString(name) > MaxWorkspaceName {
+		return "", fmt.Errorf("workspace name must be at most %d characters", MaxWorkspaceName)
+	}
+	return name, nil
+}
@@ -0,0 +1,203 @@
+package netguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrLoopbackAddress = errors.New("loopback addresses are not allowed")
+	ErrReservedAddress = errors.New("private or reserved addresses are not allowed")
+)
+
+type Resolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+var reservedNets = mustParseCIDRs(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.0.0.0/24",
+	"192.0.2.0/24",
+	"192.88.99.0/24",
+	"192.168.0.0/16",
+	"198.18.0.0/15",
+	"198.51.100.0/24",
+	"203.0.113.0/24",
+	"224.0.0.0/4",
+	"240.0.0.0/4",
+	"::/128",
+	"64:ff9b::/96",
+	"64:ff9b:1::/48",
+	"100::/64",
+	"2001::/32",
+	"2001:db8::/32",
+	"fc00::/7",
+	"fe80::/10",
+	"fec0::/10",
+	"ff00::/8",
+)
+
+var sixToFour = mustParseCIDRs("2002::/16")[0]
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(fmt.Sprintf("netguard: invalid CIDR %q: %v", cidr, err))
+		}
+		nets = append(nets, network)
+	}
+	return nets
+}
+
+func IsLoopback(ip net.IP) bool {
+	if v4 := ip.To4(); v4 != nil {
+		return v4[0] == 127
+	}
+	return ip.Equal(net.IPv6loopback)
+}
+
+func IsReserved(ip net.IP) bool {
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	} else if sixToFour.Contains(ip) {
+		return IsLoopback(net.IP(ip[2:6])) || IsReserved(net.IP(ip[2:6]))
+	}
+
+	for _, network := range reservedNets {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+type Policy struct {
+	AllowLoopback bool
+	AllowPrivate  bool
+}
+
+func (p Policy) Check(ip net.IP) error {
+	if IsLoopback(ip) {
+		if !p.AllowLoopback {
+			return ErrLoopbackAddress
+		}
+		return nil
+	}
+	if !p.AllowPrivate && IsReserved(ip) {
+		return ErrReservedAddress
+	}
+	return nil
+}
+
+func (p Policy) control(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("dial to non-IP address %q refused", host)
+	}
+	if err := p.Check(ip); err != nil {
+		return fmt.Errorf("dial to %s refused: %w", ip, err)
+	}
+	return nil
+}
+
+func (p Policy) Dialer(timeout time.Duration) *net.Dialer {
+	return &net.Dialer{
+		Timeout: timeout,
+		Control: p.control,
+	}
+}
+
+func ParseHostIP(host string) net.IP {
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if i := strings.IndexByte(host, '%'); i >= 0 {
+		host = host[:i]
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		if v4 := ip.To4(); v4 != nil {
+			return v4
+		}
+		return ip
+	}
+
+	return parseLegacyIPv4(strings.TrimSuffix(host, "."))
+}
+
+func parseLegacyIPv4(host string) net.IP {
+	if host == "" {
+		return nil
+	}
+
+	parts := strings.Split(host, ".")
+	if len(parts) > 4 {
+		return nil
+	}
+
+	values := make([]uint64, len(parts))
+	for i, part := range parts {
+		v, ok := parseIPv4Part(part)
+		if !ok {
+			return nil
+		}
+		values[i] = v
+	}
+
+	var addr uint64
+	last := len(values) - 1
+	for i := 0; i < last; i++ {
+		if values[i] > 0xff {
+			return nil
+		}
+		addr |= values[i] << (8 * (3 - uint(i)))
+	}
+
+	remaining := uint(4 - last)
+	if values[last] >= 1<<(8*remaining) {
+		return nil
+	}
+	addr |= values[last]
+
+	return net.IPv4(byte(addr>>24), byte(addr>>16), byte(addr>>8), byte(addr)).To4()
+}
+
+func parseIPv4Part(part string) (uint64, bool) {
+	if part == "" {
+		return 0, false
+	}
+
+	base := 10
+	digits := part
+	switch {
+	case strings.HasPrefix(part, "0x") || strings.HasPrefix(part, "0X"):
+		base = 16
+		digits = part[2:]
+		if digits == "" {
+			return 0, true
+		}
+	case len(part) > 1 && part[0] == '0':
+		base = 8
+		digits = part[1:]
+	}
+
+	v, err := strconv.ParseUint(digits, base, 32)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
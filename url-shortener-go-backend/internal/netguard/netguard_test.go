@@ -0,0 +1,127 @@
+package netguard
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseHostIP(t *testing.T) {
+	tests := []struct {
+		host string
+		want string
+	}{
+		{host: "127.0.0.1", want: "127.0.0.1"},
+		{host: "0x7f.1", want: "127.0.0.1"},
+		{host: "0x7F.0.0.1", want: "127.0.0.1"},
+		{host: "2130706433", want: "127.0.0.1"},
+		{host: "0177.0.0.1", want: "127.0.0.1"},
+		{host: "0177.1", want: "127.0.0.1"},
+		{host: "10.1", want: "10.0.0.1"},
+		{host: "169.254.43518", want: "169.254.169.254"},
+		{host: "127.0.0.1.", want: "127.0.0.1"},
+		{host: "::ffff:169.254.169.254", want: "169.254.169.254"},
+		{host: "[::1]", want: "::1"},
+		{host: "fe80::1%eth0", want: "fe80::1"},
+		{host: "example.com"},
+		{host: "256.0.0.1"},
+		{host: "1.2.3.4.5"},
+		{host: "0x100000000"},
+		{host: "08.0.0.1"},
+		{host: "1..1"},
+		{host: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			got := ParseHostIP(tt.host)
+			if tt.want == "" {
+				if got != nil {
+					t.Fatalf("ParseHostIP(%q) = %s, want nil", tt.host, got)
+				}
+				return
+			}
+			if !got.Equal(net.ParseIP(tt.want)) {
+				t.Fatalf("ParseHostIP(%q) = %s, want %s", tt.host, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyCheck(t *testing.T) {
+	tests := []struct {
+		ip          string
+		wantDefault error
+		wantPrivate error
+	}{
+		{ip: "127.0.0.1", wantDefault: ErrLoopbackAddress, wantPrivate: ErrLoopbackAddress},
+		{ip: "127.8.9.10", wantDefault: ErrLoopbackAddress, wantPrivate: ErrLoopbackAddress},
+		{ip: "::1", wantDefault: ErrLoopbackAddress, wantPrivate: ErrLoopbackAddress},
+		{ip: "::ffff:127.0.0.1", wantDefault: ErrLoopbackAddress, wantPrivate: ErrLoopbackAddress},
+		{ip: "10.0.0.1", wantDefault: ErrReservedAddress},
+		{ip: "100.64.0.1", wantDefault: ErrReservedAddress},
+		{ip: "100.127.255.254", wantDefault: ErrReservedAddress},
+		{ip: "169.254.169.254", wantDefault: ErrReservedAddress},
+		{ip: "172.16.0.1", wantDefault: ErrReservedAddress},
+		{ip: "192.168.1.1", wantDefault: ErrReservedAddress},
+		{ip: "0.0.0.0", wantDefault: ErrReservedAddress},
+		{ip: "224.0.0.1", wantDefault: ErrReservedAddress},
+		{ip: "::ffff:169.254.169.254", wantDefault: ErrReservedAddress},
+		{ip: "::ffff:10.0.0.1", wantDefault: ErrReservedAddress},
+		{ip: "2002:a00:1::", wantDefault: ErrReservedAddress},
+		{ip: "2002:a9fe:a9fe::1", wantDefault: ErrReservedAddress},
+		{ip: "2002:7f00:1::", wantDefault: ErrReservedAddress},
+		{ip: "64:ff9b::a9fe:a9fe", wantDefault: ErrReservedAddress},
+		{ip: "64:ff9b:1::1", wantDefault: ErrReservedAddress},
+		{ip: "fc00::1", wantDefault: ErrReservedAddress},
+		{ip: "fe80::1", wantDefault: ErrReservedAddress},
+		{ip: "8.8.8.8"},
+		{ip: "100.128.0.1"},
+		{ip: "93.184.216.34"},
+		{ip: "2002:5db8:d822::"},
+		{ip: "2606:4700:4700::1111"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			ip := net.ParseIP(tt.ip)
+			if err := (Policy{}).Check(ip); !errors.Is(err, tt.wantDefault) || (tt.wantDefault == nil && err != nil) {
+				t.Fatalf("Policy{}.Check(%s) = %v, want %v", tt.ip, err, tt.wantDefault)
+			}
+			if err := (Policy{AllowPrivate: true}).Check(ip); !errors.Is(err, tt.wantPrivate) || (tt.wantPrivate == nil && err != nil) {
+				t.Fatalf("AllowPrivate Check(%s) = %v, want %v", tt.ip, err, tt.wantPrivate)
+			}
+		})
+	}
+}
+
+func TestDialerChecksResolvedAddress(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(srv.Close)
+	_, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("listener address: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for _, address := range []string{srv.Listener.Addr().String(), net.JoinHostPort("localhost", port)} {
+		if conn, err := (Policy{AllowPrivate: true}).Dialer(time.Second).DialContext(ctx, "tcp", address); !errors.Is(err, ErrLoopbackAddress) {
+			if conn != nil {
+				conn.Close()
+			}
+			t.Fatalf("dial %s = %v, want ErrLoopbackAddress", address, err)
+		}
+	}
+
+	conn, err := (Policy{AllowLoopback: true}).Dialer(time.Second).DialContext(ctx, "tcp", srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("dial with AllowLoopback: %v", err)
+	}
+	conn.Close()
+}
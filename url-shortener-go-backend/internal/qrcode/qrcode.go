@@ -0,0 +1,175 @@
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strconv"
+	"strings"
+
+	"rsc.io/qr"
+)
+
+const (
+	FormatPNG = "png"
+	FormatSVG = "svg"
+
+	DefaultSize   = 256
+	MinSize       = 64
+	MaxSize       = 2048
+	DefaultMargin = 4
+	MaxMargin     = 16
+)
+
+var ErrInvalidOption = errors.New("invalid qr option")
+
+type Options struct {
+	Format     string
+	Size       int
+	Margin     int
+	Level      qr.Level
+	Foreground color.RGBA
+	Background color.RGBA
+}
+
+func DefaultOptions() Options {
+	return Options{
+		Format:     FormatPNG,
+		Size:       DefaultSize,
+		Margin:     DefaultMargin,
+		Level:      qr.M,
+		Foreground: color.RGBA{A: 0xff},
+		Background: color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+	}
+}
+
+func ParseLevel(s string) (qr.Level, error) {
+	switch strings.ToUpper(s) {
+	case "L":
+		return qr.L, nil
+	case "M":
+		return qr.M, nil
+	case "Q":
+		return qr.Q, nil
+	case "H":
+		return qr.H, nil
+	}
+	return 0, fmt.Errorf("%w: ecc must be one of L, M, Q, H", ErrInvalidOption)
+}
+
+func ParseColor(s string) (color.RGBA, error) {
+	s = strings.TrimPrefix(s, "#")
+	if len(s) == 3 || len(s) == 4 {
+		expanded := make([]byte, 0, 2*len(s))
+		for i := 0; i < len(s); i++ {
+			expanded = append(expanded, s[i], s[i])
+		}
+		s = string(expanded)
+	}
+	if len(s) != 6 && len(s) != 8 {
+		return color.RGBA{}, fmt.Errorf("%w: color must be hex RGB or RGBA", ErrInvalidOption)
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("%w: color must be hex RGB or RGBA", ErrInvalidOption)
+	}
+	if len(s) == 6 {
+		v = v<<8 | 0xff
+	}
+	return color.RGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
+
+func ContentType(format string) string {
+	if format == FormatSVG {
+		return "image/svg+xml"
+	}
+	return "image/png"
+}
+
+func Render(text string, opts Options) ([]byte, error) {
+	code, err := qr.Encode(text, opts.Level)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode qr code: %w", err)
+	}
+
+	switch opts.Format {
+	case FormatSVG:
+		return renderSVG(code, opts), nil
+	case FormatPNG, "":
+		return renderPNG(code, opts)
+	}
+	return nil, fmt.Errorf("%w: format must be png or svg", ErrInvalidOption)
+}
+
+func renderPNG(code *qr.Code, opts Options) ([]byte, error) {
+	modules := code.Size + 2*opts.Margin
+	scale := opts.Size / modules
+	if scale < 1 {
+		scale = 1
+	}
+	side := modules * scale
+	dim := max(side, opts.Size)
+	offset := (dim - side) / 2
+
+	img := image.NewPaletted(image.Rect(0, 0, dim, dim), color.Palette{opts.Background, opts.Foreground})
+	for y := 0; y < code.Size; y++ {
+		for x := 0; x < code.Size; x++ {
+			if !code.Black(x, y) {
+				continue
+			}
+			px := offset + (x+opts.Margin)*scale
+			py := offset + (y+opts.Margin)*scale
+			for dy := 0; dy < scale; dy++ {
+				row := img.Pix[(py+dy)*img.Stride:]
+				for dx := 0; dx < scale; dx++ {
+					row[px+dx] = 1
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode png: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func renderSVG(code *qr.Code, opts Options) []byte {
+	modules := code.Size + 2*opts.Margin
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%d" height="%d" shape-rendering="crispEdges">`, modules, modules, opts.Size, opts.Size)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"%s/>`, hex(opts.Background), opacity(opts.Background))
+	fmt.Fprintf(&b, `<path fill="%s"%s d="`, hex(opts.Foreground), opacity(opts.Foreground))
+	for y := 0; y < code.Size; y++ {
+		for x := 0; x < code.Size; {
+			if !code.Black(x, y) {
+				x++
+				continue
+			}
+			run := 1
+			for x+run < code.Size && code.Black(x+run, y) {
+				run++
+			}
+			fmt.Fprintf(&b, "M%d %dh%dv1h-%dz", x+opts.Margin, y+opts.Margin, run, run)
+			x += run
+		}
+	}
+	b.WriteString(`"/></svg>`)
+	return b.Bytes()
+}
+
+func hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+func opacity(c color.RGBA) string {
+	if c.A == 0xff {
+		return ""
+	}
+	return fmt.Sprintf(` fill-opacity="%.3f"`, float64(c.A)/255)
+}
@@ -8,17 +8,23 @@ import (
 type AnalyticsRepository interface {
 	SaveAnalytics(ctx context.Context, userID, urlID, referrer, deviceType string) error
 
-	GetUserAnalyticsSummary(ctx context.Context, userID string) (*model.UserAnalyticsSummary, error)
+	SaveClickEvent(ctx context.Context, event model.ClickEvent) error
 
-	GetUserTopURLs(ctx context.Context, userID string, limit int) ([]model.URLClickStats, error)
+	GetURLWorkspaceID(ctx context.Context, urlID string) (string, error)
 
-	GetUserDailyClicks(ctx context.Context, userID string, days int) ([]model.DailyClickStats, error)
+	GetWorkspaceAnalyticsSummary(ctx context.Context, workspaceID string) (*model.UserAnalyticsSummary, error)
 
-	GetUserTopReferrers(ctx context.Context, userID string, limit int) ([]model.ReferrerStats, error)
+	GetWorkspaceTopURLs(ctx context.Context, workspaceID string, limit int, cursor *model.PageCursor) (model.Page[model.URLClickStats], error)
 
-	GetUserDeviceBreakdown(ctx context.Context, userID string) ([]model.DeviceStats, error)
+	GetWorkspaceDailyClicks(ctx context.Context, workspaceID string, days int) ([]model.DailyClickStats, error)
+
+	GetWorkspaceTopReferrers(ctx context.Context, workspaceID string, limit int, cursor *model.PageCursor) (model.Page[model.ReferrerStats], error)
+
+	GetWorkspaceDeviceBreakdown(ctx context.Context, workspaceID string) ([]model.DeviceStats, error)
+
+	GetURLVariantStats(ctx context.Context, workspaceID, urlID string) ([]model.VariantStats, error)
 
 	AggregateYesterdayAnalytics(ctx context.Context) error
 
-	GetUserStats(ctx context.Context, userID string) (totalURLs int64, totalClicks int64, clicksToday int64, clicksYesterday int64, err error)
+	GetWorkspaceStats(ctx context.Context, workspaceID string) (totalURLs int64, totalClicks int64, clicksToday int64, clicksYesterday int64, err error)
 }
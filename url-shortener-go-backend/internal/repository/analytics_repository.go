@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"log/slog"
 	"slices"
+	"strconv"
+	"strings"
 	"time"
 	"url-shortener-go-backend/internal/model"
 	"url-shortener-go-backend/internal/utils"
@@ -13,6 +15,11 @@ import (
 	"github.com/supabase-community/postgrest-go"
 )
 
+const (
+	topURLsCursorSort   = "clicks:desc"
+	referrersCursorSort = "referrer_clicks:desc"
+)
+
 type AnalyticsRepositoryImpl struct {
 	*SupabaseRepository
 }
@@ -22,16 +29,41 @@ func NewAnalyticsRepository(baseRepo *SupabaseRepository) AnalyticsRepository {
 }
 
 func (a *AnalyticsRepositoryImpl) SaveAnalytics(ctx context.Context, userID, urlID, referrer, deviceType string) error {
+	return a.SaveClickEvent(ctx, model.ClickEvent{
+		UserID:     userID,
+		URLID:      urlID,
+		Referrer:   referrer,
+		DeviceType: deviceType,
+	})
+}
+
+func (a *AnalyticsRepositoryImpl) SaveClickEvent(ctx context.Context, event model.ClickEvent) error {
+	userID, urlID := event.UserID, event.URLID
 	data := map[string]interface{}{
 		"url_id":      urlID,
-		"referrer":    referrer,
-		"device_type": deviceType,
+		"referrer":    event.Referrer,
+		"device_type": event.DeviceType,
 		"clicked_at":  utils.NowUTC(),
 	}
 
 	if userID != "" {
 		data["user_id"] = userID
 	}
+	if event.WorkspaceID != "" {
+		data["workspace_id"] = event.WorkspaceID
+	}
+	if event.OS != "" {
+		data["os"] = event.OS
+	}
+	if event.Country != "" {
+		data["country"] = event.Country
+	}
+	if event.MatchedRule != "" {
+		data["matched_rule"] = event.MatchedRule
+	}
+	if event.Variant != "" {
+		data["variant"] = event.Variant
+	}
 
 	resp, _, err := a.Client.
 		From("analytics").
@@ -56,8 +88,36 @@ func (a *AnalyticsRepositoryImpl) SaveAnalytics(ctx context.Context, userID, url
 	return nil
 }
 
-func (a *AnalyticsRepositoryImpl) GetUserAnalyticsSummary(ctx context.Context, userID string) (*model.UserAnalyticsSummary, error) {
-	slog.Info("creating analytics summary", "user_id", userID)
+func (a *AnalyticsRepositoryImpl) GetURLWorkspaceID(ctx context.Context, urlID string) (string, error) {
+	resp, _, err := a.Client.
+		From("urls").
+		Select("workspace_id", "exact", false).
+		Eq("id", urlID).
+		Single().
+		Execute()
+	if err != nil {
+		errStr := strings.ToLower(err.Error())
+		if strings.Contains(errStr, "pgrst116") || strings.Contains(errStr, "no rows") || strings.Contains(errStr, "json object requested") {
+			return "", utils.ErrNotFound
+		}
+		return "", fmt.Errorf("failed to fetch url workspace: %w", err)
+	}
+
+	var link struct {
+		WorkspaceID *string `json:"workspace_id"`
+	}
+	if err := json.Unmarshal(resp, &link); err != nil {
+		return "", fmt.Errorf("failed to decode url workspace: %w", err)
+	}
+
+	if link.WorkspaceID == nil {
+		return "", nil
+	}
+	return *link.WorkspaceID, nil
+}
+
+func (a *AnalyticsRepositoryImpl) GetWorkspaceAnalyticsSummary(ctx context.Context, workspaceID string) (*model.UserAnalyticsSummary, error) {
+	slog.Info("creating analytics summary", "workspace_id", workspaceID)
 
 	summary := &model.UserAnalyticsSummary{
 		TopURLs:         []model.URLClickStats{},
@@ -66,9 +126,10 @@ func (a *AnalyticsRepositoryImpl) GetUserAnalyticsSummary(ctx context.Context, u
 		DailyClickTrend: []model.DailyClickStats{},
 	}
 
-	topURLs, err := a.GetUserTopURLs(ctx, userID, 100)
+	topURLsPage, err := a.GetWorkspaceTopURLs(ctx, workspaceID, 100, nil)
+	topURLs := topURLsPage.Items
 	if err != nil {
-		slog.Error("failed to get top urls for summary", "user_id", userID, "error", err)
+		slog.Error("failed to get top urls for summary", "workspace_id", workspaceID, "error", err)
 		summary.TopURLs = []model.URLClickStats{}
 		summary.TotalURLs = 0
 		summary.TotalClicks = 0
@@ -92,12 +153,12 @@ func (a *AnalyticsRepositoryImpl) GetUserAnalyticsSummary(ctx context.Context, u
 			summary.TopURLs = topURLs
 		}
 
-		slog.Info("calculated url stats", "user_id", userID, "total_urls", summary.TotalURLs, "total_clicks", summary.TotalClicks)
+		slog.Info("calculated url stats", "workspace_id", workspaceID, "total_urls", summary.TotalURLs, "total_clicks", summary.TotalClicks)
 	}
 
-	dailyTrend, err := a.GetUserDailyClicks(ctx, userID, 2)
+	dailyTrend, err := a.GetWorkspaceDailyClicks(ctx, workspaceID, 2)
 	if err != nil || len(dailyTrend) == 0 {
-		slog.Warn("could not get daily trend for today/yesterday", "user_id", userID)
+		slog.Warn("could not get daily trend for today/yesterday", "workspace_id", workspaceID)
 		summary.ClicksToday = 0
 		summary.ClicksYesterday = 0
 	} else {
@@ -116,42 +177,42 @@ func (a *AnalyticsRepositoryImpl) GetUserAnalyticsSummary(ctx context.Context, u
 			}
 		}
 
-		slog.Info("daily clicks calculated", "user_id", userID, "today", summary.ClicksToday, "yesterday", summary.ClicksYesterday)
+		slog.Info("daily clicks calculated", "workspace_id", workspaceID, "today", summary.ClicksToday, "yesterday", summary.ClicksYesterday)
 	}
 
-	topReferrers, err := a.GetUserTopReferrers(ctx, userID, 5)
+	topReferrers, err := a.GetWorkspaceTopReferrers(ctx, workspaceID, 5, nil)
 	if err != nil {
-		slog.Error("failed to get top referrers for summary", "user_id", userID, "error", err)
+		slog.Error("failed to get top referrers for summary", "workspace_id", workspaceID, "error", err)
 		summary.TopReferrers = []model.ReferrerStats{}
 	} else {
-		summary.TopReferrers = topReferrers
+		summary.TopReferrers = topReferrers.Items
 	}
 
-	deviceBreakdown, err := a.GetUserDeviceBreakdown(ctx, userID)
+	deviceBreakdown, err := a.GetWorkspaceDeviceBreakdown(ctx, workspaceID)
 	if err != nil {
-		slog.Error("failed to get device breakdown for summary", "user_id", userID, "error", err)
+		slog.Error("failed to get device breakdown for summary", "workspace_id", workspaceID, "error", err)
 		summary.DeviceBreakdown = []model.DeviceStats{}
 	} else {
 		summary.DeviceBreakdown = deviceBreakdown
 	}
 
-	dailyTrend, err = a.GetUserDailyClicks(ctx, userID, 7)
+	dailyTrend, err = a.GetWorkspaceDailyClicks(ctx, workspaceID, 7)
 	if err != nil {
-		slog.Error("failed to get daily trend for summary", "user_id", userID, "error", err)
+		slog.Error("failed to get daily trend for summary", "workspace_id", workspaceID, "error", err)
 		summary.DailyClickTrend = []model.DailyClickStats{}
 	} else {
 		summary.DailyClickTrend = dailyTrend
 	}
 
-	slog.Info("analytics summary created", "user_id", userID)
+	slog.Info("analytics summary created", "workspace_id", workspaceID)
 	return summary, nil
 }
 
-func (a *AnalyticsRepositoryImpl) GetUserStats(ctx context.Context, userID string) (totalURLs int64, totalClicks int64, clicksToday int64, clicksYesterday int64, err error) {
+func (a *AnalyticsRepositoryImpl) GetWorkspaceStats(ctx context.Context, workspaceID string) (totalURLs int64, totalClicks int64, clicksToday int64, clicksYesterday int64, err error) {
 	resp, count, err := a.Client.
 		From("urls").
 		Select("click_count", "exact", true).
-		Eq("user_id", userID).
+		Eq("workspace_id", workspaceID).
 		Execute()
 
 	if err != nil {
@@ -175,12 +236,12 @@ func (a *AnalyticsRepositoryImpl) GetUserStats(ctx context.Context, userID strin
 	_, todayCount, err := a.Client.
 		From("analytics").
 		Select("id", "exact", true).
-		Eq("user_id", userID).
+		Eq("workspace_id", workspaceID).
 		Gte("clicked_at", today).
 		Execute()
 
 	if err != nil {
-		slog.Error("failed to get today's clicks", "user_id", userID, "error", err)
+		slog.Error("failed to get today's clicks", "workspace_id", workspaceID, "error", err)
 		clicksToday = 0
 	} else {
 		clicksToday = int64(todayCount)
@@ -191,13 +252,13 @@ func (a *AnalyticsRepositoryImpl) GetUserStats(ctx context.Context, userID strin
 	_, yesterdayCount, err := a.Client.
 		From("analytics").
 		Select("id", "exact", true).
-		Eq("user_id", userID).
+		Eq("workspace_id", workspaceID).
 		Gte("clicked_at", yesterday).
 		Lt("clicked_at", yesterdayEnd).
 		Execute()
 
 	if err != nil {
-		slog.Error("failed to get yesterday's clicks", "user_id", userID, "error", err)
+		slog.Error("failed to get yesterday's clicks", "workspace_id", workspaceID, "error", err)
 		clicksYesterday = 0
 	} else {
 		clicksYesterday = int64(yesterdayCount)
@@ -206,29 +267,52 @@ func (a *AnalyticsRepositoryImpl) GetUserStats(ctx context.Context, userID strin
 	return totalURLs, totalClicks, clicksToday, clicksYesterday, nil
 }
 
-func (a *AnalyticsRepositoryImpl) GetUserTopURLs(ctx context.Context, userID string, limit int) ([]model.URLClickStats, error) {
-	resp, _, err := a.Client.
+func (a *AnalyticsRepositoryImpl) GetWorkspaceTopURLs(ctx context.Context, workspaceID string, limit int, cursor *model.PageCursor) (model.Page[model.URLClickStats], error) {
+	empty := model.Page[model.URLClickStats]{Items: []model.URLClickStats{}}
+
+	query := a.Client.
 		From("urls").
-		Select("id, short_code, original_url, click_count, created_at", "exact", false).
-		Eq("user_id", userID).
+		Select("id, short_code, original_url, normalized_url, click_count, created_at", "exact", false).
+		Eq("workspace_id", workspaceID)
+
+	if cursor != nil {
+		if cursor.Sort != topURLsCursorSort {
+			return empty, model.ErrInvalidCursor
+		}
+		filter, err := keysetFilter("click_count", false, cursor)
+		if err != nil {
+			return empty, err
+		}
+		query = query.Or(filter, "")
+	}
+
+	resp, _, err := query.
 		Order("click_count", &postgrest.OrderOpts{Ascending: false}).
-		Limit(limit, "").
+		Order("id", &postgrest.OrderOpts{Ascending: false}).
+		Limit(limit+1, "").
 		Execute()
 
 	if err != nil {
-		return []model.URLClickStats{}, fmt.Errorf("failed to fetch top URLs: %w", err)
+		return empty, fmt.Errorf("failed to fetch top URLs: %w", err)
 	}
 
 	var urls []model.URLClickStats
 	if err := json.Unmarshal(resp, &urls); err != nil {
-		return []model.URLClickStats{}, fmt.Errorf("failed to decode top URLs: %w", err)
+		return empty, fmt.Errorf("failed to decode top URLs: %w", err)
+	}
+
+	page := model.Page[model.URLClickStats]{Items: urls}
+	if len(urls) > limit {
+		page.Items = urls[:limit]
+		last := page.Items[limit-1]
+		page.NextCursor = model.PageCursor{Sort: topURLsCursorSort, Value: strconv.FormatInt(last.ClickCount, 10), ID: last.URLID}.Encode()
 	}
 
-	if urls == nil {
-		urls = []model.URLClickStats{}
+	if page.Items == nil {
+		page.Items = []model.URLClickStats{}
 	}
 
-	return urls, nil
+	return page, nil
 }
 
 func fillMissingDays(data []model.DailyClickStats, days int) []model.DailyClickStats {
@@ -249,10 +333,10 @@ func fillMissingDays(data []model.DailyClickStats, days int) []model.DailyClickS
 	return result
 }
 
-func (a *AnalyticsRepositoryImpl) GetUserDailyClicks(ctx context.Context, userID string, days int) ([]model.DailyClickStats, error) {
-	rawJSON := a.Client.Rpc("get_user_daily_clicks", "", map[string]any{
-		"p_days":    days,
-		"p_user_id": userID,
+func (a *AnalyticsRepositoryImpl) GetWorkspaceDailyClicks(ctx context.Context, workspaceID string, days int) ([]model.DailyClickStats, error) {
+	rawJSON := a.Client.Rpc("get_workspace_daily_clicks", "", map[string]any{
+		"p_days":         days,
+		"p_workspace_id": workspaceID,
 	})
 
 	if rawJSON == "" {
@@ -269,66 +353,133 @@ func (a *AnalyticsRepositoryImpl) GetUserDailyClicks(ctx context.Context, userID
 	return stats, nil
 }
 
-func (a *AnalyticsRepositoryImpl) GetUserTopReferrers(ctx context.Context, userID string, limit int) ([]model.ReferrerStats, error) {
+func (a *AnalyticsRepositoryImpl) GetWorkspaceTopReferrers(ctx context.Context, workspaceID string, limit int, cursor *model.PageCursor) (model.Page[model.ReferrerStats], error) {
+	empty := model.Page[model.ReferrerStats]{Items: []model.ReferrerStats{}}
+
+	params := map[string]any{
+		"p_workspace_id": workspaceID,
+		"p_limit":        limit + 1,
+	}
+	if cursor != nil {
+		n, err := strconv.ParseInt(cursor.Value, 10, 64)
+		if err != nil || cursor.Sort != referrersCursorSort {
+			return empty, model.ErrInvalidCursor
+		}
+		params["p_after_clicks"] = n
+		params["p_after_referrer"] = cursor.ID
+	}
+
+	rawJSON := a.Client.Rpc("get_workspace_top_referrers", "", params)
+	if rawJSON == "" {
+		return empty, nil
+	}
+
+	var referrers []model.ReferrerStats
+	if err := json.Unmarshal([]byte(rawJSON), &referrers); err != nil {
+		return empty, fmt.Errorf("failed to decode top referrers: %w", err)
+	}
+
+	page := model.Page[model.ReferrerStats]{Items: referrers}
+	if len(referrers) > limit {
+		page.Items = referrers[:limit]
+		last := page.Items[limit-1]
+		page.NextCursor = model.PageCursor{Sort: referrersCursorSort, Value: strconv.FormatInt(last.Clicks, 10), ID: last.Referrer}.Encode()
+	}
+
+	if page.Items == nil {
+		page.Items = []model.ReferrerStats{}
+	}
+
+	return page, nil
+}
+
+func (a *AnalyticsRepositoryImpl) GetURLVariantStats(ctx context.Context, workspaceID, urlID string) ([]model.VariantStats, error) {
 	resp, _, err := a.Client.
-		From("analytics").
-		Select("referrer", "exact", false).
-		Eq("user_id", userID).
-		Not("referrer", "is", "").
-		Not("referrer", "eq", "").
+		From("urls").
+		Select("variants", "exact", false).
+		Eq("id", urlID).
+		Eq("workspace_id", workspaceID).
+		Single().
 		Execute()
-
 	if err != nil {
-		return []model.ReferrerStats{}, fmt.Errorf("failed to fetch referrer data: %w", err)
+		errStr := strings.ToLower(err.Error())
+		if strings.Contains(errStr, "pgrst116") || strings.Contains(errStr, "no rows") || strings.Contains(errStr, "json object requested") {
+			return nil, utils.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch url variants: %w", err)
 	}
 
-	if len(resp) == 0 || string(resp) == "[]" || string(resp) == "" {
-		slog.Info("no referrer data found", "user_id", userID)
-		return []model.ReferrerStats{}, nil
+	var link struct {
+		Variants []model.Variant `json:"variants"`
+	}
+	if err := json.Unmarshal(resp, &link); err != nil {
+		return nil, fmt.Errorf("failed to decode url variants: %w", err)
+	}
+
+	resp, _, err = a.Client.
+		From("analytics").
+		Select("variant", "exact", false).
+		Eq("workspace_id", workspaceID).
+		Eq("url_id", urlID).
+		Not("variant", "is", "null").
+		Execute()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch variant data: %w", err)
 	}
 
 	var analytics []struct {
-		Referrer string `json:"referrer"`
+		Variant string `json:"variant"`
 	}
-	if err := json.Unmarshal(resp, &analytics); err != nil {
-		slog.Error("failed to decode referrer data", "user_id", userID, "error", err)
-		return []model.ReferrerStats{}, nil
+	if len(resp) > 0 {
+		if err := json.Unmarshal(resp, &analytics); err != nil {
+			slog.Error("failed to decode variant data", "url_id", urlID, "error", err)
+			return nil, fmt.Errorf("failed to decode variant data: %w", err)
+		}
 	}
 
-	referrerCounts := make(map[string]int64)
+	counts := make(map[string]int64)
+	var total int64
 	for _, record := range analytics {
-		if record.Referrer != "" {
-			referrerCounts[record.Referrer]++
+		if record.Variant != "" {
+			counts[record.Variant]++
+			total++
 		}
 	}
 
-	referrers := make([]model.ReferrerStats, 0, len(referrerCounts))
-	for referrer, count := range referrerCounts {
-		referrers = append(referrers, model.ReferrerStats{
-			Referrer: referrer,
-			Clicks:   count,
+	stats := make([]model.VariantStats, 0, len(link.Variants))
+	for _, v := range link.Variants {
+		stats = append(stats, model.VariantStats{
+			Variant:     v.Name,
+			Destination: v.Destination,
+			Weight:      v.Weight,
+			Clicks:      counts[v.Name],
 		})
+		delete(counts, v.Name)
+	}
+	for name, count := range counts {
+		stats = append(stats, model.VariantStats{Variant: name, Clicks: count})
 	}
 
-	slices.SortFunc(referrers, func(a, b model.ReferrerStats) int {
+	for i := range stats {
+		if total > 0 {
+			stats[i].Share = float64(stats[i].Clicks) / float64(total)
+		}
+	}
+
+	slices.SortFunc(stats, func(a, b model.VariantStats) int {
 		if a.Clicks > b.Clicks {
 			return -1
 		}
 		if a.Clicks < b.Clicks {
 			return 1
 		}
-		return 0
+		return strings.Compare(a.Variant, b.Variant)
 	})
 
-	if len(referrers) > limit {
-		referrers = referrers[:limit]
-	}
-
-	slog.Info("referrers found", "user_id", userID, "count", len(referrers))
-	return referrers, nil
+	return stats, nil
 }
 
-func (a *AnalyticsRepositoryImpl) GetUserDeviceBreakdown(ctx context.Context, userID string) ([]model.DeviceStats, error) {
+func (a *AnalyticsRepositoryImpl) GetWorkspaceDeviceBreakdown(ctx context.Context, workspaceID string) ([]model.DeviceStats, error) {
 	resp, _, err := a.Client.
 		From("daily_analytics").
 		Select(`
@@ -340,17 +491,17 @@ func (a *AnalyticsRepositoryImpl) GetUserDeviceBreakdown(ctx context.Context, us
 			UNION ALL
 			SELECT 'unknown' as device_type, SUM(unknown_clicks) as clicks
 		`, "exact", false).
-		Eq("user_id", userID).
+		Eq("workspace_id", workspaceID).
 		Order("clicks", &postgrest.OrderOpts{Ascending: false}).
 		Execute()
 
 	if err != nil {
-		return a.getUserDeviceBreakdownFromRaw(ctx, userID)
+		return a.getWorkspaceDeviceBreakdownFromRaw(ctx, workspaceID)
 	}
 
 	var devices []model.DeviceStats
 	if err := json.Unmarshal(resp, &devices); err != nil {
-		return a.getUserDeviceBreakdownFromRaw(ctx, userID)
+		return a.getWorkspaceDeviceBreakdownFromRaw(ctx, workspaceID)
 	}
 
 	if devices == nil {
@@ -360,11 +511,11 @@ func (a *AnalyticsRepositoryImpl) GetUserDeviceBreakdown(ctx context.Context, us
 	return devices, nil
 }
 
-func (a *AnalyticsRepositoryImpl) getUserDeviceBreakdownFromRaw(ctx context.Context, userID string) ([]model.DeviceStats, error) {
+func (a *AnalyticsRepositoryImpl) getWorkspaceDeviceBreakdownFromRaw(ctx context.Context, workspaceID string) ([]model.DeviceStats, error) {
 	resp, _, err := a.Client.
 		From("analytics").
 		Select("device_type", "exact", false).
-		Eq("user_id", userID).
+		Eq("workspace_id", workspaceID).
 		Execute()
 
 	if err != nil {
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"url-shortener-go-backend/internal/model"
+)
+
+type DomainRepository interface {
+	CreateDomain(ctx context.Context, domain *model.Domain) error
+	GetDomainByHostname(ctx context.Context, hostname string) (*model.Domain, error)
+	ListUserDomains(ctx context.Context, userID string) ([]model.Domain, error)
+	MarkDomainVerified(ctx context.Context, id string) (*model.Domain, error)
+	DeleteDomain(ctx context.Context, id string) error
+}
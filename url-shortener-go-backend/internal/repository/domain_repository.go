@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"url-shortener-go-backend/internal/model"
+	"url-shortener-go-backend/internal/utils"
+
+	"github.com/supabase-community/postgrest-go"
+)
+
+type DomainRepositoryImpl struct {
+	*SupabaseRepository
+}
+
+func NewDomainRepository(baseRepo *SupabaseRepository) DomainRepository {
+	return &DomainRepositoryImpl{baseRepo}
+}
+
+func (d *DomainRepositoryImpl) CreateDomain(ctx context.Context, domain *model.Domain) error {
+	data := map[string]interface{}{
+		"user_id":            domain.UserID,
+		"hostname":           domain.Hostname,
+		"verification_token": domain.VerificationToken,
+	}
+
+	resp, _, err := d.Client.
+		From("domains").
+		Insert(data, false, "", "", "").
+		Execute()
+
+	if err != nil {
+		msg := strings.ToLower(err.Error())
+		if strings.Contains(msg, "duplicate key") || strings.Contains(msg, "unique constraint") {
+			return ErrUniqueViolation
+		}
+		slog.Error("domain insert failed", "hostname", domain.Hostname, "error", err)
+		return fmt.Errorf("failed to save domain: %w", err)
+	}
+
+	var inserted []model.Domain
+	if err := json.Unmarshal(resp, &inserted); err != nil {
+		return fmt.Errorf("failed to decode inserted domain: %w", err)
+	}
+
+	if len(inserted) == 0 {
+		return fmt.Errorf("no domain returned after insert")
+	}
+
+	*domain = inserted[0]
+	slog.Info("domain saved", "id", domain.ID, "hostname", domain.Hostname)
+	return nil
+}
+
+func (d *DomainRepositoryImpl) GetDomainByHostname(ctx context.Context, hostname string) (*model.Domain, error) {
+	resp, _, err := d.Client.
+		From("domains").
+		Select("*", "exact", false).
+		Eq("hostname", hostname).
+		Single().
+		Execute()
+
+	if err != nil {
+		errStr := strings.ToLower(err.Error())
+		if strings.Contains(errStr, "pgrst116") || strings.Contains(errStr, "no rows") || strings.Contains(errStr, "json object requested") {
+			return nil, utils.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch domain: %w", err)
+	}
+
+	var domain model.Domain
+	if err := json.Unmarshal(resp, &domain); err != nil {
+		return nil, fmt.Errorf("failed to decode domain: %w", err)
+	}
+
+	return &domain, nil
+}
+
+func (d *DomainRepositoryImpl) ListUserDomains(ctx context.Context, userID string) ([]model.Domain, error) {
+	resp, _, err := d.Client.
+		From("domains").
+		Select("*", "exact", false).
+		Eq("user_id", userID).
+		Order("created_at", &postgrest.OrderOpts{Ascending: true}).
+		Execute()
+
+	if err != nil {
+		return []model.Domain{}, fmt.Errorf("failed to fetch domains: %w", err)
+	}
+
+	var domains []model.Domain
+	if err := json.Unmarshal(resp, &domains); err != nil {
+		return []model.Domain{}, fmt.Errorf("failed to decode domains: %w", err)
+	}
+
+	if domains == nil {
+		domains = []model.Domain{}
+	}
+
+	return domains, nil
+}
+
+func (d *DomainRepositoryImpl) MarkDomainVerified(ctx context.Context, id string) (*model.Domain, error) {
+	resp, _, err := d.Client.
+		From("domains").
+		Update(map[string]interface{}{"verified_at": utils.NowUTC()}, "representation", "").
+		Eq("id", id).
+		Execute()
+
+	if err != nil {
+		slog.Error("domain verify update failed", "id", id, "error", err)
+		return nil, fmt.Errorf("failed to mark domain verified: %w", err)
+	}
+
+	var updated []model.Domain
+	if err := json.Unmarshal(resp, &updated); err != nil {
+		return nil, fmt.Errorf("failed to decode verified domain: %w", err)
+	}
+
+	if len(updated) == 0 {
+		return nil, utils.ErrNotFound
+	}
+
+	return &updated[0], nil
+}
+
+func (d *DomainRepositoryImpl) DeleteDomain(ctx context.Context, id string) error {
+	_, _, err := d.Client.
+		From("domains").
+		Delete("", "").
+		Eq("id", id).
+		Execute()
+
+	if err != nil {
+		slog.Error("domain delete failed", "id", id, "error", err)
+		return fmt.Errorf("failed to delete domain: %w", err)
+	}
+
+	return nil
+}
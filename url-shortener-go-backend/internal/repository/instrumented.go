@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"url-shortener-go-backend/internal/metadata"
 	"url-shortener-go-backend/internal/metrics"
 	"url-shortener-go-backend/internal/model"
 )
@@ -30,11 +31,18 @@ func (r *InstrumentedURLRepository) GetURLByShortCode(ctx context.Context, short
 	return url, err
 }
 
-func (r *InstrumentedURLRepository) GetUserUrls(ctx context.Context, userID string) ([]model.URL, error) {
+func (r *InstrumentedURLRepository) FindURLByDestination(ctx context.Context, workspaceID, domain, destinationHash string, isPublic bool) (*model.URL, error) {
 	start := time.Now()
-	urls, err := r.inner.GetUserUrls(ctx, userID)
-	metrics.DBQueryDuration.WithLabelValues("GetUserUrls", "urls").Observe(time.Since(start).Seconds())
-	return urls, err
+	url, err := r.inner.FindURLByDestination(ctx, workspaceID, domain, destinationHash, isPublic)
+	metrics.DBQueryDuration.WithLabelValues("FindURLByDestination", "urls").Observe(time.Since(start).Seconds())
+	return url, err
+}
+
+func (r *InstrumentedURLRepository) GetWorkspaceURLs(ctx context.Context, workspaceID string, q model.URLQuery) (model.Page[model.URL], error) {
+	start := time.Now()
+	page, err := r.inner.GetWorkspaceURLs(ctx, workspaceID, q)
+	metrics.DBQueryDuration.WithLabelValues("GetWorkspaceURLs", "urls").Observe(time.Since(start).Seconds())
+	return page, err
 }
 
 func (r *InstrumentedURLRepository) IncrementClickCount(ctx context.Context, shortcode string) error {
@@ -44,6 +52,69 @@ func (r *InstrumentedURLRepository) IncrementClickCount(ctx context.Context, sho
 	return err
 }
 
+func (r *InstrumentedURLRepository) SetURLDisabled(ctx context.Context, shortcode, reason string, disabled bool) (*model.URL, error) {
+	start := time.Now()
+	url, err := r.inner.SetURLDisabled(ctx, shortcode, reason, disabled)
+	metrics.DBQueryDuration.WithLabelValues("SetURLDisabled", "urls").Observe(time.Since(start).Seconds())
+	return url, err
+}
+
+func (r *InstrumentedURLRepository) ListActiveURLs(ctx context.Context, afterID string, limit int) ([]model.URL, error) {
+	start := time.Now()
+	urls, err := r.inner.ListActiveURLs(ctx, afterID, limit)
+	metrics.DBQueryDuration.WithLabelValues("ListActiveURLs", "urls").Observe(time.Since(start).Seconds())
+	return urls, err
+}
+
+func (r *InstrumentedURLRepository) UpdateURL(ctx context.Context, shortcode string, update model.URLUpdate) (*model.URL, error) {
+	start := time.Now()
+	url, err := r.inner.UpdateURL(ctx, shortcode, update)
+	metrics.DBQueryDuration.WithLabelValues("UpdateURL", "urls").Observe(time.Since(start).Seconds())
+	return url, err
+}
+
+func (r *InstrumentedURLRepository) SetURLMetadata(ctx context.Context, shortcode string, meta metadata.Metadata) (*model.URL, error) {
+	start := time.Now()
+	url, err := r.inner.SetURLMetadata(ctx, shortcode, meta)
+	metrics.DBQueryDuration.WithLabelValues("SetURLMetadata", "urls").Observe(time.Since(start).Seconds())
+	return url, err
+}
+
+func (r *InstrumentedURLRepository) ListDueHealthChecks(ctx context.Context, now time.Time, afterID string, limit int) ([]model.URL, error) {
+	start := time.Now()
+	urls, err := r.inner.ListDueHealthChecks(ctx, now, afterID, limit)
+	metrics.DBQueryDuration.WithLabelValues("ListDueHealthChecks", "urls").Observe(time.Since(start).Seconds())
+	return urls, err
+}
+
+func (r *InstrumentedURLRepository) ListUnnotifiedExpiredURLs(ctx context.Context, now time.Time, afterID string, limit int) ([]model.URL, error) {
+	start := time.Now()
+	urls, err := r.inner.ListUnnotifiedExpiredURLs(ctx, now, afterID, limit)
+	metrics.DBQueryDuration.WithLabelValues("ListUnnotifiedExpiredURLs", "urls").Observe(time.Since(start).Seconds())
+	return urls, err
+}
+
+func (r *InstrumentedURLRepository) MarkExpiryNotified(ctx context.Context, id string) (bool, error) {
+	start := time.Now()
+	claimed, err := r.inner.MarkExpiryNotified(ctx, id)
+	metrics.DBQueryDuration.WithLabelValues("MarkExpiryNotified", "urls").Observe(time.Since(start).Seconds())
+	return claimed, err
+}
+
+func (r *InstrumentedURLRepository) DeleteURL(ctx context.Context, shortcode string) error {
+	start := time.Now()
+	err := r.inner.DeleteURL(ctx, shortcode)
+	metrics.DBQueryDuration.WithLabelValues("DeleteURL", "urls").Observe(time.Since(start).Seconds())
+	return err
+}
+
+func (r *InstrumentedURLRepository) SetURLHealth(ctx context.Context, shortcode string, health model.LinkHealth) (*model.URL, error) {
+	start := time.Now()
+	url, err := r.inner.SetURLHealth(ctx, shortcode, health)
+	metrics.DBQueryDuration.WithLabelValues("SetURLHealth", "urls").Observe(time.Since(start).Seconds())
+	return url, err
+}
+
 type InstrumentedAnalyticsRepository struct {
 	inner AnalyticsRepository
 }
@@ -59,38 +130,59 @@ func (r *InstrumentedAnalyticsRepository) SaveAnalytics(ctx context.Context, use
 	return err
 }
 
-func (r *InstrumentedAnalyticsRepository) GetUserAnalyticsSummary(ctx context.Context, userID string) (*model.UserAnalyticsSummary, error) {
+func (r *InstrumentedAnalyticsRepository) SaveClickEvent(ctx context.Context, event model.ClickEvent) error {
 	start := time.Now()
-	summary, err := r.inner.GetUserAnalyticsSummary(ctx, userID)
-	metrics.DBQueryDuration.WithLabelValues("GetUserAnalyticsSummary", "analytics").Observe(time.Since(start).Seconds())
+	err := r.inner.SaveClickEvent(ctx, event)
+	metrics.DBQueryDuration.WithLabelValues("SaveClickEvent", "analytics").Observe(time.Since(start).Seconds())
+	return err
+}
+
+func (r *InstrumentedAnalyticsRepository) GetURLWorkspaceID(ctx context.Context, urlID string) (string, error) {
+	start := time.Now()
+	workspaceID, err := r.inner.GetURLWorkspaceID(ctx, urlID)
+	metrics.DBQueryDuration.WithLabelValues("GetURLWorkspaceID", "urls").Observe(time.Since(start).Seconds())
+	return workspaceID, err
+}
+
+func (r *InstrumentedAnalyticsRepository) GetWorkspaceAnalyticsSummary(ctx context.Context, workspaceID string) (*model.UserAnalyticsSummary, error) {
+	start := time.Now()
+	summary, err := r.inner.GetWorkspaceAnalyticsSummary(ctx, workspaceID)
+	metrics.DBQueryDuration.WithLabelValues("GetWorkspaceAnalyticsSummary", "analytics").Observe(time.Since(start).Seconds())
 	return summary, err
 }
 
-func (r *InstrumentedAnalyticsRepository) GetUserTopURLs(ctx context.Context, userID string, limit int) ([]model.URLClickStats, error) {
+func (r *InstrumentedAnalyticsRepository) GetWorkspaceTopURLs(ctx context.Context, workspaceID string, limit int, cursor *model.PageCursor) (model.Page[model.URLClickStats], error) {
 	start := time.Now()
-	urls, err := r.inner.GetUserTopURLs(ctx, userID, limit)
-	metrics.DBQueryDuration.WithLabelValues("GetUserTopURLs", "urls").Observe(time.Since(start).Seconds())
-	return urls, err
+	page, err := r.inner.GetWorkspaceTopURLs(ctx, workspaceID, limit, cursor)
+	metrics.DBQueryDuration.WithLabelValues("GetWorkspaceTopURLs", "urls").Observe(time.Since(start).Seconds())
+	return page, err
 }
 
-func (r *InstrumentedAnalyticsRepository) GetUserDailyClicks(ctx context.Context, userID string, days int) ([]model.DailyClickStats, error) {
+func (r *InstrumentedAnalyticsRepository) GetWorkspaceDailyClicks(ctx context.Context, workspaceID string, days int) ([]model.DailyClickStats, error) {
 	start := time.Now()
-	stats, err := r.inner.GetUserDailyClicks(ctx, userID, days)
-	metrics.DBQueryDuration.WithLabelValues("GetUserDailyClicks", "analytics").Observe(time.Since(start).Seconds())
+	stats, err := r.inner.GetWorkspaceDailyClicks(ctx, workspaceID, days)
+	metrics.DBQueryDuration.WithLabelValues("GetWorkspaceDailyClicks", "analytics").Observe(time.Since(start).Seconds())
 	return stats, err
 }
 
-func (r *InstrumentedAnalyticsRepository) GetUserTopReferrers(ctx context.Context, userID string, limit int) ([]model.ReferrerStats, error) {
+func (r *InstrumentedAnalyticsRepository) GetWorkspaceTopReferrers(ctx context.Context, workspaceID string, limit int, cursor *model.PageCursor) (model.Page[model.ReferrerStats], error) {
 	start := time.Now()
-	refs, err := r.inner.GetUserTopReferrers(ctx, userID, limit)
-	metrics.DBQueryDuration.WithLabelValues("GetUserTopReferrers", "analytics").Observe(time.Since(start).Seconds())
-	return refs, err
+	page, err := r.inner.GetWorkspaceTopReferrers(ctx, workspaceID, limit, cursor)
+	metrics.DBQueryDuration.WithLabelValues("GetWorkspaceTopReferrers", "analytics").Observe(time.Since(start).Seconds())
+	return page, err
 }
 
-func (r *InstrumentedAnalyticsRepository) GetUserDeviceBreakdown(ctx context.Context, userID string) ([]model.DeviceStats, error) {
+func (r *InstrumentedAnalyticsRepository) GetURLVariantStats(ctx context.Context, workspaceID, urlID string) ([]model.VariantStats, error) {
 	start := time.Now()
-	devices, err := r.inner.GetUserDeviceBreakdown(ctx, userID)
-	metrics.DBQueryDuration.WithLabelValues("GetUserDeviceBreakdown", "analytics").Observe(time.Since(start).Seconds())
+	stats, err := r.inner.GetURLVariantStats(ctx, workspaceID, urlID)
+	metrics.DBQueryDuration.WithLabelValues("GetURLVariantStats", "analytics").Observe(time.Since(start).Seconds())
+	return stats, err
+}
+
+func (r *InstrumentedAnalyticsRepository) GetWorkspaceDeviceBreakdown(ctx context.Context, workspaceID string) ([]model.DeviceStats, error) {
+	start := time.Now()
+	devices, err := r.inner.GetWorkspaceDeviceBreakdown(ctx, workspaceID)
+	metrics.DBQueryDuration.WithLabelValues("GetWorkspaceDeviceBreakdown", "analytics").Observe(time.Since(start).Seconds())
 	return devices, err
 }
 
@@ -101,9 +193,244 @@ func (r *InstrumentedAnalyticsRepository) AggregateYesterdayAnalytics(ctx contex
 	return err
 }
 
-func (r *InstrumentedAnalyticsRepository) GetUserStats(ctx context.Context, userID string) (int64, int64, int64, int64, error) {
+func (r *InstrumentedAnalyticsRepository) GetWorkspaceStats(ctx context.Context, workspaceID string) (int64, int64, int64, int64, error) {
 	start := time.Now()
-	a, b, c, d, err := r.inner.GetUserStats(ctx, userID)
-	metrics.DBQueryDuration.WithLabelValues("GetUserStats", "analytics").Observe(time.Since(start).Seconds())
+	a, b, c, d, err := r.inner.GetWorkspaceStats(ctx, workspaceID)
+	metrics.DBQueryDuration.WithLabelValues("GetWorkspaceStats", "analytics").Observe(time.Since(start).Seconds())
 	return a, b, c, d, err
 }
+
+type InstrumentedReportRepository struct {
+	inner ReportRepository
+}
+
+func NewInstrumentedReportRepository(inner ReportRepository) ReportRepository {
+	return &InstrumentedReportRepository{inner: inner}
+}
+
+func (r *InstrumentedReportRepository) CreateReport(ctx context.Context, report *model.Report) error {
+	start := time.Now()
+	err := r.inner.CreateReport(ctx, report)
+	metrics.DBQueryDuration.WithLabelValues("CreateReport", "reports").Observe(time.Since(start).Seconds())
+	return err
+}
+
+func (r *InstrumentedReportRepository) GetReport(ctx context.Context, id string) (*model.Report, error) {
+	start := time.Now()
+	report, err := r.inner.GetReport(ctx, id)
+	metrics.DBQueryDuration.WithLabelValues("GetReport", "reports").Observe(time.Since(start).Seconds())
+	return report, err
+}
+
+func (r *InstrumentedReportRepository) ListReports(ctx context.Context, status string, limit int) ([]model.Report, error) {
+	start := time.Now()
+	reports, err := r.inner.ListReports(ctx, status, limit)
+	metrics.DBQueryDuration.WithLabelValues("ListReports", "reports").Observe(time.Since(start).Seconds())
+	return reports, err
+}
+
+func (r *InstrumentedReportRepository) ResolveReport(ctx context.Context, id, status, resolution, resolvedBy string) (*model.Report, error) {
+	start := time.Now()
+	report, err := r.inner.ResolveReport(ctx, id, status, resolution, resolvedBy)
+	metrics.DBQueryDuration.WithLabelValues("ResolveReport", "reports").Observe(time.Since(start).Seconds())
+	return report, err
+}
+
+type InstrumentedDomainRepository struct {
+	inner DomainRepository
+}
+
+func NewInstrumentedDomainRepository(inner DomainRepository) DomainRepository {
+	return &InstrumentedDomainRepository{inner: inner}
+}
+
+func (r *InstrumentedDomainRepository) CreateDomain(ctx context.Context, domain *model.Domain) error {
+	start := time.Now()
+	err := r.inner.CreateDomain(ctx, domain)
+	metrics.DBQueryDuration.WithLabelValues("CreateDomain", "domains").Observe(time.Since(start).Seconds())
+	return err
+}
+
+func (r *InstrumentedDomainRepository) GetDomainByHostname(ctx context.Context, hostname string) (*model.Domain, error) {
+	start := time.Now()
+	domain, err := r.inner.GetDomainByHostname(ctx, hostname)
+	metrics.DBQueryDuration.WithLabelValues("GetDomainByHostname", "domains").Observe(time.Since(start).Seconds())
+	return domain, err
+}
+
+func (r *InstrumentedDomainRepository) ListUserDomains(ctx context.Context, userID string) ([]model.Domain, error) {
+	start := time.Now()
+	domains, err := r.inner.ListUserDomains(ctx, userID)
+	metrics.DBQueryDuration.WithLabelValues("ListUserDomains", "domains").Observe(time.Since(start).Seconds())
+	return domains, err
+}
+
+func (r *InstrumentedDomainRepository) MarkDomainVerified(ctx context.Context, id string) (*model.Domain, error) {
+	start := time.Now()
+	domain, err := r.inner.MarkDomainVerified(ctx, id)
+	metrics.DBQueryDuration.WithLabelValues("MarkDomainVerified", "domains").Observe(time.Since(start).Seconds())
+	return domain, err
+}
+
+func (r *InstrumentedDomainRepository) DeleteDomain(ctx context.Context, id string) error {
+	start := time.Now()
+	err := r.inner.DeleteDomain(ctx, id)
+	metrics.DBQueryDuration.WithLabelValues("DeleteDomain", "domains").Observe(time.Since(start).Seconds())
+	return err
+}
+
+type InstrumentedWorkspaceRepository struct {
+	inner WorkspaceRepository
+}
+
+func NewInstrumentedWorkspaceRepository(inner WorkspaceRepository) WorkspaceRepository {
+	return &InstrumentedWorkspaceRepository{inner: inner}
+}
+
+func (r *InstrumentedWorkspaceRepository) CreateWorkspace(ctx context.Context, workspace *model.Workspace) error {
+	start := time.Now()
+	err := r.inner.CreateWorkspace(ctx, workspace)
+	metrics.DBQueryDuration.WithLabelValues("CreateWorkspace", "workspaces").Observe(time.Since(start).Seconds())
+	return err
+}
+
+func (r *InstrumentedWorkspaceRepository) GetWorkspace(ctx context.Context, workspaceID string) (*model.Workspace, error) {
+	start := time.Now()
+	workspace, err := r.inner.GetWorkspace(ctx, workspaceID)
+	metrics.DBQueryDuration.WithLabelValues("GetWorkspace", "workspaces").Observe(time.Since(start).Seconds())
+	return workspace, err
+}
+
+func (r *InstrumentedWorkspaceRepository) UpdateWorkspace(ctx context.Context, workspaceID string, update model.WorkspaceUpdate) (*model.Workspace, error) {
+	start := time.Now()
+	workspace, err := r.inner.UpdateWorkspace(ctx, workspaceID, update)
+	metrics.DBQueryDuration.WithLabelValues("UpdateWorkspace", "workspaces").Observe(time.Since(start).Seconds())
+	return workspace, err
+}
+
+func (r *InstrumentedWorkspaceRepository) GetPersonalWorkspace(ctx context.Context, userID string) (*model.Workspace, error) {
+	start := time.Now()
+	workspace, err := r.inner.GetPersonalWorkspace(ctx, userID)
+	metrics.DBQueryDuration.WithLabelValues("GetPersonalWorkspace", "workspaces").Observe(time.Since(start).Seconds())
+	return workspace, err
+}
+
+func (r *InstrumentedWorkspaceRepository) ListUserWorkspaces(ctx context.Context, userID string) ([]model.WorkspaceMembership, error) {
+	start := time.Now()
+	workspaces, err := r.inner.ListUserWorkspaces(ctx, userID)
+	metrics.DBQueryDuration.WithLabelValues("ListUserWorkspaces", "workspace_members").Observe(time.Since(start).Seconds())
+	return workspaces, err
+}
+
+func (r *InstrumentedWorkspaceRepository) GetMember(ctx context.Context, workspaceID, userID string) (*model.WorkspaceMember, error) {
+	start := time.Now()
+	member, err := r.inner.GetMember(ctx, workspaceID, userID)
+	metrics.DBQueryDuration.WithLabelValues("GetMember", "workspace_members").Observe(time.Since(start).Seconds())
+	return member, err
+}
+
+func (r *InstrumentedWorkspaceRepository) ListMembers(ctx context.Context, workspaceID string) ([]model.WorkspaceMember, error) {
+	start := time.Now()
+	members, err := r.inner.ListMembers(ctx, workspaceID)
+	metrics.DBQueryDuration.WithLabelValues("ListMembers", "workspace_members").Observe(time.Since(start).Seconds())
+	return members, err
+}
+
+func (r *InstrumentedWorkspaceRepository) AddMember(ctx context.Context, member *model.WorkspaceMember) error {
+	start := time.Now()
+	err := r.inner.AddMember(ctx, member)
+	metrics.DBQueryDuration.WithLabelValues("AddMember", "workspace_members").Observe(time.Since(start).Seconds())
+	return err
+}
+
+func (r *InstrumentedWorkspaceRepository) UpdateMemberRole(ctx context.Context, workspaceID, userID string, role model.Role) (*model.WorkspaceMember, error) {
+	start := time.Now()
+	member, err := r.inner.UpdateMemberRole(ctx, workspaceID, userID, role)
+	metrics.DBQueryDuration.WithLabelValues("UpdateMemberRole", "workspace_members").Observe(time.Since(start).Seconds())
+	return member, err
+}
+
+func (r *InstrumentedWorkspaceRepository) RemoveMember(ctx context.Context, workspaceID, userID string) error {
+	start := time.Now()
+	err := r.inner.RemoveMember(ctx, workspaceID, userID)
+	metrics.DBQueryDuration.WithLabelValues("RemoveMember", "workspace_members").Observe(time.Since(start).Seconds())
+	return err
+}
+
+type InstrumentedWebhookRepository struct {
+	inner WebhookRepository
+}
+
+func NewInstrumentedWebhookRepository(inner WebhookRepository) WebhookRepository {
+	return &InstrumentedWebhookRepository{inner: inner}
+}
+
+func (r *InstrumentedWebhookRepository) CreateWebhook(ctx context.Context, hook *model.Webhook) error {
+	start := time.Now()
+	err := r.inner.CreateWebhook(ctx, hook)
+	metrics.DBQueryDuration.WithLabelValues("CreateWebhook", "webhooks").Observe(time.Since(start).Seconds())
+	return err
+}
+
+func (r *InstrumentedWebhookRepository) GetWebhook(ctx context.Context, id string) (*model.Webhook, error) {
+	start := time.Now()
+	hook, err := r.inner.GetWebhook(ctx, id)
+	metrics.DBQueryDuration.WithLabelValues("GetWebhook", "webhooks").Observe(time.Since(start).Seconds())
+	return hook, err
+}
+
+func (r *InstrumentedWebhookRepository) ListWorkspaceWebhooks(ctx context.Context, workspaceID string) ([]model.Webhook, error) {
+	start := time.Now()
+	hooks, err := r.inner.ListWorkspaceWebhooks(ctx, workspaceID)
+	metrics.DBQueryDuration.WithLabelValues("ListWorkspaceWebhooks", "webhooks").Observe(time.Since(start).Seconds())
+	return hooks, err
+}
+
+func (r *InstrumentedWebhookRepository) UpdateWebhook(ctx context.Context, id string, update model.WebhookUpdate) (*model.Webhook, error) {
+	start := time.Now()
+	hook, err := r.inner.UpdateWebhook(ctx, id, update)
+	metrics.DBQueryDuration.WithLabelValues("UpdateWebhook", "webhooks").Observe(time.Since(start).Seconds())
+	return hook, err
+}
+
+func (r *InstrumentedWebhookRepository) DeleteWebhook(ctx context.Context, id string) error {
+	start := time.Now()
+	err := r.inner.DeleteWebhook(ctx, id)
+	metrics.DBQueryDuration.WithLabelValues("DeleteWebhook", "webhooks").Observe(time.Since(start).Seconds())
+	return err
+}
+
+func (r *InstrumentedWebhookRepository) CreateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	start := time.Now()
+	err := r.inner.CreateDelivery(ctx, delivery)
+	metrics.DBQueryDuration.WithLabelValues("CreateDelivery", "webhook_deliveries").Observe(time.Since(start).Seconds())
+	return err
+}
+
+func (r *InstrumentedWebhookRepository) UpdateDelivery(ctx context.Context, delivery model.WebhookDelivery) error {
+	start := time.Now()
+	err := r.inner.UpdateDelivery(ctx, delivery)
+	metrics.DBQueryDuration.WithLabelValues("UpdateDelivery", "webhook_deliveries").Observe(time.Since(start).Seconds())
+	return err
+}
+
+func (r *InstrumentedWebhookRepository) ListDeliveries(ctx context.Context, webhookID, status string, limit int) ([]model.WebhookDelivery, error) {
+	start := time.Now()
+	deliveries, err := r.inner.ListDeliveries(ctx, webhookID, status, limit)
+	metrics.DBQueryDuration.WithLabelValues("ListDeliveries", "webhook_deliveries").Observe(time.Since(start).Seconds())
+	return deliveries, err
+}
+
+func (r *InstrumentedWebhookRepository) ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	start := time.Now()
+	deliveries, err := r.inner.ListDueDeliveries(ctx, now, limit)
+	metrics.DBQueryDuration.WithLabelValues("ListDueDeliveries", "webhook_deliveries").Observe(time.Since(start).Seconds())
+	return deliveries, err
+}
+
+func (r *InstrumentedWebhookRepository) DeleteDeliveriesBefore(ctx context.Context, before time.Time) error {
+	start := time.Now()
+	err := r.inner.DeleteDeliveriesBefore(ctx, before)
+	metrics.DBQueryDuration.WithLabelValues("DeleteDeliveriesBefore", "webhook_deliveries").Observe(time.Since(start).Seconds())
+	return err
+}
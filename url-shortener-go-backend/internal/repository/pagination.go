@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"url-shortener-go-backend/internal/model"
+	"url-shortener-go-backend/internal/utils"
+)
+
+func keysetFilter(column string, ascending bool, cursor *model.PageCursor) (string, error) {
+	if !utils.IsValidUUID(cursor.ID) {
+		return "", model.ErrInvalidCursor
+	}
+
+	value := cursor.Value
+	if column == "created_at" {
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return "", model.ErrInvalidCursor
+		}
+		value = t.UTC().Format(time.RFC3339Nano)
+	} else {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return "", model.ErrInvalidCursor
+		}
+		value = strconv.FormatInt(n, 10)
+	}
+
+	op := "lt"
+	if ascending {
+		op = "gt"
+	}
+	return fmt.Sprintf("%s.%s.%s,and(%s.eq.%s,id.%s.%s)", column, op, value, column, value, op, cursor.ID), nil
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"url-shortener-go-backend/internal/model"
+)
+
+type ReportRepository interface {
+	CreateReport(ctx context.Context, report *model.Report) error
+	GetReport(ctx context.Context, id string) (*model.Report, error)
+	ListReports(ctx context.Context, status string, limit int) ([]model.Report, error)
+	ResolveReport(ctx context.Context, id, status, resolution, resolvedBy string) (*model.Report, error)
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"url-shortener-go-backend/internal/model"
+	"url-shortener-go-backend/internal/utils"
+
+	"github.com/supabase-community/postgrest-go"
+)
+
+type ReportRepositoryImpl struct {
+	*SupabaseRepository
+}
+
+func NewReportRepository(baseRepo *SupabaseRepository) ReportRepository {
+	return &ReportRepositoryImpl{baseRepo}
+}
+
+func (r *ReportRepositoryImpl) CreateReport(ctx context.Context, report *model.Report) error {
+	data := map[string]interface{}{
+		"url_id":           report.URLID,
+		"short_code":       report.ShortCode,
+		"reason":           report.Reason,
+		"details":          report.Details,
+		"reporter_ip_hash": report.ReporterIPHash,
+		"status":           model.ReportStatusOpen,
+	}
+
+	if userID := report.ReporterUserID; userID != nil && *userID != "" {
+		data["reporter_user_id"] = *userID
+	}
+
+	resp, _, err := r.Client.
+		From("reports").
+		Insert(data, false, "", "", "").
+		Execute()
+
+	if err != nil {
+		slog.Error("report insert failed", "short_code", report.ShortCode, "error", err)
+		return fmt.Errorf("failed to save report: %w", err)
+	}
+
+	var inserted []model.Report
+	if err := json.Unmarshal(resp, &inserted); err != nil {
+		return fmt.Errorf("failed to decode inserted report: %w", err)
+	}
+
+	if len(inserted) == 0 {
+		return fmt.Errorf("no report returned after insert")
+	}
+
+	*report = inserted[0]
+	slog.Info("report saved", "id", report.ID, "short_code", report.ShortCode, "reason", report.Reason)
+	return nil
+}
+
+func (r *ReportRepositoryImpl) GetReport(ctx context.Context, id string) (*model.Report, error) {
+	resp, _, err := r.Client.
+		From("reports").
+		Select("*", "exact", false).
+		Eq("id", id).
+		Single().
+		Execute()
+
+	if err != nil {
+		errStr := strings.ToLower(err.Error())
+		if strings.Contains(errStr, "pgrst116") || strings.Contains(errStr, "no rows") || strings.Contains(errStr, "json object requested") {
+			return nil, utils.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch report: %w", err)
+	}
+
+	var report model.Report
+	if err := json.Unmarshal(resp, &report); err != nil {
+		return nil, fmt.Errorf("failed to decode report: %w", err)
+	}
+
+	return &report, nil
+}
+
+func (r *ReportRepositoryImpl) ListReports(ctx context.Context, status string, limit int) ([]model.Report, error) {
+	query := r.Client.
+		From("reports").
+		Select("*", "exact", false)
+
+	if status != "" {
+		query = query.Eq("status", status)
+	}
+
+	resp, _, err := query.
+		Order("created_at", &postgrest.OrderOpts{Ascending: true}).
+		Limit(limit, "").
+		Execute()
+
+	if err != nil {
+		return []model.Report{}, fmt.Errorf("failed to fetch reports: %w", err)
+	}
+
+	var reports []model.Report
+	if err := json.Unmarshal(resp, &reports); err != nil {
+		return []model.Report{}, fmt.Errorf("failed to decode reports: %w", err)
+	}
+
+	if reports == nil {
+		reports = []model.Report{}
+	}
+
+	return reports, nil
+}
+
+func (r *ReportRepositoryImpl) ResolveReport(ctx context.Context, id, status, resolution, resolvedBy string) (*model.Report, error) {
+	data := map[string]interface{}{
+		"status":      status,
+		"resolution":  resolution,
+		"resolved_by": resolvedBy,
+		"resolved_at": utils.NowUTC(),
+	}
+
+	resp, _, err := r.Client.
+		From("reports").
+		Update(data, "representation", "").
+		Eq("id", id).
+		Execute()
+
+	if err != nil {
+		slog.Error("report resolve failed", "id", id, "error", err)
+		return nil, fmt.Errorf("failed to resolve report: %w", err)
+	}
+
+	var updated []model.Report
+	if err := json.Unmarshal(resp, &updated); err != nil {
+		return nil, fmt.Errorf("failed to decode resolved report: %w", err)
+	}
+
+	if len(updated) == 0 {
+		return nil, utils.ErrNotFound
+	}
+
+	return &updated[0], nil
+}
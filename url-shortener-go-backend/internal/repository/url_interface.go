@@ -2,12 +2,25 @@ package repository
 
 import (
 	"context"
+	"time"
+
+	"url-shortener-go-backend/internal/metadata"
 	"url-shortener-go-backend/internal/model"
 )
 
 type URLRepository interface {
 	SaveURL(ctx context.Context, url *model.URL) error
 	GetURLByShortCode(ctx context.Context, shortcode string) (*model.URL, error)
-	GetUserUrls(ctx context.Context, userID string) ([]model.URL, error)
+	FindURLByDestination(ctx context.Context, workspaceID, domain, destinationHash string, isPublic bool) (*model.URL, error)
+	GetWorkspaceURLs(ctx context.Context, workspaceID string, q model.URLQuery) (model.Page[model.URL], error)
 	IncrementClickCount(ctx context.Context, shortcode string) error
+	SetURLDisabled(ctx context.Context, shortcode, reason string, disabled bool) (*model.URL, error)
+	DeleteURL(ctx context.Context, shortcode string) error
+	ListActiveURLs(ctx context.Context, afterID string, limit int) ([]model.URL, error)
+	UpdateURL(ctx context.Context, shortcode string, update model.URLUpdate) (*model.URL, error)
+	SetURLMetadata(ctx context.Context, shortcode string, meta metadata.Metadata) (*model.URL, error)
+	ListDueHealthChecks(ctx context.Context, now time.Time, afterID string, limit int) ([]model.URL, error)
+	SetURLHealth(ctx context.Context, shortcode string, health model.LinkHealth) (*model.URL, error)
+	ListUnnotifiedExpiredURLs(ctx context.Context, now time.Time, afterID string, limit int) ([]model.URL, error)
+	MarkExpiryNotified(ctx context.Context, id string) (bool, error)
 }
@@ -5,12 +5,21 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"strconv"
 	"strings"
+	"time"
 
+	"url-shortener-go-backend/internal/metadata"
 	"url-shortener-go-backend/internal/model"
 	"url-shortener-go-backend/internal/utils"
+
+	"github.com/supabase-community/postgrest-go"
 )
 
+const urlColumns = "id, user_id, workspace_id, original_url, normalized_url, destination_hash, short_code, click_count, is_public, created_at, disabled_at, disabled_reason, resolved_url, resolved_status, require_preview, title, description, image_url, favicon_url, metadata_fetched_at, og_title, og_description, og_image, redirect_status, cache_max_age, no_store, forward_query, rules, variants, deep_links, domain, tags, folder, expires_at, health_status, health_failures, health_checked_at, health_next_check_at"
+
+const urlHealthColumns = urlColumns + ", health_history"
+
 type URLRepositoryImpl struct {
 	*SupabaseRepository
 	shortDomain string
@@ -21,10 +30,9 @@ func NewURLRepository(baseRepo *SupabaseRepository, shortDomain string) URLRepos
 }
 
 func (u *URLRepositoryImpl) GetURLByShortCode(ctx context.Context, shortcode string) (*model.URL, error) {
-	resp, _, err := u.Client.
+	resp, _, err := matchLink(u.Client.
 		From("urls").
-		Select("id, original_url, short_code, click_count, is_public, created_at", "exact", false).
-		Eq("short_code", shortcode).
+		Select(urlColumns, "exact", false), shortcode).
 		Single().
 		Execute()
 
@@ -50,37 +58,153 @@ func (u *URLRepositoryImpl) GetURLByShortCode(ctx context.Context, shortcode str
 	return &url, nil
 }
 
-func (u *URLRepositoryImpl) GetUserUrls(ctx context.Context, userID string) ([]model.URL, error) {
-	resp, _, err := u.Client.
+func (u *URLRepositoryImpl) FindURLByDestination(ctx context.Context, workspaceID, domain, destinationHash string, isPublic bool) (*model.URL, error) {
+	query := u.Client.
 		From("urls").
-		Select("*", "exact", false).
-		Eq("user_id", userID).
+		Select(urlColumns, "exact", false).
+		Eq("destination_hash", destinationHash).
+		Is("is_public", strconv.FormatBool(isPublic)).
+		Is("disabled_at", "null").
+		Or("expires_at.is.null,expires_at.gt."+time.Now().UTC().Format(time.RFC3339), "")
+
+	if workspaceID == "" {
+		query = query.Is("workspace_id", "null")
+	} else {
+		query = query.Eq("workspace_id", workspaceID)
+	}
+	if domain == "" {
+		query = query.Is("domain", "null")
+	} else {
+		query = query.Eq("domain", domain)
+	}
+
+	resp, _, err := query.
+		Order("created_at", &postgrest.OrderOpts{Ascending: false}).
+		Limit(1, "").
 		Execute()
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch user URLs: %w", err)
+		return nil, fmt.Errorf("failed to look up URL by destination: %w", err)
 	}
 
 	var urls []model.URL
 	if err := json.Unmarshal(resp, &urls); err != nil {
-		return nil, fmt.Errorf("failed to decode URLs: %w", err)
+		return nil, fmt.Errorf("failed to decode URL: %w", err)
 	}
 
-	if urls == nil {
-		urls = []model.URL{}
+	if len(urls) == 0 {
+		return nil, utils.ErrNotFound
 	}
 
-	for i := range urls {
-		urls[i].PopulateShortURL(u.shortDomain)
+	url := urls[0]
+	url.PopulateShortURL(u.shortDomain)
+	return &url, nil
+}
+
+func (u *URLRepositoryImpl) GetWorkspaceURLs(ctx context.Context, workspaceID string, q model.URLQuery) (model.Page[model.URL], error) {
+	columns := urlColumns
+	if q.Health != "" {
+		columns = urlHealthColumns
 	}
 
-	return urls, nil
+	query := u.Client.
+		From("urls").
+		Select(columns, "exact", false).
+		Eq("workspace_id", workspaceID)
+
+	if q.Search != "" {
+		query = query.TextSearch("search", q.Search, "simple", "websearch")
+	}
+	if len(q.Tags) > 0 {
+		query = query.Contains("tags", q.Tags)
+	}
+	if q.Folder != "" {
+		query = query.Eq("folder", q.Folder)
+	}
+	if q.Health != "" {
+		query = query.Eq("health_status", q.Health)
+	}
+	switch q.Visibility {
+	case model.VisibilityPublic:
+		query = query.Is("is_public", "true")
+	case model.VisibilityPrivate:
+		query = query.Is("is_public", "false")
+	}
+
+	var conditions []string
+	if q.CreatedFrom != nil {
+		conditions = append(conditions, "created_at.gte."+q.CreatedFrom.UTC().Format(time.RFC3339))
+	}
+	if q.CreatedTo != nil {
+		conditions = append(conditions, "created_at.lte."+q.CreatedTo.UTC().Format(time.RFC3339))
+	}
+	if q.Expired != nil {
+		now := time.Now().UTC().Format(time.RFC3339)
+		if *q.Expired {
+			conditions = append(conditions, "expires_at.lte."+now)
+		} else {
+			conditions = append(conditions, "or(expires_at.is.null,expires_at.gt."+now+")")
+		}
+	}
+	if q.Cursor != nil {
+		filter, err := keysetFilter(q.SortColumn(), q.Ascending, q.Cursor)
+		if err != nil {
+			return model.Page[model.URL]{}, err
+		}
+		conditions = append(conditions, "or("+filter+")")
+	}
+	if len(conditions) > 0 {
+		query = query.And(strings.Join(conditions, ","), "")
+	}
+
+	limit := q.EffectiveLimit()
+	resp, _, err := query.
+		Order(q.SortColumn(), &postgrest.OrderOpts{Ascending: q.Ascending}).
+		Order("id", &postgrest.OrderOpts{Ascending: q.Ascending}).
+		Limit(limit+1, "").
+		Execute()
+
+	if err != nil {
+		return model.Page[model.URL]{}, fmt.Errorf("failed to fetch workspace URLs: %w", err)
+	}
+
+	var urls []model.URL
+	if err := json.Unmarshal(resp, &urls); err != nil {
+		return model.Page[model.URL]{}, fmt.Errorf("failed to decode URLs: %w", err)
+	}
+
+	page := model.Page[model.URL]{Items: urls}
+	if len(urls) > limit {
+		page.Items = urls[:limit]
+		last := page.Items[limit-1]
+		cursor := model.PageCursor{Sort: q.SortKey(), Value: last.CreatedAt.UTC().Format(time.RFC3339Nano), ID: last.ID}
+		if q.Sort == model.URLSortClicks {
+			cursor.Value = strconv.Itoa(last.ClickCount)
+		}
+		page.NextCursor = cursor.Encode()
+	}
+
+	if page.Items == nil {
+		page.Items = []model.URL{}
+	}
+
+	for i := range page.Items {
+		page.Items[i].PopulateShortURL(u.shortDomain)
+	}
+
+	return page, nil
 }
 
 func (u *URLRepositoryImpl) IncrementClickCount(ctx context.Context, shortcode string) error {
-	err := u.Client.Rpc("increment_click_count", "", map[string]any{
-		"sc": shortcode,
-	})
+	domain, code := model.SplitLinkKey(shortcode)
+	params := map[string]any{
+		"sc":  code,
+		"dom": nil,
+	}
+	if domain != "" {
+		params["dom"] = domain
+	}
+	err := u.Client.Rpc("increment_click_count", "", params)
 
 	if err != "" {
 		slog.Error("rpc increment_click_count failed", "shortcode", shortcode, "error", err)
@@ -98,16 +222,76 @@ func (u *URLRepositoryImpl) SaveURL(ctx context.Context, url *model.URL) error {
 		"click_count":  url.ClickCount,
 	}
 
+	if url.Domain != "" {
+		data["domain"] = url.Domain
+	}
+
+	if url.NormalizedURL != "" {
+		data["normalized_url"] = url.NormalizedURL
+	}
+	if url.DestinationHash != "" {
+		data["destination_hash"] = url.DestinationHash
+	}
+
 	if userID := url.UserID; userID != nil && *userID != "" {
 		data["user_id"] = *userID
 	}
 
+	if workspaceID := url.WorkspaceID; workspaceID != nil && *workspaceID != "" {
+		data["workspace_id"] = *workspaceID
+	}
+
+	if url.ResolvedURL != "" {
+		data["resolved_url"] = url.ResolvedURL
+		data["resolved_status"] = url.ResolvedStatus
+	}
+
+	if url.RequirePreview {
+		data["require_preview"] = true
+	}
+
+	if url.RedirectStatus != 0 {
+		data["redirect_status"] = url.RedirectStatus
+	}
+	if url.CacheMaxAge > 0 {
+		data["cache_max_age"] = url.CacheMaxAge
+	}
+	if url.NoStore {
+		data["no_store"] = true
+	}
+	if url.ForwardQuery != "" {
+		data["forward_query"] = url.ForwardQuery
+	}
+	if len(url.Rules) > 0 {
+		data["rules"] = url.Rules
+	}
+	if len(url.Variants) > 0 {
+		data["variants"] = url.Variants
+	}
+	if !url.DeepLinks.IsEmpty() {
+		data["deep_links"] = url.DeepLinks
+	}
+	if len(url.Tags) > 0 {
+		data["tags"] = url.Tags
+	}
+	if url.Folder != "" {
+		data["folder"] = url.Folder
+	}
+	if url.ExpiresAt != nil {
+		data["expires_at"] = url.ExpiresAt.UTC()
+	}
+
 	resp, _, err := u.Client.
 		From("urls").
 		Insert(data, false, "", "", "").
 		Execute()
 
 	if err != nil {
+		msg := strings.ToLower(err.Error())
+		if strings.Contains(msg, "duplicate key") || strings.Contains(msg, "unique constraint") {
+			slog.Warn("unique constraint violation on url insert", "error", err)
+			return ErrUniqueViolation
+		}
 		slog.Error("url insert failed", "error", err)
 		return fmt.Errorf("supabase insert failed: %w", err)
 	}
@@ -148,3 +332,292 @@ func (u *URLRepositoryImpl) SaveURL(ctx context.Context, url *model.URL) error {
 	slog.Info("url insert successful", "id", url.ID, "short_code", url.ShortCode)
 	return nil
 }
+
+func (u *URLRepositoryImpl) SetURLDisabled(ctx context.Context, shortcode, reason string, disabled bool) (*model.URL, error) {
+	data := map[string]interface{}{
+		"disabled_at":     nil,
+		"disabled_reason": nil,
+	}
+	if disabled {
+		data["disabled_at"] = utils.NowUTC()
+		data["disabled_reason"] = reason
+	}
+
+	resp, _, err := matchLink(u.Client.
+		From("urls").
+		Update(data, "representation", ""), shortcode).
+		Execute()
+
+	if err != nil {
+		slog.Error("url disable update failed", "shortcode", shortcode, "error", err)
+		return nil, fmt.Errorf("failed to update URL status: %w", err)
+	}
+
+	var updated []model.URL
+	if err := json.Unmarshal(resp, &updated); err != nil {
+		return nil, fmt.Errorf("failed to decode updated URL: %w", err)
+	}
+
+	if len(updated) == 0 {
+		return nil, utils.ErrNotFound
+	}
+
+	url := updated[0]
+	url.PopulateShortURL(u.shortDomain)
+	return &url, nil
+}
+
+func (u *URLRepositoryImpl) ListActiveURLs(ctx context.Context, afterID string, limit int) ([]model.URL, error) {
+	query := u.Client.
+		From("urls").
+		Select(urlColumns, "exact", false).
+		Is("disabled_at", "null")
+
+	if afterID != "" {
+		query = query.Gt("id", afterID)
+	}
+
+	resp, _, err := query.
+		Order("id", &postgrest.OrderOpts{Ascending: true}).
+		Limit(limit, "").
+		Execute()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch active URLs: %w", err)
+	}
+
+	var urls []model.URL
+	if err := json.Unmarshal(resp, &urls); err != nil {
+		return nil, fmt.Errorf("failed to decode active URLs: %w", err)
+	}
+
+	if urls == nil {
+		urls = []model.URL{}
+	}
+
+	return urls, nil
+}
+
+func (u *URLRepositoryImpl) ListDueHealthChecks(ctx context.Context, now time.Time, afterID string, limit int) ([]model.URL, error) {
+	ts := now.UTC().Format(time.RFC3339)
+	query := u.Client.
+		From("urls").
+		Select(urlHealthColumns, "exact", false).
+		Is("disabled_at", "null").
+		And(fmt.Sprintf("or(health_next_check_at.is.null,health_next_check_at.lte.%s),or(expires_at.is.null,expires_at.gt.%s)", ts, ts), "")
+
+	if afterID != "" {
+		query = query.Gt("id", afterID)
+	}
+
+	resp, _, err := query.
+		Order("id", &postgrest.OrderOpts{Ascending: true}).
+		Limit(limit, "").
+		Execute()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch URLs due for health check: %w", err)
+	}
+
+	var urls []model.URL
+	if err := json.Unmarshal(resp, &urls); err != nil {
+		return nil, fmt.Errorf("failed to decode URLs due for health check: %w", err)
+	}
+
+	if urls == nil {
+		urls = []model.URL{}
+	}
+
+	return urls, nil
+}
+
+func (u *URLRepositoryImpl) ListUnnotifiedExpiredURLs(ctx context.Context, now time.Time, afterID string, limit int) ([]model.URL, error) {
+	query := u.Client.
+		From("urls").
+		Select(urlColumns, "exact", false).
+		Lte("expires_at", now.UTC().Format(time.RFC3339)).
+		Is("expiry_notified_at", "null")
+
+	if afterID != "" {
+		query = query.Gt("id", afterID)
+	}
+
+	resp, _, err := query.
+		Order("id", &postgrest.OrderOpts{Ascending: true}).
+		Limit(limit, "").
+		Execute()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch expired URLs: %w", err)
+	}
+
+	var urls []model.URL
+	if err := json.Unmarshal(resp, &urls); err != nil {
+		return nil, fmt.Errorf("failed to decode expired URLs: %w", err)
+	}
+
+	if urls == nil {
+		urls = []model.URL{}
+	}
+
+	for i := range urls {
+		urls[i].PopulateShortURL(u.shortDomain)
+	}
+
+	return urls, nil
+}
+
+func (u *URLRepositoryImpl) MarkExpiryNotified(ctx context.Context, id string) (bool, error) {
+	resp, _, err := u.Client.
+		From("urls").
+		Update(map[string]interface{}{"expiry_notified_at": utils.NowUTC()}, "representation", "").
+		Eq("id", id).
+		Is("expiry_notified_at", "null").
+		Execute()
+
+	if err != nil {
+		slog.Error("url expiry mark failed", "id", id, "error", err)
+		return false, fmt.Errorf("failed to mark URL expiry notified: %w", err)
+	}
+
+	var updated []struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(resp, &updated); err != nil {
+		return false, fmt.Errorf("failed to decode marked URL: %w", err)
+	}
+
+	return len(updated) > 0, nil
+}
+
+func (u *URLRepositoryImpl) UpdateURL(ctx context.Context, shortcode string, update model.URLUpdate) (*model.URL, error) {
+	data := map[string]interface{}{}
+	if update.RequirePreview != nil {
+		data["require_preview"] = *update.RequirePreview
+	}
+	if update.OGTitle != nil {
+		data["og_title"] = *update.OGTitle
+	}
+	if update.OGDescription != nil {
+		data["og_description"] = *update.OGDescription
+	}
+	if update.OGImage != nil {
+		data["og_image"] = *update.OGImage
+	}
+	if update.RedirectStatus != nil {
+		data["redirect_status"] = *update.RedirectStatus
+	}
+	if update.CacheMaxAge != nil {
+		data["cache_max_age"] = *update.CacheMaxAge
+	}
+	if update.NoStore != nil {
+		data["no_store"] = *update.NoStore
+	}
+	if update.ForwardQuery != nil {
+		data["forward_query"] = *update.ForwardQuery
+	}
+	if update.Rules != nil {
+		data["rules"] = *update.Rules
+	}
+	if update.Variants != nil {
+		data["variants"] = *update.Variants
+	}
+	if update.DeepLinks != nil {
+		if update.DeepLinks.IsEmpty() {
+			data["deep_links"] = nil
+		} else {
+			data["deep_links"] = update.DeepLinks
+		}
+	}
+	if update.Tags != nil {
+		data["tags"] = *update.Tags
+	}
+	if update.Folder != nil {
+		if *update.Folder == "" {
+			data["folder"] = nil
+		} else {
+			data["folder"] = *update.Folder
+		}
+	}
+	if update.ClearExpiry {
+		data["expires_at"] = nil
+		data["expiry_notified_at"] = nil
+	} else if update.ExpiresAt != nil {
+		data["expires_at"] = update.ExpiresAt.UTC()
+		data["expiry_notified_at"] = nil
+	}
+
+	return u.updateURL(ctx, shortcode, data)
+}
+
+func (u *URLRepositoryImpl) updateURL(ctx context.Context, shortcode string, data map[string]interface{}) (*model.URL, error) {
+	resp, _, err := matchLink(u.Client.
+		From("urls").
+		Update(data, "representation", ""), shortcode).
+		Execute()
+
+	if err != nil {
+		slog.Error("url update failed", "shortcode", shortcode, "error", err)
+		return nil, fmt.Errorf("failed to update URL: %w", err)
+	}
+
+	var updated []model.URL
+	if err := json.Unmarshal(resp, &updated); err != nil {
+		return nil, fmt.Errorf("failed to decode updated URL: %w", err)
+	}
+
+	if len(updated) == 0 {
+		return nil, utils.ErrNotFound
+	}
+
+	url := updated[0]
+	url.PopulateShortURL(u.shortDomain)
+	return &url, nil
+}
+
+func (u *URLRepositoryImpl) DeleteURL(ctx context.Context, shortcode string) error {
+	_, _, err := matchLink(u.Client.
+		From("urls").
+		Delete("", ""), shortcode).
+		Execute()
+
+	if err != nil {
+		slog.Error("url delete failed", "shortcode", shortcode, "error", err)
+		return fmt.Errorf("failed to delete URL: %w", err)
+	}
+
+	return nil
+}
+
+func (u *URLRepositoryImpl) SetURLMetadata(ctx context.Context, shortcode string, meta metadata.Metadata) (*model.URL, error) {
+	data := map[string]interface{}{
+		"title":               meta.Title,
+		"description":         meta.Description,
+		"image_url":           meta.ImageURL,
+		"favicon_url":         meta.FaviconURL,
+		"metadata_fetched_at": utils.NowUTC(),
+	}
+
+	return u.updateURL(ctx, shortcode, data)
+}
+
+func (u *URLRepositoryImpl) SetURLHealth(ctx context.Context, shortcode string, health model.LinkHealth) (*model.URL, error) {
+	data := map[string]interface{}{
+		"health_status":        health.Status,
+		"health_failures":      health.Failures,
+		"health_checked_at":    health.CheckedAt.UTC(),
+		"health_next_check_at": health.NextCheckAt.UTC(),
+		"health_history":       health.History,
+	}
+
+	return u.updateURL(ctx, shortcode, data)
+}
+
+func matchLink(query *postgrest.FilterBuilder, key string) *postgrest.FilterBuilder {
+	domain, code := model.SplitLinkKey(key)
+	query = query.Eq("short_code", code)
+	if domain == "" {
+		return query.Is("domain", "null")
+	}
+	return query.Eq("domain", domain)
+}
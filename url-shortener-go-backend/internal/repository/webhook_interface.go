@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"url-shortener-go-backend/internal/model"
+)
+
+type WebhookRepository interface {
+	CreateWebhook(ctx context.Context, hook *model.Webhook) error
+	GetWebhook(ctx context.Context, id string) (*model.Webhook, error)
+	ListWorkspaceWebhooks(ctx context.Context, workspaceID string) ([]model.Webhook, error)
+	UpdateWebhook(ctx context.Context, id string, update model.WebhookUpdate) (*model.Webhook, error)
+	DeleteWebhook(ctx context.Context, id string) error
+	CreateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error
+	UpdateDelivery(ctx context.Context, delivery model.WebhookDelivery) error
+	ListDeliveries(ctx context.Context, webhookID, status string, limit int) ([]model.WebhookDelivery, error)
+	ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]model.WebhookDelivery, error)
+	DeleteDeliveriesBefore(ctx context.Context, before time.Time) error
+}
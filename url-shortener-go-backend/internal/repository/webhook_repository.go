@@ -0,0 +1,316 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"url-shortener-go-backend/internal/model"
+	"url-shortener-go-backend/internal/utils"
+
+	"github.com/supabase-community/postgrest-go"
+)
+
+type WebhookRepositoryImpl struct {
+	*SupabaseRepository
+}
+
+type webhookRow struct {
+	model.Webhook
+	Secret string `json:"secret"`
+}
+
+func (r webhookRow) toModel() model.Webhook {
+	hook := r.Webhook
+	hook.Secret = r.Secret
+	return hook
+}
+
+func NewWebhookRepository(baseRepo *SupabaseRepository) WebhookRepository {
+	return &WebhookRepositoryImpl{baseRepo}
+}
+
+func (w *WebhookRepositoryImpl) CreateWebhook(ctx context.Context, hook *model.Webhook) error {
+	data := map[string]interface{}{
+		"workspace_id": hook.WorkspaceID,
+		"url":          hook.URL,
+		"description":  hook.Description,
+		"secret":       hook.Secret,
+		"events":       hook.Events,
+		"active":       hook.Active,
+		"created_by":   hook.CreatedBy,
+	}
+
+	resp, _, err := w.Client.
+		From("webhooks").
+		Insert(data, false, "", "", "").
+		Execute()
+
+	if err != nil {
+		slog.Error("webhook insert failed", "workspace_id", hook.WorkspaceID, "error", err)
+		return fmt.Errorf("failed to save webhook: %w", err)
+	}
+
+	var inserted []webhookRow
+	if err := json.Unmarshal(resp, &inserted); err != nil {
+		return fmt.Errorf("failed to decode inserted webhook: %w", err)
+	}
+
+	if len(inserted) == 0 {
+		return fmt.Errorf("no webhook returned after insert")
+	}
+
+	*hook = inserted[0].toModel()
+	slog.Info("webhook saved", "id", hook.ID, "workspace_id", hook.WorkspaceID)
+	return nil
+}
+
+func (w *WebhookRepositoryImpl) GetWebhook(ctx context.Context, id string) (*model.Webhook, error) {
+	resp, _, err := w.Client.
+		From("webhooks").
+		Select("*", "exact", false).
+		Eq("id", id).
+		Single().
+		Execute()
+
+	if err != nil {
+		errStr := strings.ToLower(err.Error())
+		if strings.Contains(errStr, "pgrst116") || strings.Contains(errStr, "no rows") || strings.Contains(errStr, "json object requested") {
+			return nil, utils.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch webhook: %w", err)
+	}
+
+	var row webhookRow
+	if err := json.Unmarshal(resp, &row); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook: %w", err)
+	}
+
+	hook := row.toModel()
+	return &hook, nil
+}
+
+func (w *WebhookRepositoryImpl) ListWorkspaceWebhooks(ctx context.Context, workspaceID string) ([]model.Webhook, error) {
+	resp, _, err := w.Client.
+		From("webhooks").
+		Select("*", "exact", false).
+		Eq("workspace_id", workspaceID).
+		Order("created_at", &postgrest.OrderOpts{Ascending: true}).
+		Execute()
+
+	if err != nil {
+		return []model.Webhook{}, fmt.Errorf("failed to fetch webhooks: %w", err)
+	}
+
+	var rows []webhookRow
+	if err := json.Unmarshal(resp, &rows); err != nil {
+		return []model.Webhook{}, fmt.Errorf("failed to decode webhooks: %w", err)
+	}
+
+	hooks := make([]model.Webhook, 0, len(rows))
+	for _, row := range rows {
+		hooks = append(hooks, row.toModel())
+	}
+
+	return hooks, nil
+}
+
+func (w *WebhookRepositoryImpl) UpdateWebhook(ctx context.Context, id string, update model.WebhookUpdate) (*model.Webhook, error) {
+	data := map[string]interface{}{}
+	if update.URL != nil {
+		data["url"] = *update.URL
+	}
+	if update.Description != nil {
+		data["description"] = *update.Description
+	}
+	if update.Events != nil {
+		data["events"] = *update.Events
+	}
+	if update.Active != nil {
+		data["active"] = *update.Active
+	}
+	if update.Secret != nil {
+		data["secret"] = *update.Secret
+	}
+
+	resp, _, err := w.Client.
+		From("webhooks").
+		Update(data, "representation", "").
+		Eq("id", id).
+		Execute()
+
+	if err != nil {
+		slog.Error("webhook update failed", "id", id, "error", err)
+		return nil, fmt.Errorf("failed to update webhook: %w", err)
+	}
+
+	var updated []webhookRow
+	if err := json.Unmarshal(resp, &updated); err != nil {
+		return nil, fmt.Errorf("failed to decode updated webhook: %w", err)
+	}
+
+	if len(updated) == 0 {
+		return nil, utils.ErrNotFound
+	}
+
+	hook := updated[0].toModel()
+	return &hook, nil
+}
+
+func (w *WebhookRepositoryImpl) DeleteWebhook(ctx context.Context, id string) error {
+	_, _, err := w.Client.
+		From("webhooks").
+		Delete("", "").
+		Eq("id", id).
+		Execute()
+
+	if err != nil {
+		slog.Error("webhook delete failed", "id", id, "error", err)
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	return nil
+}
+
+func (w *WebhookRepositoryImpl) CreateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	data := map[string]interface{}{
+		"webhook_id": delivery.WebhookID,
+		"event_id":   delivery.EventID,
+		"event_type": delivery.EventType,
+		"payload":    delivery.Payload,
+		"status":     delivery.Status,
+		"attempts":   delivery.Attempts,
+	}
+	if delivery.NextAttemptAt != nil {
+		data["next_attempt_at"] = delivery.NextAttemptAt.UTC()
+	}
+
+	resp, _, err := w.Client.
+		From("webhook_deliveries").
+		Insert(data, false, "", "", "").
+		Execute()
+
+	if err != nil {
+		slog.Error("webhook delivery insert failed", "webhook_id", delivery.WebhookID, "event_id", delivery.EventID, "error", err)
+		return fmt.Errorf("failed to save webhook delivery: %w", err)
+	}
+
+	var inserted []model.WebhookDelivery
+	if err := json.Unmarshal(resp, &inserted); err != nil {
+		return fmt.Errorf("failed to decode inserted webhook delivery: %w", err)
+	}
+
+	if len(inserted) == 0 {
+		return fmt.Errorf("no webhook delivery returned after insert")
+	}
+
+	*delivery = inserted[0]
+	return nil
+}
+
+func (w *WebhookRepositoryImpl) UpdateDelivery(ctx context.Context, delivery model.WebhookDelivery) error {
+	data := map[string]interface{}{
+		"status":          delivery.Status,
+		"attempts":        delivery.Attempts,
+		"response_status": delivery.ResponseStatus,
+		"error":           delivery.Error,
+		"duration_ms":     delivery.DurationMS,
+		"next_attempt_at": nil,
+		"delivered_at":    nil,
+		"updated_at":      utils.NowUTC(),
+	}
+	if delivery.NextAttemptAt != nil {
+		data["next_attempt_at"] = delivery.NextAttemptAt.UTC()
+	}
+	if delivery.DeliveredAt != nil {
+		data["delivered_at"] = delivery.DeliveredAt.UTC()
+	}
+
+	_, _, err := w.Client.
+		From("webhook_deliveries").
+		Update(data, "minimal", "").
+		Eq("id", delivery.ID).
+		Execute()
+
+	if err != nil {
+		slog.Error("webhook delivery update failed", "id", delivery.ID, "error", err)
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+func (w *WebhookRepositoryImpl) ListDeliveries(ctx context.Context, webhookID, status string, limit int) ([]model.WebhookDelivery, error) {
+	query := w.Client.
+		From("webhook_deliveries").
+		Select("*", "exact", false).
+		Eq("webhook_id", webhookID)
+
+	if status != "" {
+		query = query.Eq("status", status)
+	}
+
+	resp, _, err := query.
+		Order("created_at", &postgrest.OrderOpts{Ascending: false}).
+		Limit(limit, "").
+		Execute()
+
+	if err != nil {
+		return []model.WebhookDelivery{}, fmt.Errorf("failed to fetch webhook deliveries: %w", err)
+	}
+
+	var deliveries []model.WebhookDelivery
+	if err := json.Unmarshal(resp, &deliveries); err != nil {
+		return []model.WebhookDelivery{}, fmt.Errorf("failed to decode webhook deliveries: %w", err)
+	}
+
+	if deliveries == nil {
+		deliveries = []model.WebhookDelivery{}
+	}
+
+	return deliveries, nil
+}
+
+func (w *WebhookRepositoryImpl) ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	resp, _, err := w.Client.
+		From("webhook_deliveries").
+		Select("*", "exact", false).
+		Eq("status", model.DeliveryPending).
+		Lte("next_attempt_at", now.UTC().Format(time.RFC3339)).
+		Order("next_attempt_at", &postgrest.OrderOpts{Ascending: true}).
+		Limit(limit, "").
+		Execute()
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch due webhook deliveries: %w", err)
+	}
+
+	var deliveries []model.WebhookDelivery
+	if err := json.Unmarshal(resp, &deliveries); err != nil {
+		return nil, fmt.Errorf("failed to decode due webhook deliveries: %w", err)
+	}
+
+	if deliveries == nil {
+		deliveries = []model.WebhookDelivery{}
+	}
+
+	return deliveries, nil
+}
+
+func (w *WebhookRepositoryImpl) DeleteDeliveriesBefore(ctx context.Context, before time.Time) error {
+	_, _, err := w.Client.
+		From("webhook_deliveries").
+		Delete("minimal", "").
+		Lt("created_at", before.UTC().Format(time.RFC3339)).
+		Execute()
+
+	if err != nil {
+		slog.Error("webhook delivery cleanup failed", "before", before, "error", err)
+		return fmt.Errorf("failed to delete old webhook deliveries: %w", err)
+	}
+
+	return nil
+}
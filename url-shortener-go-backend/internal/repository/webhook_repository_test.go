@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestWebhookRowDecodesSecret(t *testing.T) {
+	var row webhookRow
+	if err := json.Unmarshal([]byte(`{"id":"w1","workspace_id":"ws","url":"https://hooks.example.com","secret":"whsec_abc","events":["link.created"],"active":true}`), &row); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	hook := row.toModel()
+	if hook.Secret != "whsec_abc" || hook.ID != "w1" || !hook.Active {
+		t.Fatalf("toModel = %+v", hook)
+	}
+
+	encoded, err := json.Marshal(hook)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		t.Fatalf("Unmarshal encoded webhook: %v", err)
+	}
+	if _, ok := fields["secret"]; ok {
+		t.Fatalf("encoded webhook leaks the secret: %s", encoded)
+	}
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"url-shortener-go-backend/internal/model"
+)
+
+type WorkspaceRepository interface {
+	CreateWorkspace(ctx context.Context, workspace *model.Workspace) error
+	GetWorkspace(ctx context.Context, workspaceID string) (*model.Workspace, error)
+	UpdateWorkspace(ctx context.Context, workspaceID string, update model.WorkspaceUpdate) (*model.Workspace, error)
+	GetPersonalWorkspace(ctx context.Context, userID string) (*model.Workspace, error)
+	ListUserWorkspaces(ctx context.Context, userID string) ([]model.WorkspaceMembership, error)
+	GetMember(ctx context.Context, workspaceID, userID string) (*model.WorkspaceMember, error)
+	ListMembers(ctx context.Context, workspaceID string) ([]model.WorkspaceMember, error)
+	AddMember(ctx context.Context, member *model.WorkspaceMember) error
+	UpdateMemberRole(ctx context.Context, workspaceID, userID string, role model.Role) (*model.WorkspaceMember, error)
+	RemoveMember(ctx context.Context, workspaceID, userID string) error
+}
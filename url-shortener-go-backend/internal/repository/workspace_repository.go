@@ -0,0 +1,306 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"url-shortener-go-backend/internal/model"
+	"url-shortener-go-backend/internal/utils"
+
+	"github.com/supabase-community/postgrest-go"
+)
+
+type WorkspaceRepositoryImpl struct {
+	*SupabaseRepository
+}
+
+func NewWorkspaceRepository(baseRepo *SupabaseRepository) WorkspaceRepository {
+	return &WorkspaceRepositoryImpl{baseRepo}
+}
+
+func (w *WorkspaceRepositoryImpl) CreateWorkspace(ctx context.Context, workspace *model.Workspace) error {
+	data := map[string]interface{}{
+		"name":       workspace.Name,
+		"created_by": workspace.CreatedBy,
+	}
+	if workspace.PersonalOwner != nil {
+		data["personal_owner"] = *workspace.PersonalOwner
+	}
+
+	resp, _, err := w.Client.
+		From("workspaces").
+		Insert(data, false, "", "", "").
+		Execute()
+
+	if err != nil {
+		msg := strings.ToLower(err.Error())
+		if strings.Contains(msg, "duplicate key") || strings.Contains(msg, "unique constraint") {
+			return ErrUniqueViolation
+		}
+		slog.Error("workspace insert failed", "created_by", workspace.CreatedBy, "error", err)
+		return fmt.Errorf("failed to save workspace: %w", err)
+	}
+
+	var inserted []model.Workspace
+	if err := json.Unmarshal(resp, &inserted); err != nil {
+		return fmt.Errorf("failed to decode inserted workspace: %w", err)
+	}
+
+	if len(inserted) == 0 {
+		return fmt.Errorf("no workspace returned after insert")
+	}
+
+	*workspace = inserted[0]
+
+	owner := &model.WorkspaceMember{
+		WorkspaceID: workspace.ID,
+		UserID:      workspace.CreatedBy,
+		Role:        model.RoleOwner,
+	}
+	if err := w.AddMember(ctx, owner); err != nil {
+		if _, _, delErr := w.Client.From("workspaces").Delete("", "").Eq("id", workspace.ID).Execute(); delErr != nil {
+			slog.Error("failed to roll back workspace", "id", workspace.ID, "error", delErr)
+		}
+		return err
+	}
+
+	slog.Info("workspace saved", "id", workspace.ID, "personal", workspace.IsPersonal())
+	return nil
+}
+
+func (w *WorkspaceRepositoryImpl) GetWorkspace(ctx context.Context, workspaceID string) (*model.Workspace, error) {
+	resp, _, err := w.Client.
+		From("workspaces").
+		Select("*", "exact", false).
+		Eq("id", workspaceID).
+		Single().
+		Execute()
+
+	if err != nil {
+		errStr := strings.ToLower(err.Error())
+		if strings.Contains(errStr, "pgrst116") || strings.Contains(errStr, "no rows") || strings.Contains(errStr, "json object requested") {
+			return nil, utils.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch workspace: %w", err)
+	}
+
+	var workspace model.Workspace
+	if err := json.Unmarshal(resp, &workspace); err != nil {
+		return nil, fmt.Errorf("failed to decode workspace: %w", err)
+	}
+
+	return &workspace, nil
+}
+
+func (w *WorkspaceRepositoryImpl) UpdateWorkspace(ctx context.Context, workspaceID string, update model.WorkspaceUpdate) (*model.Workspace, error) {
+	data := map[string]interface{}{}
+	if update.Name != nil {
+		data["name"] = *update.Name
+	}
+	if update.DedupPolicy != nil {
+		data["dedup_policy"] = *update.DedupPolicy
+	}
+
+	resp, _, err := w.Client.
+		From("workspaces").
+		Update(data, "representation", "").
+		Eq("id", workspaceID).
+		Execute()
+
+	if err != nil {
+		slog.Error("workspace update failed", "workspace_id", workspaceID, "error", err)
+		return nil, fmt.Errorf("failed to update workspace: %w", err)
+	}
+
+	var updated []model.Workspace
+	if err := json.Unmarshal(resp, &updated); err != nil {
+		return nil, fmt.Errorf("failed to decode updated workspace: %w", err)
+	}
+
+	if len(updated) == 0 {
+		return nil, utils.ErrNotFound
+	}
+
+	return &updated[0], nil
+}
+
+func (w *WorkspaceRepositoryImpl) GetPersonalWorkspace(ctx context.Context, userID string) (*model.Workspace, error) {
+	resp, _, err := w.Client.
+		From("workspaces").
+		Select("*", "exact", false).
+		Eq("personal_owner", userID).
+		Single().
+		Execute()
+
+	if err != nil {
+		errStr := strings.ToLower(err.Error())
+		if strings.Contains(errStr, "pgrst116") || strings.Contains(errStr, "no rows") || strings.Contains(errStr, "json object requested") {
+			return nil, utils.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch personal workspace: %w", err)
+	}
+
+	var workspace model.Workspace
+	if err := json.Unmarshal(resp, &workspace); err != nil {
+		return nil, fmt.Errorf("failed to decode workspace: %w", err)
+	}
+
+	return &workspace, nil
+}
+
+func (w *WorkspaceRepositoryImpl) ListUserWorkspaces(ctx context.Context, userID string) ([]model.WorkspaceMembership, error) {
+	resp, _, err := w.Client.
+		From("workspace_members").
+		Select("role, workspaces(*)", "exact", false).
+		Eq("user_id", userID).
+		Order("created_at", &postgrest.OrderOpts{Ascending: true}).
+		Execute()
+
+	if err != nil {
+		return []model.WorkspaceMembership{}, fmt.Errorf("failed to fetch workspaces: %w", err)
+	}
+
+	var rows []struct {
+		Role      model.Role      `json:"role"`
+		Workspace model.Workspace `json:"workspaces"`
+	}
+	if err := json.Unmarshal(resp, &rows); err != nil {
+		return []model.WorkspaceMembership{}, fmt.Errorf("failed to decode workspaces: %w", err)
+	}
+
+	memberships := make([]model.WorkspaceMembership, 0, len(rows))
+	for _, row := range rows {
+		memberships = append(memberships, model.WorkspaceMembership{Workspace: row.Workspace, Role: row.Role})
+	}
+
+	return memberships, nil
+}
+
+func (w *WorkspaceRepositoryImpl) GetMember(ctx context.Context, workspaceID, userID string) (*model.WorkspaceMember, error) {
+	resp, _, err := w.Client.
+		From("workspace_members").
+		Select("*", "exact", false).
+		Eq("workspace_id", workspaceID).
+		Eq("user_id", userID).
+		Single().
+		Execute()
+
+	if err != nil {
+		errStr := strings.ToLower(err.Error())
+		if strings.Contains(errStr, "pgrst116") || strings.Contains(errStr, "no rows") || strings.Contains(errStr, "json object requested") {
+			return nil, utils.ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch workspace member: %w", err)
+	}
+
+	var member model.WorkspaceMember
+	if err := json.Unmarshal(resp, &member); err != nil {
+		return nil, fmt.Errorf("failed to decode workspace member: %w", err)
+	}
+
+	return &member, nil
+}
+
+func (w *WorkspaceRepositoryImpl) ListMembers(ctx context.Context, workspaceID string) ([]model.WorkspaceMember, error) {
+	resp, _, err := w.Client.
+		From("workspace_members").
+		Select("*", "exact", false).
+		Eq("workspace_id", workspaceID).
+		Order("created_at", &postgrest.OrderOpts{Ascending: true}).
+		Execute()
+
+	if err != nil {
+		return []model.WorkspaceMember{}, fmt.Errorf("failed to fetch workspace members: %w", err)
+	}
+
+	var members []model.WorkspaceMember
+	if err := json.Unmarshal(resp, &members); err != nil {
+		return []model.WorkspaceMember{}, fmt.Errorf("failed to decode workspace members: %w", err)
+	}
+
+	if members == nil {
+		members = []model.WorkspaceMember{}
+	}
+
+	return members, nil
+}
+
+func (w *WorkspaceRepositoryImpl) AddMember(ctx context.Context, member *model.WorkspaceMember) error {
+	data := map[string]interface{}{
+		"workspace_id": member.WorkspaceID,
+		"user_id":      member.UserID,
+		"role":         member.Role,
+	}
+
+	resp, _, err := w.Client.
+		From("workspace_members").
+		Insert(data, false, "", "", "").
+		Execute()
+
+	if err != nil {
+		msg := strings.ToLower(err.Error())
+		if strings.Contains(msg, "duplicate key") || strings.Contains(msg, "unique constraint") {
+			return ErrUniqueViolation
+		}
+		if strings.Contains(msg, "foreign key") {
+			return utils.ErrNotFound
+		}
+		slog.Error("workspace member insert failed", "workspace_id", member.WorkspaceID, "error", err)
+		return fmt.Errorf("failed to save workspace member: %w", err)
+	}
+
+	var inserted []model.WorkspaceMember
+	if err := json.Unmarshal(resp, &inserted); err != nil {
+		return fmt.Errorf("failed to decode inserted workspace member: %w", err)
+	}
+
+	if len(inserted) > 0 {
+		*member = inserted[0]
+	}
+
+	return nil
+}
+
+func (w *WorkspaceRepositoryImpl) UpdateMemberRole(ctx context.Context, workspaceID, userID string, role model.Role) (*model.WorkspaceMember, error) {
+	resp, _, err := w.Client.
+		From("workspace_members").
+		Update(map[string]interface{}{"role": role}, "representation", "").
+		Eq("workspace_id", workspaceID).
+		Eq("user_id", userID).
+		Execute()
+
+	if err != nil {
+		slog.Error("workspace member update failed", "workspace_id", workspaceID, "error", err)
+		return nil, fmt.Errorf("failed to update workspace member: %w", err)
+	}
+
+	var updated []model.WorkspaceMember
+	if err := json.Unmarshal(resp, &updated); err != nil {
+		return nil, fmt.Errorf("failed to decode updated workspace member: %w", err)
+	}
+
+	if len(updated) == 0 {
+		return nil, utils.ErrNotFound
+	}
+
+	return &updated[0], nil
+}
+
+func (w *WorkspaceRepositoryImpl) RemoveMember(ctx context.Context, workspaceID, userID string) error {
+	_, _, err := w.Client.
+		From("workspace_members").
+		Delete("", "").
+		Eq("workspace_id", workspaceID).
+		Eq("user_id", userID).
+		Execute()
+
+	if err != nil {
+		slog.Error("workspace member delete failed", "workspace_id", workspaceID, "error", err)
+		return fmt.Errorf("failed to remove workspace member: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,198 @@
+package reputation
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"sync"
+)
+
+type listUpdateFile struct {
+	ListUpdateResponses []listUpdateResponse `json:"listUpdateResponses"`
+}
+
+type listUpdateResponse struct {
+	ThreatType     string           `json:"threatType"`
+	ResponseType   string           `json:"responseType"`
+	Additions      []threatEntrySet `json:"additions"`
+	NewClientState string           `json:"newClientState"`
+	Checksum       struct {
+		SHA256 string `json:"sha256"`
+	} `json:"checksum"`
+}
+
+type threatEntrySet struct {
+	CompressionType string `json:"compressionType"`
+	RawHashes       *struct {
+		PrefixSize int    `json:"prefixSize"`
+		RawHashes  string `json:"rawHashes"`
+	} `json:"rawHashes"`
+}
+
+type HashPrefixBlocklist struct {
+	path     string
+	finder   FullHashFinder
+	mu       sync.RWMutex
+	prefixes map[int]map[string]string
+}
+
+func NewHashPrefixBlocklist(path string, finder FullHashFinder) (*HashPrefixBlocklist, error) {
+	b := &HashPrefixBlocklist{path: path, finder: finder}
+	if err := b.Reload(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (b *HashPrefixBlocklist) Reload() error {
+	data, err := os.ReadFile(b.path)
+	if err != nil {
+		return fmt.Errorf("failed to read blocklist file: %w", err)
+	}
+
+	prefixes, total, err := parseListUpdates(data)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	b.prefixes = prefixes
+	b.mu.Unlock()
+
+	slog.Info("reputation blocklist loaded", "path", b.path, "prefixes", total)
+	return nil
+}
+
+func (b *HashPrefixBlocklist) Check(ctx context.Context, rawURL string) (Verdict, error) {
+	expressions, err := lookupExpressions(rawURL)
+	if err != nil {
+		return Verdict{}, err
+	}
+
+	hashes := make([][sha256.Size]byte, 0, len(expressions))
+	var prefixes [][]byte
+	var threatTypes []string
+	suspect := ""
+
+	b.mu.RLock()
+	for _, expr := range expressions {
+		hash := sha256.Sum256([]byte(expr))
+		hashes = append(hashes, hash)
+		for size, set := range b.prefixes {
+			threat, ok := set[string(hash[:size])]
+			if !ok {
+				continue
+			}
+			if size == sha256.Size {
+				b.mu.RUnlock()
+				return Verdict{Safe: false, Threat: threat, Source: "blocklist"}, nil
+			}
+			suspect = threat
+			prefixes = append(prefixes, hash[:size])
+			if !slices.Contains(threatTypes, threat) {
+				threatTypes = append(threatTypes, threat)
+			}
+		}
+	}
+	b.mu.RUnlock()
+
+	if len(prefixes) == 0 {
+		return Verdict{Safe: true, Source: "blocklist"}, nil
+	}
+	if b.finder == nil {
+		return Verdict{Safe: true, Suspect: true, Threat: suspect, Source: "blocklist"}, nil
+	}
+
+	matches, err := b.finder.FindFullHashes(ctx, prefixes, threatTypes)
+	if err != nil {
+		return Verdict{}, fmt.Errorf("failed to confirm blocklist match: %w", err)
+	}
+	for _, hash := range hashes {
+		if threat, ok := matches[string(hash[:])]; ok {
+			return Verdict{Safe: false, Threat: threat, Source: "blocklist"}, nil
+		}
+	}
+
+	return Verdict{Safe: true, Source: "blocklist"}, nil
+}
+
+func parseListUpdates(data []byte) (map[int]map[string]string, int, error) {
+	var file listUpdateFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode blocklist: %w", err)
+	}
+
+	prefixes := make(map[int]map[string]string)
+	total := 0
+
+	for _, list := range file.ListUpdateResponses {
+		if list.ResponseType != "" && list.ResponseType != "FULL_UPDATE" {
+			return nil, 0, fmt.Errorf("unsupported response type %q for %s: only FULL_UPDATE files are supported", list.ResponseType, list.ThreatType)
+		}
+
+		var listHashes [][]byte
+		for _, addition := range list.Additions {
+			if addition.CompressionType != "" && addition.CompressionType != "RAW" {
+				return nil, 0, fmt.Errorf("unsupported compression type %q for %s", addition.CompressionType, list.ThreatType)
+			}
+			if addition.RawHashes == nil {
+				continue
+			}
+
+			size := addition.RawHashes.PrefixSize
+			if size < 4 || size > sha256.Size {
+				return nil, 0, fmt.Errorf("invalid prefix size %d for %s", size, list.ThreatType)
+			}
+
+			raw, err := base64.StdEncoding.DecodeString(addition.RawHashes.RawHashes)
+			if err != nil {
+				return nil, 0, fmt.Errorf("failed to decode raw hashes for %s: %w", list.ThreatType, err)
+			}
+			if len(raw)%size != 0 {
+				return nil, 0, fmt.Errorf("raw hashes for %s are not a multiple of prefix size %d", list.ThreatType, size)
+			}
+
+			if prefixes[size] == nil {
+				prefixes[size] = make(map[string]string)
+			}
+			for i := 0; i < len(raw); i += size {
+				prefix := raw[i : i+size]
+				prefixes[size][string(prefix)] = list.ThreatType
+				listHashes = append(listHashes, prefix)
+				total++
+			}
+		}
+
+		if list.Checksum.SHA256 != "" {
+			if err := verifyChecksum(listHashes, list.Checksum.SHA256); err != nil {
+				return nil, 0, fmt.Errorf("checksum mismatch for %s: %w", list.ThreatType, err)
+			}
+		}
+	}
+
+	return prefixes, total, nil
+}
+
+func verifyChecksum(hashes [][]byte, expected string) error {
+	want, err := base64.StdEncoding.DecodeString(expected)
+	if err != nil {
+		return fmt.Errorf("invalid checksum encoding: %w", err)
+	}
+
+	slices.SortFunc(hashes, bytes.Compare)
+	hasher := sha256.New()
+	for _, h := range hashes {
+		hasher.Write(h)
+	}
+
+	if !bytes.Equal(hasher.Sum(nil), want) {
+		return fmt.Errorf("computed checksum does not match")
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package reputation
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestHashPrefixBlocklistCheck(t *testing.T) {
+	const bad = "http://evil.example/login"
+	const collision = "http://collision.example/"
+
+	badHash := fullHash(t, bad)
+	collisionHash := fullHash(t, collision)
+
+	tests := []struct {
+		name        string
+		size        int
+		confirmed   map[string]string
+		url         string
+		wantSafe    bool
+		wantSuspect bool
+		wantThreat  string
+	}{
+		{name: "full hash match", size: 32, url: bad, wantThreat: "MALWARE"},
+		{name: "no match", size: 4, url: "http://good.example/", wantSafe: true},
+		{name: "prefix only without finder", size: 4, url: bad, wantSafe: true, wantSuspect: true, wantThreat: "MALWARE"},
+		{name: "prefix confirmed by full hash", size: 4, confirmed: map[string]string{string(badHash): "SOCIAL_ENGINEERING"}, url: bad, wantThreat: "SOCIAL_ENGINEERING"},
+		{name: "prefix not confirmed by full hash", size: 4, confirmed: map[string]string{string(collisionHash): "MALWARE"}, url: bad, wantSafe: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var finder FullHashFinder
+			if tt.confirmed != nil {
+				_, srv := newFakeSafeBrowsing(t, tt.confirmed)
+				finder = NewSafeBrowsingFullHashes(srv.URL, "key", time.Second)
+			}
+
+			bl, err := NewHashPrefixBlocklist(writeBlocklist(t, "MALWARE", tt.size, badHash), finder)
+			if err != nil {
+				t.Fatalf("NewHashPrefixBlocklist: %v", err)
+			}
+
+			verdict, err := bl.Check(context.Background(), tt.url)
+			if err != nil {
+				t.Fatalf("Check: %v", err)
+			}
+			if verdict.Safe != tt.wantSafe || verdict.Suspect != tt.wantSuspect || verdict.Threat != tt.wantThreat {
+				t.Fatalf("Check(%q) = %+v, want safe=%v suspect=%v threat=%q", tt.url, verdict, tt.wantSafe, tt.wantSuspect, tt.wantThreat)
+			}
+		})
+	}
+}
+
+func TestHashPrefixBlocklistSkipsLookupWithoutPrefixHit(t *testing.T) {
+	fake, srv := newFakeSafeBrowsing(t, map[string]string{})
+	bl, err := NewHashPrefixBlocklist(writeBlocklist(t, "MALWARE", 4, fullHash(t, "http://evil.example/")), NewSafeBrowsingFullHashes(srv.URL, "key", time.Second))
+	if err != nil {
+		t.Fatalf("NewHashPrefixBlocklist: %v", err)
+	}
+
+	if _, err := bl.Check(context.Background(), "http://good.example/"); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if n := fake.Requests(); n != 0 {
+		t.Fatalf("full hash lookups = %d, want 0", n)
+	}
+}
+
+func TestHashPrefixBlocklistLookupError(t *testing.T) {
+	fake, srv := newFakeSafeBrowsing(t, map[string]string{})
+	fake.status = http.StatusServiceUnavailable
+
+	bl, err := NewHashPrefixBlocklist(writeBlocklist(t, "MALWARE", 4, fullHash(t, "http://evil.example/")), NewSafeBrowsingFullHashes(srv.URL, "key", time.Second))
+	if err != nil {
+		t.Fatalf("NewHashPrefixBlocklist: %v", err)
+	}
+
+	if _, err := bl.Check(context.Background(), "http://evil.example/"); err == nil {
+		t.Fatal("Check succeeded, want error when the full hash lookup fails")
+	}
+}
+
+func TestParseListUpdatesRejectsBadChecksum(t *testing.T) {
+	data := []byte(`{"listUpdateResponses":[{"threatType":"MALWARE","responseType":"FULL_UPDATE","additions":[{"compressionType":"RAW","rawHashes":{"prefixSize":4,"rawHashes":"AAAAAA=="}}],"checksum":{"sha256":"AAAA"}}]}`)
+	if _, _, err := parseListUpdates(data); err == nil {
+		t.Fatal("parseListUpdates succeeded, want checksum error")
+	}
+}
@@ -0,0 +1,45 @@
+package reputation
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"url-shortener-go-backend/internal/cache"
+)
+
+type CachedChecker struct {
+	inner ReputationChecker
+	cache cache.Cache
+	salt  string
+	ttl   time.Duration
+}
+
+func NewCachedChecker(inner ReputationChecker, c cache.Cache, salt string, ttl time.Duration) *CachedChecker {
+	return &CachedChecker{inner: inner, cache: c, salt: salt, ttl: ttl}
+}
+
+func (c *CachedChecker) Check(ctx context.Context, rawURL string) (Verdict, error) {
+	key := cache.KeyReputation(c.salt, rawURL)
+
+	if val, ok, err := c.cache.Get(ctx, key); err == nil && ok {
+		var cached Verdict
+		if err := json.Unmarshal([]byte(val), &cached); err == nil {
+			return cached, nil
+		}
+	}
+
+	verdict, err := c.inner.Check(ctx, rawURL)
+	if err != nil {
+		return Verdict{}, err
+	}
+
+	if jsonVal, err := json.Marshal(verdict); err == nil {
+		if err := c.cache.Set(ctx, key, string(jsonVal), c.ttl); err != nil {
+			slog.Warn("failed to cache reputation verdict", "error", err)
+		}
+	}
+
+	return verdict, nil
+}
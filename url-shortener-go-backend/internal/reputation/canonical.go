@@ -0,0 +1,146 @@
+package reputation
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+func canonicalize(rawURL string) (host, path, query string, err error) {
+	cleaned := strings.NewReplacer("\t", "", "\r", "", "\n", "").Replace(strings.TrimSpace(rawURL))
+	if i := strings.IndexByte(cleaned, '#'); i >= 0 {
+		cleaned = cleaned[:i]
+	}
+	cleaned = unescapeFully(cleaned)
+	if !strings.Contains(cleaned, "://") {
+		cleaned = "http://" + cleaned
+	}
+
+	u, err := url.Parse(cleaned)
+	if err != nil {
+		return "", "", "", fmt.Errorf("invalid URL: %w", err)
+	}
+
+	host = strings.ToLower(u.Hostname())
+	host = strings.Trim(host, ".")
+	for strings.Contains(host, "..") {
+		host = strings.ReplaceAll(host, "..", ".")
+	}
+	if host == "" {
+		return "", "", "", fmt.Errorf("URL has no host")
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		if v4 := ip.To4(); v4 != nil {
+			host = v4.String()
+		}
+	}
+
+	path = resolvePath(u.Path)
+	query = u.RawQuery
+	if u.ForceQuery || query != "" {
+		query = "?" + query
+	}
+
+	return escapeCanonical(host), escapeCanonical(path), escapeCanonical(query), nil
+}
+
+func unescapeFully(s string) string {
+	for i := 0; i < 10; i++ {
+		unescaped, err := url.PathUnescape(s)
+		if err != nil || unescaped == s {
+			return s
+		}
+		s = unescaped
+	}
+	return s
+}
+
+func resolvePath(p string) string {
+	if p == "" {
+		return "/"
+	}
+
+	segments := strings.Split(p, "/")
+	resolved := make([]string, 0, len(segments))
+	for _, seg := range segments {
+		switch seg {
+		case "", ".":
+			continue
+		case "..":
+			if len(resolved) > 0 {
+				resolved = resolved[:len(resolved)-1]
+			}
+		default:
+			resolved = append(resolved, seg)
+		}
+	}
+
+	out := "/" + strings.Join(resolved, "/")
+	if strings.HasSuffix(p, "/") && out != "/" {
+		out += "/"
+	}
+	return out
+}
+
+func escapeCanonical(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c <= 0x20 || c >= 0x7f || c == '#' || c == '%' {
+			fmt.Fprintf(&b, "%%%02X", c)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+func lookupExpressions(rawURL string) ([]string, error) {
+	host, path, query, err := canonicalize(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := []string{host}
+	if net.ParseIP(host) == nil {
+		labels := strings.Split(host, ".")
+		start := len(labels) - 5
+		if start < 1 {
+			start = 1
+		}
+		for i := start; i < len(labels)-1 && len(hosts) < 5; i++ {
+			hosts = append(hosts, strings.Join(labels[i:], "."))
+		}
+	}
+
+	paths := []string{}
+	if query != "" {
+		paths = append(paths, path+query)
+	}
+	paths = append(paths, path)
+	if path != "/" {
+		paths = append(paths, "/")
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	prefix := "/"
+	for i := 0; i < len(segments)-1 && len(paths) < 6; i++ {
+		prefix += segments[i] + "/"
+		if prefix != path {
+			paths = append(paths, prefix)
+		}
+	}
+
+	seen := make(map[string]bool, len(hosts)*len(paths))
+	expressions := make([]string, 0, len(hosts)*len(paths))
+	for _, h := range hosts {
+		for _, p := range paths {
+			expr := h + p
+			if !seen[expr] {
+				seen[expr] = true
+				expressions = append(expressions, expr)
+			}
+		}
+	}
+	return expressions, nil
+}
@@ -0,0 +1,134 @@
+package reputation
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+type fakeSafeBrowsing struct {
+	mu       sync.Mutex
+	full     map[string]string
+	requests int
+	status   int
+}
+
+func newFakeSafeBrowsing(t *testing.T, full map[string]string) (*fakeSafeBrowsing, *httptest.Server) {
+	t.Helper()
+	fake := &fakeSafeBrowsing{full: full, status: http.StatusOK}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+	return fake, srv
+}
+
+func (f *fakeSafeBrowsing) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests++
+
+	if f.status != http.StatusOK {
+		w.WriteHeader(f.status)
+		return
+	}
+
+	var req fullHashRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var resp struct {
+		Matches []map[string]interface{} `json:"matches"`
+	}
+	resp.Matches = []map[string]interface{}{}
+	for _, entry := range req.ThreatInfo.ThreatEntries {
+		prefix, err := base64.StdEncoding.DecodeString(entry.Hash)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		for hash, threat := range f.full {
+			if len(hash) >= len(prefix) && hash[:len(prefix)] == string(prefix) {
+				resp.Matches = append(resp.Matches, map[string]interface{}{
+					"threatType": threat,
+					"threat":     map[string]string{"hash": base64.StdEncoding.EncodeToString([]byte(hash))},
+				})
+			}
+		}
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (f *fakeSafeBrowsing) Requests() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests
+}
+
+func fakeWebhook(t *testing.T, verdicts map[string]webhookResponse) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var req webhookRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		verdict, ok := verdicts[req.URL]
+		if !ok {
+			verdict = webhookResponse{Verdict: "safe"}
+		}
+		_ = json.NewEncoder(w).Encode(verdict)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func fullHash(t *testing.T, rawURL string) []byte {
+	t.Helper()
+	expressions, err := lookupExpressions(rawURL)
+	if err != nil {
+		t.Fatalf("lookupExpressions(%q): %v", rawURL, err)
+	}
+	hash := sha256.Sum256([]byte(expressions[0]))
+	return hash[:]
+}
+
+func writeBlocklist(t *testing.T, threat string, size int, hashes ...[]byte) string {
+	t.Helper()
+	var raw []byte
+	for _, h := range hashes {
+		raw = append(raw, h[:size]...)
+	}
+	file := map[string]interface{}{
+		"listUpdateResponses": []map[string]interface{}{{
+			"threatType":   threat,
+			"responseType": "FULL_UPDATE",
+			"additions": []map[string]interface{}{{
+				"compressionType": "RAW",
+				"rawHashes": map[string]interface{}{
+					"prefixSize": size,
+					"rawHashes":  base64.StdEncoding.EncodeToString(raw),
+				},
+			}},
+		}},
+	}
+	data, err := json.Marshal(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "blocklist.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
@@ -0,0 +1,113 @@
+package reputation
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const DefaultFullHashEndpoint = "https://safebrowsing.googleapis.com/v4/fullHashes:find"
+
+type FullHashFinder interface {
+	FindFullHashes(ctx context.Context, prefixes [][]byte, threatTypes []string) (map[string]string, error)
+}
+
+type SafeBrowsingFullHashes struct {
+	endpoint string
+	apiKey   string
+	client   *http.Client
+}
+
+type fullHashRequest struct {
+	Client struct {
+		ClientID      string `json:"clientId"`
+		ClientVersion string `json:"clientVersion"`
+	} `json:"client"`
+	ThreatInfo struct {
+		ThreatTypes      []string         `json:"threatTypes"`
+		PlatformTypes    []string         `json:"platformTypes"`
+		ThreatEntryTypes []string         `json:"threatEntryTypes"`
+		ThreatEntries    []fullHashEntity `json:"threatEntries"`
+	} `json:"threatInfo"`
+}
+
+type fullHashEntity struct {
+	Hash string `json:"hash"`
+}
+
+type fullHashResponse struct {
+	Matches []struct {
+		ThreatType string         `json:"threatType"`
+		Threat     fullHashEntity `json:"threat"`
+	} `json:"matches"`
+}
+
+func NewSafeBrowsingFullHashes(endpoint, apiKey string, timeout time.Duration) *SafeBrowsingFullHashes {
+	if endpoint == "" {
+		endpoint = DefaultFullHashEndpoint
+	}
+	return &SafeBrowsingFullHashes{
+		endpoint: endpoint,
+		apiKey:   apiKey,
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+func (f *SafeBrowsingFullHashes) FindFullHashes(ctx context.Context, prefixes [][]byte, threatTypes []string) (map[string]string, error) {
+	var body fullHashRequest
+	body.Client.ClientID = "url-shortener-go-backend"
+	body.Client.ClientVersion = "1.0"
+	body.ThreatInfo.ThreatTypes = threatTypes
+	body.ThreatInfo.PlatformTypes = []string{"ANY_PLATFORM"}
+	body.ThreatInfo.ThreatEntryTypes = []string{"URL"}
+	for _, prefix := range prefixes {
+		body.ThreatInfo.ThreatEntries = append(body.ThreatInfo.ThreatEntries, fullHashEntity{Hash: base64.StdEncoding.EncodeToString(prefix)})
+	}
+
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode full hash request: %w", err)
+	}
+
+	endpoint := f.endpoint
+	if f.apiKey != "" {
+		endpoint += "?key=" + url.QueryEscape(f.apiKey)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build full hash request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("full hash request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("full hash lookup returned status %d", resp.StatusCode)
+	}
+
+	var decoded fullHashResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode full hash response: %w", err)
+	}
+
+	matches := make(map[string]string, len(decoded.Matches))
+	for _, m := range decoded.Matches {
+		hash, err := base64.StdEncoding.DecodeString(m.Threat.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hash in full hash response: %w", err)
+		}
+		matches[string(hash)] = m.ThreatType
+	}
+	return matches, nil
+}
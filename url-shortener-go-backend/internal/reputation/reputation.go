@@ -0,0 +1,65 @@
+package reputation
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"url-shortener-go-backend/internal/metrics"
+)
+
+var ErrUnavailable = errors.New("reputation check unavailable")
+
+type Verdict struct {
+	Safe    bool   `json:"safe"`
+	Suspect bool   `json:"suspect,omitempty"`
+	Threat  string `json:"threat,omitempty"`
+	Source  string `json:"source,omitempty"`
+}
+
+type ReputationChecker interface {
+	Check(ctx context.Context, rawURL string) (Verdict, error)
+}
+
+type Chain struct {
+	checkers   []ReputationChecker
+	failClosed bool
+}
+
+func NewChain(failClosed bool, checkers ...ReputationChecker) *Chain {
+	return &Chain{checkers: checkers, failClosed: failClosed}
+}
+
+func (c *Chain) Len() int {
+	return len(c.checkers)
+}
+
+func (c *Chain) Check(ctx context.Context, rawURL string) (Verdict, error) {
+	var suspect *Verdict
+	for _, checker := range c.checkers {
+		verdict, err := checker.Check(ctx, rawURL)
+		if err != nil {
+			metrics.ReputationChecksTotal.WithLabelValues("error").Inc()
+			if c.failClosed {
+				return Verdict{}, errors.Join(ErrUnavailable, err)
+			}
+			slog.Warn("reputation check failed, continuing", "error", err)
+			continue
+		}
+		if !verdict.Safe {
+			metrics.ReputationChecksTotal.WithLabelValues("unsafe").Inc()
+			return verdict, nil
+		}
+		if verdict.Suspect && suspect == nil {
+			suspect = &verdict
+		}
+	}
+
+	if suspect != nil {
+		metrics.ReputationChecksTotal.WithLabelValues("suspect").Inc()
+		return *suspect, nil
+	}
+
+	metrics.ReputationChecksTotal.WithLabelValues("safe").Inc()
+	return Verdict{Safe: true}, nil
+}
@@ -0,0 +1,84 @@
+package reputation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type stubChecker struct {
+	verdict Verdict
+	err     error
+}
+
+func (s stubChecker) Check(ctx context.Context, rawURL string) (Verdict, error) {
+	return s.verdict, s.err
+}
+
+func TestWebhookChecker(t *testing.T) {
+	srv := fakeWebhook(t, map[string]webhookResponse{
+		"https://phish.example/": {Verdict: "unsafe", Threat: "phishing"},
+		"https://odd.example/":   {Verdict: "maybe"},
+	})
+	checker := NewWebhookChecker(srv.URL, "token", time.Second)
+
+	verdict, err := checker.Check(context.Background(), "https://good.example/")
+	if err != nil || !verdict.Safe {
+		t.Fatalf("safe url: verdict=%+v err=%v", verdict, err)
+	}
+
+	verdict, err = checker.Check(context.Background(), "https://phish.example/")
+	if err != nil || verdict.Safe || verdict.Threat != "phishing" {
+		t.Fatalf("unsafe url: verdict=%+v err=%v", verdict, err)
+	}
+
+	if _, err := checker.Check(context.Background(), "https://odd.example/"); err == nil {
+		t.Fatal("unknown verdict: want error")
+	}
+
+	if _, err := NewWebhookChecker(srv.URL, "wrong", time.Second).Check(context.Background(), "https://good.example/"); err == nil {
+		t.Fatal("bad token: want error")
+	}
+}
+
+func TestChain(t *testing.T) {
+	safe := stubChecker{verdict: Verdict{Safe: true}}
+	suspect := stubChecker{verdict: Verdict{Safe: true, Suspect: true, Threat: "MALWARE", Source: "blocklist"}}
+	unsafe := stubChecker{verdict: Verdict{Safe: false, Threat: "phishing", Source: "webhook"}}
+	broken := stubChecker{err: errors.New("boom")}
+
+	tests := []struct {
+		name        string
+		failClosed  bool
+		checkers    []ReputationChecker
+		wantSafe    bool
+		wantSuspect bool
+		wantErr     bool
+	}{
+		{name: "all safe", checkers: []ReputationChecker{safe, safe}, wantSafe: true},
+		{name: "unsafe wins", checkers: []ReputationChecker{safe, unsafe}},
+		{name: "suspect is not unsafe", checkers: []ReputationChecker{suspect, safe}, wantSafe: true, wantSuspect: true},
+		{name: "later unsafe overrides suspect", checkers: []ReputationChecker{suspect, unsafe}},
+		{name: "error skipped when open", checkers: []ReputationChecker{broken, safe}, wantSafe: true},
+		{name: "error fails closed", failClosed: true, checkers: []ReputationChecker{broken, safe}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict, err := NewChain(tt.failClosed, tt.checkers...).Check(context.Background(), "https://example.com/")
+			if tt.wantErr {
+				if !errors.Is(err, ErrUnavailable) {
+					t.Fatalf("err = %v, want ErrUnavailable", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Check: %v", err)
+			}
+			if verdict.Safe != tt.wantSafe || verdict.Suspect != tt.wantSuspect {
+				t.Fatalf("verdict = %+v, want safe=%v suspect=%v", verdict, tt.wantSafe, tt.wantSuspect)
+			}
+		})
+	}
+}
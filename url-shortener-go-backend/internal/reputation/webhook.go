@@ -0,0 +1,74 @@
+package reputation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+type WebhookChecker struct {
+	endpoint string
+	token    string
+	client   *http.Client
+}
+
+type webhookRequest struct {
+	URL string `json:"url"`
+}
+
+type webhookResponse struct {
+	Verdict string `json:"verdict"`
+	Threat  string `json:"threat"`
+}
+
+func NewWebhookChecker(endpoint, token string, timeout time.Duration) *WebhookChecker {
+	return &WebhookChecker{
+		endpoint: endpoint,
+		token:    token,
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+func (c *WebhookChecker) Check(ctx context.Context, rawURL string) (Verdict, error) {
+	body, err := json.Marshal(webhookRequest{URL: rawURL})
+	if err != nil {
+		return Verdict{}, fmt.Errorf("failed to encode webhook request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return Verdict{}, fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return Verdict{}, fmt.Errorf("reputation webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Verdict{}, fmt.Errorf("reputation webhook returned status %d", resp.StatusCode)
+	}
+
+	var decoded webhookResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&decoded); err != nil {
+		return Verdict{}, fmt.Errorf("failed to decode webhook response: %w", err)
+	}
+
+	switch decoded.Verdict {
+	case "safe":
+		return Verdict{Safe: true, Source: "webhook"}, nil
+	case "unsafe":
+		return Verdict{Safe: false, Threat: decoded.Threat, Source: "webhook"}, nil
+	default:
+		return Verdict{}, fmt.Errorf("reputation webhook returned unknown verdict %q", decoded.Verdict)
+	}
+}
@@ -13,15 +13,31 @@ import (
 	"url-shortener-go-backend/internal/metrics"
 	"url-shortener-go-backend/internal/middleware"
 	"url-shortener-go-backend/internal/repository"
+	"url-shortener-go-backend/internal/service"
 	"url-shortener-go-backend/internal/utils"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+const (
+	wellKnownPath     = "/.well-known/"
+	acmeChallengePath = wellKnownPath + "acme-challenge/"
 )
 
 type APIServer struct {
 	address          string
 	router           *http.ServeMux
 	server           *http.Server
+	tlsServer        *http.Server
 	urlHandler       *handler.URLHandler
 	analyticsHandler *handler.AnalyticsHandler
+	reportHandler    *handler.ReportHandler
+	wellKnownHandler *handler.WellKnownHandler
+	domainHandler    *handler.DomainHandler
+	domainService    service.DomainService
+	workspaceHandler *handler.WorkspaceHandler
+	webhookHandler   *handler.WebhookHandler
+	limiter          *middleware.RateLimiter
 	middlewares      []func(http.Handler) http.Handler
 	authMiddleware   func(http.Handler) http.Handler
 	cache            cache.Cache
@@ -34,6 +50,13 @@ func NewAPIServer(
 	cfg *config.Config,
 	urlHandler *handler.URLHandler,
 	analyticsHandler *handler.AnalyticsHandler,
+	reportHandler *handler.ReportHandler,
+	wellKnownHandler *handler.WellKnownHandler,
+	domainHandler *handler.DomainHandler,
+	domainService service.DomainService,
+	workspaceHandler *handler.WorkspaceHandler,
+	webhookHandler *handler.WebhookHandler,
+	limiter *middleware.RateLimiter,
 	c cache.Cache,
 	supabaseRepo *repository.SupabaseRepository,
 	authMw func(http.Handler) http.Handler,
@@ -45,6 +68,13 @@ func NewAPIServer(
 		router:           mux,
 		urlHandler:       urlHandler,
 		analyticsHandler: analyticsHandler,
+		reportHandler:    reportHandler,
+		wellKnownHandler: wellKnownHandler,
+		domainHandler:    domainHandler,
+		domainService:    domainService,
+		workspaceHandler: workspaceHandler,
+		webhookHandler:   webhookHandler,
+		limiter:          limiter,
 		middlewares:      mws,
 		authMiddleware:   authMw,
 		cache:            c,
@@ -65,7 +95,7 @@ func NewAPIServer(
 
 	s.server = &http.Server{
 		Addr:         s.address,
-		Handler:      s.withMiddleware(s.router, allMiddlewares...),
+		Handler:      s.withMiddleware(s.dispatchByHost(s.router), allMiddlewares...),
 		ReadTimeout:  5 * time.Second,
 		WriteTimeout: 10 * time.Second,
 		IdleTimeout:  60 * time.Second,
@@ -75,13 +105,44 @@ func NewAPIServer(
 	return s
 }
 
+func (s *APIServer) EnableAutoTLS(addr string, manager *autocert.Manager) {
+	s.router.Handle(acmeChallengePath, manager.HTTPHandler(nil))
+	s.tlsServer = &http.Server{
+		Addr:         addr,
+		Handler:      s.server.Handler,
+		TLSConfig:    manager.TLSConfig(),
+		ReadTimeout:  s.server.ReadTimeout,
+		WriteTimeout: s.server.WriteTimeout,
+		IdleTimeout:  s.server.IdleTimeout,
+	}
+	slog.Info("acme tls enabled", "address", addr)
+}
+
 func (s *APIServer) Run() error {
-	slog.Info("http server listening", "address", s.address)
-	return s.server.ListenAndServe()
+	if s.tlsServer == nil {
+		slog.Info("http server listening", "address", s.address)
+		return s.server.ListenAndServe()
+	}
+
+	errs := make(chan error, 2)
+	go func() {
+		slog.Info("https server listening", "address", s.tlsServer.Addr)
+		errs <- s.tlsServer.ListenAndServeTLS("", "")
+	}()
+	go func() {
+		slog.Info("http server listening", "address", s.address)
+		errs <- s.server.ListenAndServe()
+	}()
+	return <-errs
 }
 
 func (s *APIServer) Shutdown(ctx context.Context) error {
 	slog.Info("shutting down http server")
+	if s.tlsServer != nil {
+		if err := s.tlsServer.Shutdown(ctx); err != nil {
+			slog.Error("https server shutdown failed", "error", err)
+		}
+	}
 	return s.server.Shutdown(ctx)
 }
 
@@ -129,7 +190,7 @@ func (s *APIServer) routes() {
 	s.router.Handle("/api/urls", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodPost:
-			s.urlHandler.HandleShorten()(w, r)
+			s.authMiddleware(http.HandlerFunc(s.urlHandler.HandleShorten())).ServeHTTP(w, r)
 		case http.MethodGet:
 			protected := s.authMiddleware(http.HandlerFunc(s.urlHandler.HandleGetUserUrls()))
 			protected.ServeHTTP(w, r)
@@ -138,16 +199,62 @@ func (s *APIServer) routes() {
 		}
 	}))
 
+	s.router.Handle("/api/urls/broken", s.authMiddleware(http.HandlerFunc(s.urlHandler.HandleBrokenURLs())))
+
 	s.router.HandleFunc("/api/urls/", func(w http.ResponseWriter, r *http.Request) {
-		slog.Info("get url by shortcode", "method", r.Method, "path", r.URL.Path)
-		s.urlHandler.HandleGetUrlByShortCode()(w, r)
+		slog.Info("url by shortcode", "method", r.Method, "path", r.URL.Path)
+		if strings.HasSuffix(r.URL.Path, "/qr") {
+			s.urlHandler.HandleGetQRCode()(w, r)
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/metadata") {
+			s.authMiddleware(http.HandlerFunc(s.urlHandler.HandleRefreshMetadata())).ServeHTTP(w, r)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			s.urlHandler.HandleGetUrlByShortCode()(w, r)
+		case http.MethodPatch:
+			s.authMiddleware(http.HandlerFunc(s.urlHandler.HandleUpdateURL())).ServeHTTP(w, r)
+		case http.MethodDelete:
+			s.authMiddleware(http.HandlerFunc(s.urlHandler.HandleDeleteURL())).ServeHTTP(w, r)
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
 	})
 
 	s.registerAnalyticsRoutes()
+	s.registerReportRoutes()
+
+	s.router.Handle("/api/domains", s.authMiddleware(s.domainHandler.HandleDomains()))
+	s.router.Handle("/api/domains/", s.authMiddleware(s.domainHandler.HandleDomain()))
+	s.router.Handle("/api/workspaces", s.authMiddleware(s.workspaceHandler.HandleWorkspaces()))
+	s.router.Handle("/api/workspaces/", s.authMiddleware(s.workspaceHandler.HandleWorkspace()))
+	s.router.Handle("/api/webhooks", s.authMiddleware(s.webhookHandler.HandleWebhooks()))
+	s.router.Handle("/api/webhooks/", s.authMiddleware(s.webhookHandler.HandleWebhook()))
+
+	s.router.Handle("/.well-known/apple-app-site-association", s.wellKnownHandler.HandleAppleAppSiteAssociation())
+	s.router.Handle("/.well-known/assetlinks.json", s.wellKnownHandler.HandleAssetLinks())
 
 	s.router.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		slog.Info("shortcode handler", "method", r.Method, "path", r.URL.Path)
-		s.urlHandler.ShortCodeHandler()(w, r)
+		s.urlHandler.ShortCodeHandler("")(w, r)
+	})
+}
+
+func (s *APIServer) dispatchByHost(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, wellKnownPath) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		domain, ok := s.domainService.ResolveHost(r.Context(), r.Host)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		slog.Info("custom domain shortcode handler", "host", domain, "path", r.URL.Path)
+		s.urlHandler.ShortCodeHandler(domain)(w, r)
 	})
 }
 
@@ -166,6 +273,10 @@ func (s *APIServer) registerAnalyticsRoutes() {
 		http.HandlerFunc(s.analyticsHandler.HandleGetTopReferrers()),
 	))
 
+	s.router.Handle("/api/analytics/variants", s.authMiddleware(
+		http.HandlerFunc(s.analyticsHandler.HandleGetVariantReport()),
+	))
+
 	s.router.Handle("/api/analytics/devices", s.authMiddleware(
 		http.HandlerFunc(s.analyticsHandler.HandleGetDeviceBreakdown()),
 	))
@@ -181,6 +292,25 @@ func (s *APIServer) registerAnalyticsRoutes() {
 	slog.Info("analytics routes registered")
 }
 
+func (s *APIServer) registerReportRoutes() {
+	slog.Info("registering report routes")
+
+	reportLimit := s.limiter.BucketMiddleware("reports", s.cfg.ReportRateLimit, time.Hour)
+	s.router.Handle("/api/reports/", reportLimit(s.authMiddleware(
+		http.HandlerFunc(s.reportHandler.HandleSubmitReport()),
+	)))
+
+	adminOnly := func(h http.HandlerFunc) http.Handler {
+		return s.authMiddleware(middleware.RequireAdmin(s.cfg.AdminUserIDs)(h))
+	}
+
+	s.router.Handle("/api/admin/reports", adminOnly(s.reportHandler.HandleListReports()))
+	s.router.Handle("/api/admin/reports/", adminOnly(s.reportHandler.HandleResolveReport()))
+	s.router.Handle("/api/admin/urls/", adminOnly(s.reportHandler.HandleSetURLStatus()))
+
+	slog.Info("report routes registered")
+}
+
 func (s *APIServer) withMiddleware(h http.Handler, mws ...func(http.Handler) http.Handler) http.Handler {
 	for i := len(mws) - 1; i >= 0; i-- {
 		h = mws[i](h)
@@ -203,8 +333,8 @@ func (s *APIServer) cors() func(http.Handler) http.Handler {
 			if _, ok := allowedSet[strings.TrimRight(origin, "/")]; ok {
 				w.Header().Set("Access-Control-Allow-Origin", origin)
 				w.Header().Set("Access-Control-Allow-Credentials", "true")
-				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, "+handler.WorkspaceHeader)
 			}
 
 			if r.Method == http.MethodOptions {
@@ -0,0 +1,622 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"url-shortener-go-backend/internal/config"
+	"url-shortener-go-backend/internal/deeplink"
+	"url-shortener-go-backend/internal/handler"
+	"url-shortener-go-backend/internal/middleware"
+	"url-shortener-go-backend/internal/model"
+	"url-shortener-go-backend/internal/netguard"
+	"url-shortener-go-backend/internal/repository"
+	"url-shortener-go-backend/internal/service"
+	"url-shortener-go-backend/internal/shortcode"
+	"url-shortener-go-backend/internal/targeting"
+	"url-shortener-go-backend/internal/urlnorm"
+	"url-shortener-go-backend/internal/utils"
+	"url-shortener-go-backend/internal/webhook"
+)
+
+type memCache struct {
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func (c *memCache) Get(ctx context.Context, key string) (string, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.data[key]
+	return v, ok, nil
+}
+
+func (c *memCache) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.data[key] = value
+	return nil
+}
+
+func (c *memCache) Incr(ctx context.Context, key string) (int64, error) {
+	return 0, nil
+}
+
+func (c *memCache) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return nil
+}
+
+func (c *memCache) TTL(ctx context.Context, key string) (time.Duration, error) {
+	return 0, nil
+}
+
+func (c *memCache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.data, key)
+	return nil
+}
+
+func (c *memCache) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (c *memCache) Close() error {
+	return nil
+}
+
+func testUUID(n int) string {
+	return fmt.Sprintf("00000000-0000-0000-0000-%012d", n)
+}
+
+type fakeURLRepo struct {
+	repository.URLRepository
+
+	mu     sync.Mutex
+	urls   map[string]*model.URL
+	nextID int
+}
+
+func (r *fakeURLRepo) SaveURL(ctx context.Context, url *model.URL) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.urls[url.Key()]; ok {
+		return repository.ErrUniqueViolation
+	}
+	r.nextID++
+	url.ID = fmt.Sprint(r.nextID)
+	url.PopulateShortURL("https://short.example")
+	stored := *url
+	r.urls[url.Key()] = &stored
+	return nil
+}
+
+func (r *fakeURLRepo) GetURLByShortCode(ctx context.Context, shortcode string) (*model.URL, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	url, ok := r.urls[shortcode]
+	if !ok {
+		return nil, utils.ErrNotFound
+	}
+	copied := *url
+	return &copied, nil
+}
+
+func (r *fakeURLRepo) FindURLByDestination(ctx context.Context, workspaceID, domain, destinationHash string, isPublic bool) (*model.URL, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, url := range r.urls {
+		owner := ""
+		if url.WorkspaceID != nil {
+			owner = *url.WorkspaceID
+		}
+		if owner == workspaceID && url.Domain == domain && url.DestinationHash == destinationHash && url.IsPublic == isPublic {
+			copied := *url
+			return &copied, nil
+		}
+	}
+	return nil, utils.ErrNotFound
+}
+
+func (r *fakeURLRepo) UpdateURL(ctx context.Context, shortcode string, update model.URLUpdate) (*model.URL, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	url, ok := r.urls[shortcode]
+	if !ok {
+		return nil, utils.ErrNotFound
+	}
+	if update.RequirePreview != nil {
+		url.RequirePreview = *update.RequirePreview
+	}
+	copied := *url
+	return &copied, nil
+}
+
+type fakeWorkspaceRepo struct {
+	repository.WorkspaceRepository
+
+	mu         sync.Mutex
+	workspaces map[string]*model.Workspace
+	members    map[string]model.Role
+	nextID     int
+}
+
+func (r *fakeWorkspaceRepo) CreateWorkspace(ctx context.Context, workspace *model.Workspace) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	workspace.ID = testUUID(r.nextID)
+	stored := *workspace
+	r.workspaces[workspace.ID] = &stored
+	r.members[workspace.ID+":"+workspace.CreatedBy] = model.RoleOwner
+	return nil
+}
+
+func (r *fakeWorkspaceRepo) GetWorkspace(ctx context.Context, workspaceID string) (*model.Workspace, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	workspace, ok := r.workspaces[workspaceID]
+	if !ok {
+		return nil, utils.ErrNotFound
+	}
+	copied := *workspace
+	return &copied, nil
+}
+
+func (r *fakeWorkspaceRepo) GetPersonalWorkspace(ctx context.Context, userID string) (*model.Workspace, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, workspace := range r.workspaces {
+		if workspace.PersonalOwner != nil && *workspace.PersonalOwner == userID {
+			copied := *workspace
+			return &copied, nil
+		}
+	}
+	return nil, utils.ErrNotFound
+}
+
+func (r *fakeWorkspaceRepo) GetMember(ctx context.Context, workspaceID, userID string) (*model.WorkspaceMember, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	role, ok := r.members[workspaceID+":"+userID]
+	if !ok {
+		return nil, utils.ErrNotFound
+	}
+	return &model.WorkspaceMember{WorkspaceID: workspaceID, UserID: userID, Role: role}, nil
+}
+
+func (r *fakeWorkspaceRepo) add(workspace model.Workspace, members map[string]model.Role) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.workspaces[workspace.ID] = &workspace
+	for userID, role := range members {
+		r.members[workspace.ID+":"+userID] = role
+	}
+}
+
+type fakeDomainRepo struct {
+	repository.DomainRepository
+
+	domains map[string]*model.Domain
+}
+
+func (r *fakeDomainRepo) GetDomainByHostname(ctx context.Context, hostname string) (*model.Domain, error) {
+	domain, ok := r.domains[hostname]
+	if !ok {
+		return nil, utils.ErrNotFound
+	}
+	copied := *domain
+	return &copied, nil
+}
+
+type fakeWebhookRepo struct {
+	repository.WebhookRepository
+
+	mu         sync.Mutex
+	hooks      map[string]*model.Webhook
+	deliveries map[string]model.WebhookDelivery
+	nextID     int
+}
+
+func (r *fakeWebhookRepo) CreateWebhook(ctx context.Context, hook *model.Webhook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	hook.ID = testUUID(r.nextID)
+	stored := *hook
+	r.hooks[hook.ID] = &stored
+	return nil
+}
+
+func (r *fakeWebhookRepo) GetWebhook(ctx context.Context, id string) (*model.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	hook, ok := r.hooks[id]
+	if !ok {
+		return nil, utils.ErrNotFound
+	}
+	copied := *hook
+	return &copied, nil
+}
+
+func (r *fakeWebhookRepo) ListWorkspaceWebhooks(ctx context.Context, workspaceID string) ([]model.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	hooks := []model.Webhook{}
+	for _, hook := range r.hooks {
+		if hook.WorkspaceID == workspaceID {
+			hooks = append(hooks, *hook)
+		}
+	}
+	return hooks, nil
+}
+
+func (r *fakeWebhookRepo) CreateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	delivery.ID = testUUID(r.nextID)
+	r.deliveries[delivery.ID] = *delivery
+	return nil
+}
+
+func (r *fakeWebhookRepo) UpdateDelivery(ctx context.Context, delivery model.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliveries[delivery.ID] = delivery
+	return nil
+}
+
+func (r *fakeWebhookRepo) succeeded(eventType string) []model.WebhookDelivery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []model.WebhookDelivery
+	for _, d := range r.deliveries {
+		if d.EventType == eventType && d.Status == model.DeliverySucceeded {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+type publicResolver struct{}
+
+func (publicResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	return []net.IPAddr{{IP: net.ParseIP("93.184.216.34")}}, nil
+}
+
+func fakeAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), middleware.UserIDKey, token)))
+	})
+}
+
+type testEnv struct {
+	handler    http.Handler
+	urls       *fakeURLRepo
+	workspaces *fakeWorkspaceRepo
+	domains    *fakeDomainRepo
+	webhooks   *fakeWebhookRepo
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	c := &memCache{data: map[string]string{}}
+	env := &testEnv{
+		urls:       &fakeURLRepo{urls: map[string]*model.URL{}},
+		workspaces: &fakeWorkspaceRepo{workspaces: map[string]*model.Workspace{}, members: map[string]model.Role{}},
+		domains:    &fakeDomainRepo{domains: map[string]*model.Domain{}},
+		webhooks:   &fakeWebhookRepo{hooks: map[string]*model.Webhook{}, deliveries: map[string]model.WebhookDelivery{}},
+	}
+
+	validatorConfig := middleware.DefaultConfig()
+	validatorConfig.Resolver = publicResolver{}
+
+	webhookService := service.NewWebhookService(env.webhooks, c,
+		webhook.NewSender(time.Second, netguard.Policy{AllowLoopback: true}),
+		model.DeliveryPolicy{MaxAttempts: 3, BaseBackoff: time.Minute, MaxBackoff: time.Hour}, 1, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go webhookService.RunWorkers(ctx)
+
+	codes := shortcode.NewMux(shortcode.NewRandomGenerator(strings.Repeat("s", 32)), shortcode.NewFilter(nil))
+	urlService := service.NewURLService(env.urls, c, codes, shortcode.CollisionPolicy{MaxAttempts: 3}, nil, nil, urlnorm.New(true), webhookService)
+	analyticsService := service.NewAnalyticsService(nil, c, "test")
+	domainService := service.NewDomainService(env.domains, c, nil, "short.example")
+	workspaceService := service.NewWorkspaceService(env.workspaces, c)
+
+	wellKnown, err := handler.NewWellKnownHandler(deeplink.Config{
+		IOSAppIDs:           []string{"ABCDE12345.com.example.app"},
+		AndroidPackage:      "com.example.app",
+		AndroidFingerprints: []string{strings.TrimSuffix(strings.Repeat("AB:", 32), ":")},
+	})
+	if err != nil {
+		t.Fatalf("NewWellKnownHandler: %v", err)
+	}
+
+	server := NewAPIServer(":0", &config.Config{Environment: "test"},
+		handler.NewURLHandler(urlService, analyticsService, domainService, workspaceService, middleware.NewURLValidator(validatorConfig), targeting.NewDetector(""), webhookService),
+		handler.NewAnalyticsHandler(analyticsService, workspaceService),
+		handler.NewReportHandler(nil, urlService),
+		wellKnown,
+		handler.NewDomainHandler(domainService),
+		domainService,
+		handler.NewWorkspaceHandler(workspaceService),
+		handler.NewWebhookHandler(webhookService, workspaceService),
+		middleware.NewRateLimiter(c, nil),
+		c,
+		nil,
+		fakeAuth,
+	)
+	env.handler = server.server.Handler
+	return env
+}
+
+func (e *testEnv) do(t *testing.T, method, path, token string, body any) (*httptest.ResponseRecorder, map[string]any) {
+	t.Helper()
+	return e.doIn(t, "", method, path, token, body)
+}
+
+func (e *testEnv) doIn(t *testing.T, workspaceID, method, path, token string, body any) (*httptest.ResponseRecorder, map[string]any) {
+	t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatalf("encode body: %v", err)
+		}
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	req.Host = "short.example"
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if workspaceID != "" {
+		req.Header.Set(handler.WorkspaceHeader, workspaceID)
+	}
+	rec := httptest.NewRecorder()
+	e.handler.ServeHTTP(rec, req)
+
+	var out map[string]any
+	_ = json.Unmarshal(rec.Body.Bytes(), &out)
+	return rec, out
+}
+
+func TestShortenWithTokenOwnsLink(t *testing.T) {
+	env := newTestEnv(t)
+
+	rec, created := env.do(t, http.MethodPost, "/api/urls", "alice", map[string]any{"url": "https://example.com/launch"})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("POST /api/urls = %d %s", rec.Code, rec.Body)
+	}
+	code, _ := created["short_code"].(string)
+	workspaceID, _ := created["workspace_id"].(string)
+	if code == "" || workspaceID == "" {
+		t.Fatalf("created link = %v, want a short code in the caller's workspace", created)
+	}
+
+	rec, updated := env.do(t, http.MethodPatch, "/api/urls/"+code, "alice", map[string]any{"require_preview": true})
+	if rec.Code != http.StatusOK || updated["require_preview"] != true {
+		t.Fatalf("PATCH by owner = %d %s", rec.Code, rec.Body)
+	}
+
+	if rec, _ := env.do(t, http.MethodPatch, "/api/urls/"+code, "mallory", map[string]any{"require_preview": false}); rec.Code != http.StatusNotFound {
+		t.Fatalf("PATCH by stranger = %d, want 404", rec.Code)
+	}
+}
+
+func TestShortenWithoutTokenStaysAnonymous(t *testing.T) {
+	env := newTestEnv(t)
+
+	rec, created := env.do(t, http.MethodPost, "/api/urls", "", map[string]any{"url": "https://example.com/anon"})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("anonymous POST /api/urls = %d %s", rec.Code, rec.Body)
+	}
+	if _, ok := created["workspace_id"]; ok {
+		t.Fatalf("anonymous link = %v, want no workspace", created)
+	}
+
+	code, _ := created["short_code"].(string)
+	if rec, _ := env.do(t, http.MethodPatch, "/api/urls/"+code, "", map[string]any{"require_preview": true}); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("anonymous PATCH = %d, want 401", rec.Code)
+	}
+}
+
+func TestShortenOnCustomDomain(t *testing.T) {
+	env := newTestEnv(t)
+	verifiedAt := time.Now()
+	env.domains.domains["links.customer.com"] = &model.Domain{ID: "d1", UserID: "alice", Hostname: "links.customer.com", VerifiedAt: &verifiedAt}
+	env.domains.domains["pending.customer.com"] = &model.Domain{ID: "d2", UserID: "alice", Hostname: "pending.customer.com"}
+
+	rec, created := env.do(t, http.MethodPost, "/api/urls", "alice", map[string]any{"url": "https://example.com/promo", "domain": "Links.Customer.com"})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("POST on verified domain = %d %s", rec.Code, rec.Body)
+	}
+	code, _ := created["short_code"].(string)
+	if created["domain"] != "links.customer.com" || created["short_url"] != "https://links.customer.com/"+code {
+		t.Fatalf("created link = %v, want it served from links.customer.com", created)
+	}
+	if _, err := env.urls.GetURLByShortCode(context.Background(), "links.customer.com/"+code); err != nil {
+		t.Fatalf("link not stored under the custom domain: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		token  string
+		domain string
+		want   int
+	}{
+		{name: "anonymous", domain: "links.customer.com", want: http.StatusUnauthorized},
+		{name: "unverified", token: "alice", domain: "pending.customer.com", want: http.StatusBadRequest},
+		{name: "not owner", token: "bob", domain: "links.customer.com", want: http.StatusBadRequest},
+		{name: "unknown", token: "alice", domain: "other.customer.com", want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, _ := env.do(t, http.MethodPost, "/api/urls", tt.token, map[string]any{"url": "https://example.com/promo", "domain": tt.domain})
+			if rec.Code != tt.want {
+				t.Fatalf("POST on %s = %d %s, want %d", tt.domain, rec.Code, rec.Body, tt.want)
+			}
+		})
+	}
+}
+
+func TestShortenDeliversLinkCreatedWebhook(t *testing.T) {
+	env := newTestEnv(t)
+
+	received := make(chan model.WebhookEvent, 4)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var event model.WebhookEvent
+		if err := json.Unmarshal(body, &event); err == nil {
+			received <- event
+		}
+	}))
+	t.Cleanup(receiver.Close)
+
+	rec, _ := env.do(t, http.MethodPost, "/api/webhooks", "alice", map[string]any{"url": receiver.URL, "events": []string{model.EventLinkCreated}})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("POST /api/webhooks = %d %s", rec.Code, rec.Body)
+	}
+
+	rec, created := env.do(t, http.MethodPost, "/api/urls", "alice", map[string]any{"url": "https://example.com/hooked"})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("POST /api/urls = %d %s", rec.Code, rec.Body)
+	}
+
+	select {
+	case event := <-received:
+		if event.Type != model.EventLinkCreated || event.WorkspaceID != created["workspace_id"] || event.Data.Link.ShortCode != created["short_code"] {
+			t.Fatalf("delivered event = %+v, want link.created for %v", event, created)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no webhook delivered after shortening")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(env.webhooks.succeeded(model.EventLinkCreated)) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("no succeeded link.created delivery recorded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestShortenDedupIsScopedToWorkspace(t *testing.T) {
+	env := newTestEnv(t)
+	destination := map[string]any{"url": "https://example.com/shared?utm_source=mail"}
+
+	shorten := func(workspaceID, token string) (int, map[string]any) {
+		t.Helper()
+		rec, body := env.doIn(t, workspaceID, http.MethodPost, "/api/urls", token, destination)
+		if rec.Code != http.StatusCreated && rec.Code != http.StatusOK {
+			t.Fatalf("POST /api/urls as %q = %d %s", token, rec.Code, rec.Body)
+		}
+		return rec.Code, body
+	}
+
+	_, alice := shorten("", "alice")
+	_, bob := shorten("", "bob")
+	if alice["short_code"] == bob["short_code"] || alice["workspace_id"] == bob["workspace_id"] {
+		t.Fatalf("alice = %v, bob = %v; want separate links in separate workspaces", alice, bob)
+	}
+
+	status, again := shorten("", "alice")
+	if status != http.StatusOK || again["reused"] != true || again["short_code"] != alice["short_code"] {
+		t.Fatalf("second shorten by alice = %d %v, want her existing link reused", status, again)
+	}
+
+	_, anonymous := shorten("", "")
+	if anonymous["short_code"] == alice["short_code"] || anonymous["short_code"] == bob["short_code"] {
+		t.Fatalf("anonymous link %v reused a workspace link", anonymous)
+	}
+
+	team := testUUID(100)
+	env.workspaces.add(model.Workspace{ID: team, Name: "Team", DedupPolicy: model.DedupAlwaysNew}, map[string]model.Role{"alice": model.RoleEditor})
+	_, first := shorten(team, "alice")
+	status, second := shorten(team, "alice")
+	if status != http.StatusCreated || first["short_code"] == second["short_code"] || first["workspace_id"] != team {
+		t.Fatalf("always_new workspace links = %v, %v; want two fresh codes in %s", first, second, team)
+	}
+}
+
+func TestPreviewShowsTargetedDestination(t *testing.T) {
+	env := newTestEnv(t)
+	link := &model.URL{
+		ShortCode:   "spring-promo",
+		OriginalURL: "https://example.com/default",
+		Rules:       []model.RedirectRule{{OS: []string{model.OSiOS}, Destination: "https://example.com/ios"}},
+		Variants: []model.Variant{
+			{Name: "a", Destination: "https://example.com/variant-a", Weight: 0},
+			{Name: "b", Destination: "https://example.com/variant-b", Weight: 100},
+		},
+	}
+	if err := env.urls.SaveURL(context.Background(), link); err != nil {
+		t.Fatalf("SaveURL: %v", err)
+	}
+
+	preview := func(userAgent string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/spring-promo+", nil)
+		req.Host = "short.example"
+		req.Header.Set("User-Agent", userAgent)
+		rec := httptest.NewRecorder()
+		env.handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET /spring-promo+ = %d %s", rec.Code, rec.Body)
+		}
+		return rec
+	}
+
+	rec := preview("Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 Mobile/15E148")
+	if body := rec.Body.String(); !strings.Contains(body, "https://example.com/ios") || strings.Contains(body, "https://example.com/default") {
+		t.Fatalf("iOS preview does not show the rule destination:\n%s", body)
+	}
+
+	rec = preview("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 Chrome/120.0 Safari/537.36")
+	if body := rec.Body.String(); !strings.Contains(body, "https://example.com/variant-b") || strings.Contains(body, "https://example.com/default") {
+		t.Fatalf("desktop preview does not show the assigned variant:\n%s", body)
+	}
+	if cookie := rec.Header().Get("Set-Cookie"); !strings.Contains(cookie, handler.VariantCookiePrefix+"spring-promo=b") {
+		t.Fatalf("preview Set-Cookie = %q, want the variant pinned for Continue", cookie)
+	}
+}
+
+func TestWellKnownOnCustomDomain(t *testing.T) {
+	env := newTestEnv(t)
+	verifiedAt := time.Now()
+	env.domains.domains["links.customer.com"] = &model.Domain{ID: "d1", UserID: "alice", Hostname: "links.customer.com", VerifiedAt: &verifiedAt}
+
+	for _, path := range []string{"/.well-known/apple-app-site-association", "/.well-known/assetlinks.json"} {
+		for _, host := range []string{"short.example", "links.customer.com"} {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			req.Host = host
+			rec := httptest.NewRecorder()
+			env.handler.ServeHTTP(rec, req)
+			if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "com.example.app") {
+				t.Fatalf("GET %s on %s = %d %s", path, host, rec.Code, rec.Body)
+			}
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/security.txt", nil)
+	req.Host = "links.customer.com"
+	rec := httptest.NewRecorder()
+	env.handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("GET unknown well-known file on custom domain = %d, want 404", rec.Code)
+	}
+}
@@ -6,14 +6,16 @@ import (
 )
 
 type AnalyticsService interface {
-	GetUserDashboard(ctx context.Context, userID string) (*model.UserAnalyticsSummary, error)
+	GetWorkspaceDashboard(ctx context.Context, workspaceID string) (*model.UserAnalyticsSummary, error)
 
-	GetUserTopURLs(ctx context.Context, userID string, limit int) ([]model.URLClickStats, error)
-	GetUserDailyTrend(ctx context.Context, userID string, days int) ([]model.DailyClickStats, error)
-	GetUserTopReferrers(ctx context.Context, userID string, limit int) ([]model.ReferrerStats, error)
-	GetUserDeviceBreakdown(ctx context.Context, userID string) ([]model.DeviceStats, error)
+	GetWorkspaceTopURLs(ctx context.Context, workspaceID string, limit int, cursor *model.PageCursor) (model.Page[model.URLClickStats], error)
+	GetWorkspaceDailyTrend(ctx context.Context, workspaceID string, days int) ([]model.DailyClickStats, error)
+	GetWorkspaceTopReferrers(ctx context.Context, workspaceID string, limit int, cursor *model.PageCursor) (model.Page[model.ReferrerStats], error)
+	GetWorkspaceDeviceBreakdown(ctx context.Context, workspaceID string) ([]model.DeviceStats, error)
+	GetURLVariantStats(ctx context.Context, workspaceID, urlID string) ([]model.VariantStats, error)
 
 	RecordAnalytics(ctx context.Context, userID, urlID, referrer, deviceType string) error
+	RecordClick(ctx context.Context, event model.ClickEvent) error
 
 	ProcessDailyAnalytics(ctx context.Context) error
 }
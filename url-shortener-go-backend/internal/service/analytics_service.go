@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"url-shortener-go-backend/internal/metrics"
 	"url-shortener-go-backend/internal/model"
 	"url-shortener-go-backend/internal/repository"
+	"url-shortener-go-backend/internal/utils"
 )
 
 type AnalyticsServiceImpl struct {
@@ -42,62 +44,63 @@ func NormalizeSummary(summary *model.UserAnalyticsSummary) {
 	}
 }
 
-func (s *AnalyticsServiceImpl) GetUserDashboard(ctx context.Context, userID string) (*model.UserAnalyticsSummary, error) {
-	cacheKey := cache.KeyUserAnalytics(s.salt, userID, time.Now().AddDate(0, 0, -7), time.Now())
+func (s *AnalyticsServiceImpl) GetWorkspaceDashboard(ctx context.Context, workspaceID string) (*model.UserAnalyticsSummary, error) {
+	cacheKey := cache.KeyWorkspaceAnalytics(s.salt, workspaceID, time.Now().AddDate(0, 0, -7), time.Now())
 
 	if val, ok, err := s.cache.Get(ctx, cacheKey); err == nil && ok {
 		var cached model.UserAnalyticsSummary
 		if err := json.Unmarshal([]byte(val), &cached); err == nil {
-			slog.Info("dashboard cache hit", "user_id", userID)
+			slog.Info("dashboard cache hit", "workspace_id", workspaceID)
 			NormalizeSummary(&cached)
 			return &cached, nil
 		}
 	}
 
-	slog.Info("dashboard cache miss", "user_id", userID)
-	summary, err := s.analyticsRepo.GetUserAnalyticsSummary(ctx, userID)
+	slog.Info("dashboard cache miss", "workspace_id", workspaceID)
+	summary, err := s.analyticsRepo.GetWorkspaceAnalyticsSummary(ctx, workspaceID)
 	if summary != nil {
 		NormalizeSummary(summary)
 	}
 
 	if err != nil {
-		slog.Error("failed to get analytics summary", "user_id", userID, "error", err)
+		slog.Error("failed to get analytics summary", "workspace_id", workspaceID, "error", err)
 		return nil, fmt.Errorf("failed to get user dashboard: %w", err)
 	}
 
 	if jsonVal, err := json.Marshal(summary); err == nil {
 		_ = s.cache.Set(ctx, cacheKey, string(jsonVal), time.Hour)
-		slog.Info("dashboard cached", "user_id", userID)
+		slog.Info("dashboard cached", "workspace_id", workspaceID)
 	}
 
 	return summary, nil
 }
 
-func (s *AnalyticsServiceImpl) GetUserTopURLs(ctx context.Context, userID string, limit int) ([]model.URLClickStats, error) {
-	cacheKey := fmt.Sprintf("user_top_urls:%s:%d", userID, limit)
+func (s *AnalyticsServiceImpl) GetWorkspaceTopURLs(ctx context.Context, workspaceID string, limit int, cursor *model.PageCursor) (model.Page[model.URLClickStats], error) {
+	version := cache.Version(ctx, s.cache, "workspace_top_version:"+workspaceID)
+	cacheKey := fmt.Sprintf("workspace_top_urls:%s:%d:%d:%s", workspaceID, version, limit, cursor)
 
 	if val, ok, err := s.cache.Get(ctx, cacheKey); err == nil && ok {
-		var urls []model.URLClickStats
-		if err := json.Unmarshal([]byte(val), &urls); err == nil {
-			return urls, nil
+		var page model.Page[model.URLClickStats]
+		if err := json.Unmarshal([]byte(val), &page); err == nil {
+			return page, nil
 		}
 	}
 
-	urls, err := s.analyticsRepo.GetUserTopURLs(ctx, userID, limit)
+	page, err := s.analyticsRepo.GetWorkspaceTopURLs(ctx, workspaceID, limit, cursor)
 	if err != nil {
-		slog.Error("failed to get top urls", "user_id", userID, "error", err)
-		return nil, fmt.Errorf("failed to get top URLs: %w", err)
+		slog.Error("failed to get top urls", "workspace_id", workspaceID, "error", err)
+		return model.Page[model.URLClickStats]{}, fmt.Errorf("failed to get top URLs: %w", err)
 	}
 
-	if jsonVal, err := json.Marshal(urls); err == nil {
+	if jsonVal, err := json.Marshal(page); err == nil {
 		_ = s.cache.Set(ctx, cacheKey, string(jsonVal), 30*time.Minute)
 	}
 
-	return urls, nil
+	return page, nil
 }
 
-func (s *AnalyticsServiceImpl) GetUserDailyTrend(ctx context.Context, userID string, days int) ([]model.DailyClickStats, error) {
-	cacheKey := fmt.Sprintf("user_daily_trend:%s:%d", userID, days)
+func (s *AnalyticsServiceImpl) GetWorkspaceDailyTrend(ctx context.Context, workspaceID string, days int) ([]model.DailyClickStats, error) {
+	cacheKey := fmt.Sprintf("workspace_daily_trend:%s:%d", workspaceID, days)
 
 	if val, ok, err := s.cache.Get(ctx, cacheKey); err == nil && ok {
 		var trend []model.DailyClickStats
@@ -106,9 +109,9 @@ func (s *AnalyticsServiceImpl) GetUserDailyTrend(ctx context.Context, userID str
 		}
 	}
 
-	trend, err := s.analyticsRepo.GetUserDailyClicks(ctx, userID, days)
+	trend, err := s.analyticsRepo.GetWorkspaceDailyClicks(ctx, workspaceID, days)
 	if err != nil {
-		slog.Error("failed to get daily trend", "user_id", userID, "error", err)
+		slog.Error("failed to get daily trend", "workspace_id", workspaceID, "error", err)
 		return []model.DailyClickStats{}, nil
 	}
 
@@ -123,31 +126,32 @@ func (s *AnalyticsServiceImpl) GetUserDailyTrend(ctx context.Context, userID str
 	return trend, nil
 }
 
-func (s *AnalyticsServiceImpl) GetUserTopReferrers(ctx context.Context, userID string, limit int) ([]model.ReferrerStats, error) {
-	cacheKey := fmt.Sprintf("user_top_referrers:%s:%d", userID, limit)
+func (s *AnalyticsServiceImpl) GetWorkspaceTopReferrers(ctx context.Context, workspaceID string, limit int, cursor *model.PageCursor) (model.Page[model.ReferrerStats], error) {
+	version := cache.Version(ctx, s.cache, "workspace_top_version:"+workspaceID)
+	cacheKey := fmt.Sprintf("workspace_top_referrers:%s:%d:%d:%s", workspaceID, version, limit, cursor)
 
 	if val, ok, err := s.cache.Get(ctx, cacheKey); err == nil && ok {
-		var referrers []model.ReferrerStats
-		if err := json.Unmarshal([]byte(val), &referrers); err == nil {
-			return referrers, nil
+		var page model.Page[model.ReferrerStats]
+		if err := json.Unmarshal([]byte(val), &page); err == nil {
+			return page, nil
 		}
 	}
 
-	referrers, err := s.analyticsRepo.GetUserTopReferrers(ctx, userID, limit)
+	page, err := s.analyticsRepo.GetWorkspaceTopReferrers(ctx, workspaceID, limit, cursor)
 	if err != nil {
-		slog.Error("failed to get top referrers", "user_id", userID, "error", err)
-		return nil, fmt.Errorf("failed to get top referrers: %w", err)
+		slog.Error("failed to get top referrers", "workspace_id", workspaceID, "error", err)
+		return model.Page[model.ReferrerStats]{}, fmt.Errorf("failed to get top referrers: %w", err)
 	}
 
-	if jsonVal, err := json.Marshal(referrers); err == nil {
+	if jsonVal, err := json.Marshal(page); err == nil {
 		_ = s.cache.Set(ctx, cacheKey, string(jsonVal), 45*time.Minute)
 	}
 
-	return referrers, nil
+	return page, nil
 }
 
-func (s *AnalyticsServiceImpl) GetUserDeviceBreakdown(ctx context.Context, userID string) ([]model.DeviceStats, error) {
-	cacheKey := fmt.Sprintf("user_device_breakdown:%s", userID)
+func (s *AnalyticsServiceImpl) GetWorkspaceDeviceBreakdown(ctx context.Context, workspaceID string) ([]model.DeviceStats, error) {
+	cacheKey := fmt.Sprintf("workspace_device_breakdown:%s", workspaceID)
 
 	if val, ok, err := s.cache.Get(ctx, cacheKey); err == nil && ok {
 		var devices []model.DeviceStats
@@ -156,9 +160,9 @@ func (s *AnalyticsServiceImpl) GetUserDeviceBreakdown(ctx context.Context, userI
 		}
 	}
 
-	devices, err := s.analyticsRepo.GetUserDeviceBreakdown(ctx, userID)
+	devices, err := s.analyticsRepo.GetWorkspaceDeviceBreakdown(ctx, workspaceID)
 	if err != nil {
-		slog.Error("failed to get device breakdown", "user_id", userID, "error", err)
+		slog.Error("failed to get device breakdown", "workspace_id", workspaceID, "error", err)
 		return nil, fmt.Errorf("failed to get device breakdown: %w", err)
 	}
 
@@ -169,14 +173,73 @@ func (s *AnalyticsServiceImpl) GetUserDeviceBreakdown(ctx context.Context, userI
 	return devices, nil
 }
 
+func (s *AnalyticsServiceImpl) GetURLVariantStats(ctx context.Context, workspaceID, urlID string) ([]model.VariantStats, error) {
+	cacheKey := fmt.Sprintf("url_variant_stats:%s:%s", workspaceID, urlID)
+
+	if val, ok, err := s.cache.Get(ctx, cacheKey); err == nil && ok {
+		var stats []model.VariantStats
+		if err := json.Unmarshal([]byte(val), &stats); err == nil {
+			return stats, nil
+		}
+	}
+
+	stats, err := s.analyticsRepo.GetURLVariantStats(ctx, workspaceID, urlID)
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			return nil, err
+		}
+		slog.Error("failed to get variant stats", "workspace_id", workspaceID, "url_id", urlID, "error", err)
+		return nil, fmt.Errorf("failed to get variant stats: %w", err)
+	}
+
+	if jsonVal, err := json.Marshal(stats); err == nil {
+		_ = s.cache.Set(ctx, cacheKey, string(jsonVal), 5*time.Minute)
+	}
+
+	return stats, nil
+}
+
 func (s *AnalyticsServiceImpl) RecordAnalytics(ctx context.Context, userID, urlID, referrer, deviceType string) error {
 	go func() {
 		bgCtx := context.Background()
-		if err := s.analyticsRepo.SaveAnalytics(bgCtx, userID, urlID, referrer, deviceType); err != nil {
+		workspaceID, err := s.analyticsRepo.GetURLWorkspaceID(bgCtx, urlID)
+		if err != nil && !errors.Is(err, utils.ErrNotFound) {
+			slog.Warn("failed to resolve url workspace", "url_id", urlID, "error", err)
+		}
+		event := model.ClickEvent{
+			UserID:      userID,
+			WorkspaceID: workspaceID,
+			URLID:       urlID,
+			Referrer:    referrer,
+			DeviceType:  deviceType,
+		}
+		if err := s.analyticsRepo.SaveClickEvent(bgCtx, event); err != nil {
 			slog.Error("failed to save analytics", "user_id", userID, "url_id", urlID, "error", err)
 		} else {
 			metrics.AnalyticsRecordsTotal.Inc()
-			s.invalidateUserCaches(bgCtx, userID)
+			if workspaceID != "" {
+				s.invalidateWorkspaceCaches(bgCtx, workspaceID)
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (s *AnalyticsServiceImpl) RecordClick(ctx context.Context, event model.ClickEvent) error {
+	go func() {
+		bgCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := s.analyticsRepo.SaveClickEvent(bgCtx, event); err != nil {
+			slog.Error("failed to save click event", "url_id", event.URLID, "error", err)
+			return
+		}
+		metrics.AnalyticsRecordsTotal.Inc()
+		if event.WorkspaceID != "" {
+			s.invalidateWorkspaceCaches(bgCtx, event.WorkspaceID)
+		}
+		if event.WorkspaceID != "" && event.Variant != "" {
+			_ = s.cache.Delete(bgCtx, fmt.Sprintf("url_variant_stats:%s:%s", event.WorkspaceID, event.URLID))
 		}
 	}()
 
@@ -195,13 +258,15 @@ func (s *AnalyticsServiceImpl) ProcessDailyAnalytics(ctx context.Context) error
 	return nil
 }
 
-func (s *AnalyticsServiceImpl) invalidateUserCaches(ctx context.Context, userID string) {
+func (s *AnalyticsServiceImpl) invalidateWorkspaceCaches(ctx context.Context, workspaceID string) {
+	if err := cache.BumpVersion(ctx, s.cache, "workspace_top_version:"+workspaceID); err != nil {
+		slog.Warn("failed to invalidate workspace top lists", "workspace_id", workspaceID, "error", err)
+	}
+
 	keysToDelete := []string{
-		fmt.Sprintf("user_top_urls:%s:10", userID),
-		fmt.Sprintf("user_daily_trend:%s:7", userID),
-		fmt.Sprintf("user_top_referrers:%s:5", userID),
-		fmt.Sprintf("user_device_breakdown:%s", userID),
-		cache.KeyUserAnalytics(s.salt, userID, time.Now().AddDate(0, 0, -7), time.Now()),
+		fmt.Sprintf("workspace_daily_trend:%s:7", workspaceID),
+		fmt.Sprintf("workspace_device_breakdown:%s", workspaceID),
+		cache.KeyWorkspaceAnalytics(s.salt, workspaceID, time.Now().AddDate(0, 0, -7), time.Now()),
 	}
 
 	for _, key := range keysToDelete {
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"url-shortener-go-backend/internal/cache"
+	"url-shortener-go-backend/internal/homograph"
+	"url-shortener-go-backend/internal/model"
+	"url-shortener-go-backend/internal/repository"
+	"url-shortener-go-backend/internal/utils"
+)
+
+const (
+	domainLookupTimeout  = 5 * time.Second
+	domainClaimExpiry    = 72 * time.Hour
+	domainHostCacheTTL   = time.Hour
+	domainMissingHostTTL = 5 * time.Minute
+	domainMissingMarker  = "-"
+)
+
+type TXTResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+type DomainService interface {
+	AddDomain(ctx context.Context, userID, hostname string) (*model.Domain, error)
+	ListDomains(ctx context.Context, userID string) ([]model.Domain, error)
+	VerifyDomain(ctx context.Context, userID, hostname string) (*model.Domain, error)
+	DeleteDomain(ctx context.Context, userID, hostname string) error
+	GetVerifiedDomain(ctx context.Context, userID, hostname string) (*model.Domain, error)
+	ResolveHost(ctx context.Context, host string) (string, bool)
+}
+
+type DomainServiceImpl struct {
+	repo        repository.DomainRepository
+	cache       cache.Cache
+	resolver    TXTResolver
+	defaultHost string
+}
+
+func NewDomainService(repo repository.DomainRepository, c cache.Cache, resolver TXTResolver, shortDomain string) DomainService {
+	defaultHost := shortDomain
+	if u, err := url.Parse(shortDomain); err == nil && u.Host != "" {
+		defaultHost = u.Hostname()
+	}
+	return &DomainServiceImpl{
+		repo:        repo,
+		cache:       c,
+		resolver:    resolver,
+		defaultHost: strings.ToLower(defaultHost),
+	}
+}
+
+func NormalizeHostname(raw string) (string, error) {
+	host := strings.ToLower(strings.TrimSpace(raw))
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(host, ".")
+	if host == "" || len(host) > 253 || net.ParseIP(host) != nil || !strings.Contains(host, ".") {
+		return "", ErrInvalidDomain
+	}
+
+	ascii, err := homograph.ToASCII(host)
+	if err != nil {
+		return "", ErrInvalidDomain
+	}
+	for _, label := range strings.Split(ascii, ".") {
+		if label == "" || len(label) > 63 || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return "", ErrInvalidDomain
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+				return "", ErrInvalidDomain
+			}
+		}
+	}
+	return ascii, nil
+}
+
+func (s *DomainServiceImpl) AddDomain(ctx context.Context, userID, hostname string) (*model.Domain, error) {
+	host, err := NormalizeHostname(hostname)
+	if err != nil {
+		return nil, err
+	}
+	if host == s.defaultHost {
+		return nil, ErrInvalidDomain
+	}
+
+	existing, err := s.repo.GetDomainByHostname(ctx, host)
+	switch {
+	case err == nil:
+		if existing.UserID == userID {
+			return existing, nil
+		}
+		if existing.IsVerified() || time.Since(existing.CreatedAt) < domainClaimExpiry {
+			return nil, ErrDomainTaken
+		}
+		if err := s.repo.DeleteDomain(ctx, existing.ID); err != nil {
+			return nil, err
+		}
+		slog.Info("expired domain claim released", "hostname", host, "previous_user_id", existing.UserID)
+	case !errors.Is(err, utils.ErrNotFound):
+		return nil, err
+	}
+
+	token, err := verificationToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate verification token: %w", err)
+	}
+
+	domain := &model.Domain{
+		UserID:            userID,
+		Hostname:          host,
+		VerificationToken: token,
+	}
+	if err := s.repo.CreateDomain(ctx, domain); err != nil {
+		if errors.Is(err, repository.ErrUniqueViolation) {
+			return nil, ErrDomainTaken
+		}
+		return nil, err
+	}
+
+	slog.Info("domain added", "hostname", host, "user_id", userID)
+	return domain, nil
+}
+
+func (s *DomainServiceImpl) ListDomains(ctx context.Context, userID string) ([]model.Domain, error) {
+	domains, err := s.repo.ListUserDomains(ctx, userID)
+	if err != nil {
+		slog.Error("failed to list domains", "user_id", userID, "error", err)
+		return nil, fmt.Errorf("failed to list domains: %w", err)
+	}
+	return domains, nil
+}
+
+func (s *DomainServiceImpl) VerifyDomain(ctx context.Context, userID, hostname string) (*model.Domain, error) {
+	domain, err := s.ownedDomain(ctx, userID, hostname)
+	if err != nil {
+		return nil, err
+	}
+	if domain.IsVerified() {
+		return domain, nil
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, domainLookupTimeout)
+	defer cancel()
+
+	records, err := s.resolver.LookupTXT(lookupCtx, domain.TXTRecordName())
+	if err != nil {
+		slog.Info("domain verification lookup failed", "hostname", domain.Hostname, "error", err)
+		return nil, ErrDomainVerificationFailed
+	}
+
+	want := domain.TXTRecordValue()
+	found := false
+	for _, record := range records {
+		if strings.TrimSpace(record) == want {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, ErrDomainVerificationFailed
+	}
+
+	verified, err := s.repo.MarkDomainVerified(ctx, domain.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.purgeHostCache(ctx, verified.Hostname)
+
+	slog.Info("domain verified", "hostname", verified.Hostname, "user_id", userID)
+	return verified, nil
+}
+
+func (s *DomainServiceImpl) DeleteDomain(ctx context.Context, userID, hostname string) error {
+	domain, err := s.ownedDomain(ctx, userID, hostname)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteDomain(ctx, domain.ID); err != nil {
+		return err
+	}
+	s.purgeHostCache(ctx, domain.Hostname)
+
+	slog.Info("domain deleted", "hostname", domain.Hostname, "user_id", userID)
+	return nil
+}
+
+func (s *DomainServiceImpl) GetVerifiedDomain(ctx context.Context, userID, hostname string) (*model.Domain, error) {
+	domain, err := s.ownedDomain(ctx, userID, hostname)
+	if err != nil {
+		return nil, err
+	}
+	if !domain.IsVerified() {
+		return nil, ErrDomainNotVerified
+	}
+	return domain, nil
+}
+
+func (s *DomainServiceImpl) ResolveHost(ctx context.Context, host string) (string, bool) {
+	host, err := NormalizeHostname(host)
+	if err != nil || host == s.defaultHost {
+		return "", false
+	}
+
+	cacheKey := "domain_host:" + host
+	if val, ok, err := s.cache.Get(ctx, cacheKey); err == nil && ok {
+		return val, val != domainMissingMarker
+	}
+
+	domain, err := s.repo.GetDomainByHostname(ctx, host)
+	if err != nil && !errors.Is(err, utils.ErrNotFound) {
+		slog.Error("domain lookup failed", "host", host, "error", err)
+		return "", false
+	}
+	if err != nil || !domain.IsVerified() {
+		_ = s.cache.Set(ctx, cacheKey, domainMissingMarker, domainMissingHostTTL)
+		return "", false
+	}
+
+	_ = s.cache.Set(ctx, cacheKey, domain.Hostname, domainHostCacheTTL)
+	return domain.Hostname, true
+}
+
+func (s *DomainServiceImpl) ownedDomain(ctx context.Context, userID, hostname string) (*model.Domain, error) {
+	host, err := NormalizeHostname(hostname)
+	if err != nil {
+		return nil, err
+	}
+	domain, err := s.repo.GetDomainByHostname(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	if domain.UserID != userID {
+		return nil, utils.ErrNotFound
+	}
+	return domain, nil
+}
+
+func (s *DomainServiceImpl) purgeHostCache(ctx context.Context, host string) {
+	if err := s.cache.Delete(ctx, "domain_host:"+host); err != nil {
+		slog.Warn("failed to delete cache key", "key", "domain_host:"+host, "error", err)
+	}
+}
+
+func verificationToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
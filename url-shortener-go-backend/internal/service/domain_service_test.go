@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"url-shortener-go-backend/internal/model"
+	"url-shortener-go-backend/internal/utils"
+)
+
+type fakeResolver struct {
+	records map[string][]string
+	err     error
+	block   bool
+}
+
+func (f *fakeResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	if f.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	records, ok := f.records[name]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	return records, nil
+}
+
+type fakeDomainRepo struct {
+	mu      sync.Mutex
+	domains map[string]*model.Domain
+}
+
+func (r *fakeDomainRepo) CreateDomain(ctx context.Context, domain *model.Domain) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	domain.ID = domain.Hostname
+	domain.CreatedAt = time.Now()
+	stored := *domain
+	r.domains[domain.Hostname] = &stored
+	return nil
+}
+
+func (r *fakeDomainRepo) GetDomainByHostname(ctx context.Context, hostname string) (*model.Domain, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d, ok := r.domains[hostname]
+	if !ok {
+		return nil, utils.ErrNotFound
+	}
+	copied := *d
+	return &copied, nil
+}
+
+func (r *fakeDomainRepo) ListUserDomains(ctx context.Context, userID string) ([]model.Domain, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []model.Domain
+	for _, d := range r.domains {
+		if d.UserID == userID {
+			out = append(out, *d)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeDomainRepo) MarkDomainVerified(ctx context.Context, id string) (*model.Domain, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, d := range r.domains {
+		if d.ID == id {
+			now := time.Now()
+			d.VerifiedAt = &now
+			copied := *d
+			return &copied, nil
+		}
+	}
+	return nil, utils.ErrNotFound
+}
+
+func (r *fakeDomainRepo) DeleteDomain(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.domains, id)
+	return nil
+}
+
+type memCache struct {
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func newMemCache() *memCache {
+	return &memCache{data: map[string]string{}}
+}
+
+func (c *memCache) Get(ctx context.Context, key string) (string, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.data[key]
+	return v, ok, nil
+}
+
+func (c *memCache) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.data[key] = value
+	return nil
+}
+
+func (c *memCache) Incr(ctx context.Context, key string) (int64, error) {
+	return 1, nil
+}
+
+func (c *memCache) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return nil
+}
+
+func (c *memCache) TTL(ctx context.Context, key string) (time.Duration, error) {
+	return 0, nil
+}
+
+func (c *memCache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.data, key)
+	return nil
+}
+
+func (c *memCache) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (c *memCache) Close() error {
+	return nil
+}
+
+func TestVerifyDomainTXT(t *testing.T) {
+	const host = "links.example.com"
+	const userID = "user-1"
+	const token = "abc123"
+	recordName := model.DomainVerificationPrefix + host
+	want := model.DomainVerificationValue + token
+
+	tests := []struct {
+		name     string
+		resolver *fakeResolver
+		ctx      func() (context.Context, context.CancelFunc)
+		wantErr  error
+	}{
+		{
+			name:     "match",
+			resolver: &fakeResolver{records: map[string][]string{recordName: {"v=spf1 -all", " " + want + " "}}},
+		},
+		{
+			name:     "mismatch",
+			resolver: &fakeResolver{records: map[string][]string{recordName: {model.DomainVerificationValue + "other"}}},
+			wantErr:  ErrDomainVerificationFailed,
+		},
+		{
+			name:     "nxdomain",
+			resolver: &fakeResolver{records: map[string][]string{}},
+			wantErr:  ErrDomainVerificationFailed,
+		},
+		{
+			name:     "resolver error",
+			resolver: &fakeResolver{err: &net.DNSError{Err: "server misbehaving", Name: recordName, IsTemporary: true}},
+			wantErr:  ErrDomainVerificationFailed,
+		},
+		{
+			name:     "timeout",
+			resolver: &fakeResolver{block: true},
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 20*time.Millisecond)
+			},
+			wantErr: ErrDomainVerificationFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeDomainRepo{domains: map[string]*model.Domain{
+				host: {ID: host, UserID: userID, Hostname: host, VerificationToken: token, CreatedAt: time.Now()},
+			}}
+			c := newMemCache()
+			_ = c.Set(context.Background(), "domain_host:"+host, domainMissingMarker, time.Minute)
+			svc := NewDomainService(repo, c, tt.resolver, "https://short.example")
+
+			ctx, cancel := context.Background(), context.CancelFunc(func() {})
+			if tt.ctx != nil {
+				ctx, cancel = tt.ctx()
+			}
+			defer cancel()
+
+			domain, err := svc.VerifyDomain(ctx, userID, host)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("VerifyDomain err = %v, want %v", err, tt.wantErr)
+				}
+				if stored, _ := repo.GetDomainByHostname(context.Background(), host); stored.IsVerified() {
+					t.Fatal("domain marked verified after failed verification")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("VerifyDomain: %v", err)
+			}
+			if !domain.IsVerified() {
+				t.Fatal("domain not verified")
+			}
+			if _, ok, _ := c.Get(context.Background(), "domain_host:"+host); ok {
+				t.Fatal("host cache entry not purged after verification")
+			}
+		})
+	}
+}
+
+func TestVerifyDomainOtherUser(t *testing.T) {
+	repo := &fakeDomainRepo{domains: map[string]*model.Domain{
+		"links.example.com": {ID: "links.example.com", UserID: "owner", Hostname: "links.example.com", VerificationToken: "t"},
+	}}
+	svc := NewDomainService(repo, newMemCache(), &fakeResolver{}, "https://short.example")
+
+	if _, err := svc.VerifyDomain(context.Background(), "intruder", "links.example.com"); !errors.Is(err, utils.ErrNotFound) {
+		t.Fatalf("VerifyDomain err = %v, want ErrNotFound", err)
+	}
+}
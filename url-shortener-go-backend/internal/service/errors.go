@@ -0,0 +1,25 @@
+package service
+
+import "errors"
+
+var (
+	ErrInvalidReportReason      = errors.New("invalid report reason")
+	ErrInvalidReportAction      = errors.New("invalid report action")
+	ErrReportAlreadyResolved    = errors.New("report already resolved")
+	ErrUnsafeURL                = errors.New("url failed reputation check")
+	ErrNotURLOwner              = errors.New("url does not belong to workspace")
+	ErrEmptyUpdate              = errors.New("no fields to update")
+	ErrMetadataDisabled         = errors.New("metadata fetching is disabled")
+	ErrMetadataFetch            = errors.New("failed to fetch link metadata")
+	ErrInvalidDomain            = errors.New("invalid domain name")
+	ErrDomainTaken              = errors.New("domain is already registered")
+	ErrDomainNotVerified        = errors.New("domain is not verified")
+	ErrDomainVerificationFailed = errors.New("domain verification record not found")
+	ErrNotWorkspaceMember       = errors.New("user is not a member of the workspace")
+	ErrWorkspaceForbidden       = errors.New("workspace role does not allow this action")
+	ErrInvalidWorkspace         = errors.New("invalid workspace")
+	ErrLastWorkspaceOwner       = errors.New("workspace must keep at least one owner")
+	ErrWorkspaceMemberExists    = errors.New("user is already a workspace member")
+	ErrAliasTaken               = errors.New("alias is already taken")
+	ErrWebhookLimit             = errors.New("workspace webhook limit reached")
+)
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"url-shortener-go-backend/internal/model"
+	"url-shortener-go-backend/internal/repository"
+)
+
+type fakeExpiryRepo struct {
+	repository.URLRepository
+	mu       sync.Mutex
+	urls     []model.URL
+	notified map[string]bool
+	claimed  map[string]bool
+}
+
+func (r *fakeExpiryRepo) ListUnnotifiedExpiredURLs(ctx context.Context, now time.Time, afterID string, limit int) ([]model.URL, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []model.URL
+	for _, u := range r.urls {
+		if u.ID > afterID && u.ExpiresAt != nil && !u.ExpiresAt.After(now) && !r.notified[u.ID] {
+			out = append(out, u)
+		}
+	}
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func (r *fakeExpiryRepo) MarkExpiryNotified(ctx context.Context, id string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.notified[id] || r.claimed[id] {
+		r.notified[id] = true
+		return false, nil
+	}
+	r.notified[id] = true
+	return true, nil
+}
+
+type recordedEvents struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (e *recordedEvents) LinkChanged(eventType string, url *model.URL) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.events = append(e.events, eventType+":"+url.ShortCode)
+}
+
+func (e *recordedEvents) LinkClicked(url *model.URL) {}
+
+func TestNotifyExpiredLinksOnce(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	ws := "ws-1"
+	repo := &fakeExpiryRepo{
+		urls: []model.URL{
+			{ID: "1", ShortCode: "expired1", WorkspaceID: &ws, ExpiresAt: &past},
+			{ID: "2", ShortCode: "raced", WorkspaceID: &ws, ExpiresAt: &past},
+			{ID: "3", ShortCode: "active", WorkspaceID: &ws, ExpiresAt: &future},
+		},
+		notified: map[string]bool{},
+		claimed:  map[string]bool{"2": true},
+	}
+	events := &recordedEvents{}
+	svc := &URLServiceImpl{repo: repo, events: events}
+
+	for i := 0; i < 2; i++ {
+		if err := svc.NotifyExpiredLinks(context.Background()); err != nil {
+			t.Fatalf("NotifyExpiredLinks: %v", err)
+		}
+	}
+
+	if len(events.events) != 1 || events.events[0] != model.EventLinkExpired+":expired1" {
+		t.Fatalf("events = %v, want one link.expired for expired1", events.events)
+	}
+}
+
+func TestNotifyExpiredLinksWithoutEvents(t *testing.T) {
+	svc := &URLServiceImpl{repo: &fakeExpiryRepo{notified: map[string]bool{}}}
+	if err := svc.NotifyExpiredLinks(context.Background()); err != nil {
+		t.Fatalf("NotifyExpiredLinks: %v", err)
+	}
+}
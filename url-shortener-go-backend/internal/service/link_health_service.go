@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"url-shortener-go-backend/internal/cache"
+	"url-shortener-go-backend/internal/linkhealth"
+	"url-shortener-go-backend/internal/metrics"
+	"url-shortener-go-backend/internal/model"
+	"url-shortener-go-backend/internal/repository"
+)
+
+const healthCheckBatchSize = 200
+
+type LinkHealthService interface {
+	CheckDueLinks(ctx context.Context) error
+}
+
+type LinkHealthServiceImpl struct {
+	repo   repository.URLRepository
+	cache  cache.Cache
+	runner *linkhealth.Runner
+	policy model.HealthPolicy
+}
+
+func NewLinkHealthService(repo repository.URLRepository, c cache.Cache, runner *linkhealth.Runner, policy model.HealthPolicy) LinkHealthService {
+	return &LinkHealthServiceImpl{
+		repo:   repo,
+		cache:  c,
+		runner: runner,
+		policy: policy,
+	}
+}
+
+func (s *LinkHealthServiceImpl) CheckDueLinks(ctx context.Context) error {
+	var (
+		mu              sync.Mutex
+		checked, broken int
+	)
+
+	now := time.Now()
+	afterID := ""
+	for {
+		urls, err := s.repo.ListDueHealthChecks(ctx, now, afterID, healthCheckBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to list urls for health check: %w", err)
+		}
+
+		targets := make([]string, len(urls))
+		for i, url := range urls {
+			targets[i] = url.OriginalURL
+		}
+
+		s.runner.Run(ctx, targets, func(i int, check model.HealthCheck) {
+			status, err := s.record(ctx, &urls[i], check)
+			if err != nil {
+				slog.Error("failed to store link health", "shortcode", urls[i].ShortCode, "error", err)
+				return
+			}
+			mu.Lock()
+			checked++
+			if status == model.HealthBroken {
+				broken++
+			}
+			mu.Unlock()
+		})
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if len(urls) < healthCheckBatchSize {
+			break
+		}
+		afterID = urls[len(urls)-1].ID
+	}
+
+	slog.Info("link health check finished", "checked", checked, "broken", broken)
+	return nil
+}
+
+func (s *LinkHealthServiceImpl) record(ctx context.Context, url *model.URL, check model.HealthCheck) (string, error) {
+	health := url.NextHealth(check, s.policy)
+	metrics.LinkHealthChecksTotal.WithLabelValues(health.Status).Inc()
+
+	updated, err := s.repo.SetURLHealth(ctx, url.Key(), health)
+	if err != nil {
+		return "", err
+	}
+
+	if health.Status != url.HealthStatus {
+		purgeURLCaches(ctx, s.cache, updated)
+		if health.Status == model.HealthBroken {
+			slog.Warn("link marked broken", "shortcode", url.ShortCode, "failures", health.Failures, "status_code", check.StatusCode, "error", check.Error)
+		}
+	}
+	return health.Status, nil
+}
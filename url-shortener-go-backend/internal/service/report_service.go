@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+
+	"url-shortener-go-backend/internal/model"
+	"url-shortener-go-backend/internal/repository"
+)
+
+const (
+	ReportActionDisable = "disable"
+	ReportActionDismiss = "dismiss"
+)
+
+type ReportService interface {
+	SubmitReport(ctx context.Context, shortcode, reason, details, reporterIP string, reporterUserID *string) (*model.Report, error)
+	ListReports(ctx context.Context, status string, limit int) ([]model.Report, error)
+	ResolveReport(ctx context.Context, reportID, adminID, action, note string) (*model.Report, error)
+}
+
+type ReportServiceImpl struct {
+	reportRepo repository.ReportRepository
+	urlService URLService
+	salt       string
+}
+
+func NewReportService(reportRepo repository.ReportRepository, urlService URLService, salt string) ReportService {
+	return &ReportServiceImpl{
+		reportRepo: reportRepo,
+		urlService: urlService,
+		salt:       salt,
+	}
+}
+
+func (s *ReportServiceImpl) SubmitReport(ctx context.Context, shortcode, reason, details, reporterIP string, reporterUserID *string) (*model.Report, error) {
+	if !model.ReportReasons[reason] {
+		return nil, ErrInvalidReportReason
+	}
+
+	url, err := s.urlService.GetURLByShortCode(ctx, shortcode)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &model.Report{
+		URLID:          url.ID,
+		ShortCode:      url.Key(),
+		Reason:         reason,
+		Details:        details,
+		ReporterIPHash: s.hashIP(reporterIP),
+		ReporterUserID: reporterUserID,
+	}
+
+	if err := s.reportRepo.CreateReport(ctx, report); err != nil {
+		slog.Error("failed to save report", "shortcode", shortcode, "error", err)
+		return nil, fmt.Errorf("failed to submit report: %w", err)
+	}
+
+	return report, nil
+}
+
+func (s *ReportServiceImpl) ListReports(ctx context.Context, status string, limit int) ([]model.Report, error) {
+	reports, err := s.reportRepo.ListReports(ctx, status, limit)
+	if err != nil {
+		slog.Error("failed to list reports", "status", status, "error", err)
+		return nil, fmt.Errorf("failed to list reports: %w", err)
+	}
+	return reports, nil
+}
+
+func (s *ReportServiceImpl) ResolveReport(ctx context.Context, reportID, adminID, action, note string) (*model.Report, error) {
+	if action != ReportActionDisable && action != ReportActionDismiss {
+		return nil, ErrInvalidReportAction
+	}
+
+	report, err := s.reportRepo.GetReport(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	if report.Status != model.ReportStatusOpen {
+		return nil, ErrReportAlreadyResolved
+	}
+
+	status := model.ReportStatusDismissed
+	if action == ReportActionDisable {
+		if _, err := s.urlService.SetURLDisabled(ctx, report.ShortCode, report.Reason, true); err != nil {
+			return nil, fmt.Errorf("failed to disable reported url: %w", err)
+		}
+		status = model.ReportStatusActioned
+	}
+
+	resolved, err := s.reportRepo.ResolveReport(ctx, reportID, status, note, adminID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve report: %w", err)
+	}
+
+	slog.Info("report resolved", "report_id", reportID, "action", action, "admin_id", adminID)
+	return resolved, nil
+}
+
+func (s *ReportServiceImpl) hashIP(ip string) string {
+	if ip == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(s.salt + ":" + ip))
+	return hex.EncodeToString(sum[:16])
+}
@@ -3,87 +3,208 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
 	"time"
 
 	"url-shortener-go-backend/internal/cache"
+	"url-shortener-go-backend/internal/metadata"
 	"url-shortener-go-backend/internal/metrics"
 	"url-shortener-go-backend/internal/model"
 	"url-shortener-go-backend/internal/repository"
+	"url-shortener-go-backend/internal/reputation"
+	"url-shortener-go-backend/internal/shortcode"
+	"url-shortener-go-backend/internal/urlnorm"
 	"url-shortener-go-backend/internal/utils"
+	"url-shortener-go-backend/internal/worker"
+)
+
+const (
+	reputationRecheckBatchSize = 200
+	expirySweepBatchSize       = 200
+	metadataQueueSize          = 1000
 )
 
 type URLService interface {
-	CreateShortURL(ctx context.Context, originalURL string, isPublic bool, userID *string, codeLength int) (*model.URL, error)
+	CreateShortURL(ctx context.Context, input model.CreateURLInput) (*model.URL, bool, error)
 	GetURLByShortCode(ctx context.Context, shortcode string) (*model.URL, error)
-	GetUserUrls(ctx context.Context, userID string) ([]model.URL, error)
+	GetWorkspaceURLs(ctx context.Context, workspaceID string, q model.URLQuery) (model.Page[model.URL], error)
 	IncrementClickCount(ctx context.Context, shortcode string) error
+	SetURLDisabled(ctx context.Context, shortcode, reason string, disabled bool) (*model.URL, error)
+	DeleteURL(ctx context.Context, shortcode string, access model.WorkspaceAccess) error
+	RecheckReputation(ctx context.Context) error
+	NotifyExpiredLinks(ctx context.Context) error
+	UpdateURL(ctx context.Context, shortcode string, access model.WorkspaceAccess, update model.URLUpdate) (*model.URL, error)
+	RefreshMetadata(ctx context.Context, shortcode string, access model.WorkspaceAccess) (*model.URL, error)
+	RunMetadataWorkers(ctx context.Context, workers int)
 }
 
 type URLServiceImpl struct {
-	repo  repository.URLRepository
-	cache cache.Cache
-	salt  string
+	repo       repository.URLRepository
+	cache      cache.Cache
+	codes      *shortcode.Mux
+	collisions shortcode.CollisionPolicy
+	reputation reputation.ReputationChecker
+	metadata   metadata.Fetcher
+	normalizer *urlnorm.Normalizer
+	metaQueue  *worker.Queue[string]
+	events     LinkEvents
 }
 
-func NewURLService(repo repository.URLRepository, c cache.Cache, salt string) URLService {
+func NewURLService(repo repository.URLRepository, c cache.Cache, codes *shortcode.Mux, collisions shortcode.CollisionPolicy, checker reputation.ReputationChecker, fetcher metadata.Fetcher, normalizer *urlnorm.Normalizer, events LinkEvents) URLService {
 	return &URLServiceImpl{
-		repo:  repo,
-		cache: c,
-		salt:  salt,
+		repo:       repo,
+		cache:      c,
+		codes:      codes,
+		collisions: collisions,
+		reputation: checker,
+		metadata:   fetcher,
+		normalizer: normalizer,
+		metaQueue:  worker.NewQueue[string]("link-metadata", metadataQueueSize),
+		events:     events,
 	}
 }
 
-func (s *URLServiceImpl) CreateShortURL(ctx context.Context, originalURL string, isPublic bool, userID *string, codeLength int) (*model.URL, error) {
-	cacheKey := fmt.Sprintf("short_url:%s:%v", originalURL, userID)
-	if val, ok, err := s.cache.Get(ctx, cacheKey); err == nil && ok {
-		var cachedURL model.URL
-		if err := json.Unmarshal([]byte(val), &cachedURL); err == nil {
-			return &cachedURL, nil
+func (s *URLServiceImpl) CreateShortURL(ctx context.Context, input model.CreateURLInput) (*model.URL, bool, error) {
+	originalURL, userID := input.OriginalURL, input.UserID
+
+	codeReq := shortcode.Request{URL: originalURL, Length: input.CodeLength, Style: input.CodeStyle}
+	if err := codeReq.Validate(); err != nil {
+		return nil, false, err
+	}
+	if input.Alias != "" {
+		if err := s.codes.ValidateAlias(input.Alias); err != nil {
+			return nil, false, err
 		}
 	}
 
-	shortcode, err := utils.GenerateCode(originalURL, codeLength, s.salt)
+	normalizedURL, err := s.normalizer.Normalize(originalURL)
 	if err != nil {
-		slog.Error("failed to generate shortcode", "error", err)
-		return nil, fmt.Errorf("failed to generate shortcode")
+		return nil, false, err
+	}
+
+	if err := s.checkReputation(ctx, originalURL); err != nil {
+		return nil, false, err
+	}
+	if normalizedURL != originalURL {
+		if err := s.checkReputation(ctx, normalizedURL); err != nil {
+			return nil, false, err
+		}
+	}
+	for _, rule := range input.Rules {
+		if err := s.checkReputation(ctx, rule.Destination); err != nil {
+			return nil, false, err
+		}
+	}
+	for _, v := range input.Variants {
+		if err := s.checkReputation(ctx, v.Destination); err != nil {
+			return nil, false, err
+		}
+	}
+	for _, destination := range input.DeepLinks.WebURLs() {
+		if err := s.checkReputation(ctx, destination); err != nil {
+			return nil, false, err
+		}
+	}
+
+	workspaceID := ""
+	if input.WorkspaceID != nil {
+		workspaceID = *input.WorkspaceID
+	}
+
+	destinationHash := utils.DestinationHash(normalizedURL)
+	if input.Alias == "" && !input.HasLinkOptions() && (input.Dedup == "" || input.Dedup == model.DedupReuse) {
+		existing, err := s.repo.FindURLByDestination(ctx, workspaceID, input.Domain, destinationHash, input.IsPublic)
+		if err == nil {
+			slog.Info("reusing existing link", "short_code", existing.ShortCode, "workspace_id", workspaceID)
+			return existing, true, nil
+		}
+		if !errors.Is(err, utils.ErrNotFound) {
+			slog.Error("dedup lookup failed", "error", err)
+			return nil, false, err
+		}
 	}
 
 	url := &model.URL{
-		ShortCode:   shortcode,
-		OriginalURL: originalURL,
-		IsPublic:    isPublic,
-		UserID:      userID,
-		CreatedAt:   time.Now(),
+		Domain:          input.Domain,
+		OriginalURL:     originalURL,
+		NormalizedURL:   normalizedURL,
+		DestinationHash: destinationHash,
+		IsPublic:        input.IsPublic,
+		UserID:          userID,
+		WorkspaceID:     input.WorkspaceID,
+		CreatedAt:       time.Now(),
+		ResolvedURL:     input.ResolvedURL,
+		ResolvedStatus:  input.ResolvedStatus,
+		RequirePreview:  input.RequirePreview,
+		RedirectStatus:  input.RedirectStatus,
+		CacheMaxAge:     input.CacheMaxAge,
+		NoStore:         input.NoStore,
+		ForwardQuery:    input.ForwardQuery,
+		Rules:           input.Rules,
+		Variants:        input.Variants,
+		DeepLinks:       input.DeepLinks,
+		Tags:            input.Tags,
+		Folder:          input.Folder,
+		ExpiresAt:       input.ExpiresAt,
 	}
 
-	for retries := 0; retries < 3; retries++ {
-		err := s.repo.SaveURL(ctx, url)
-		if err != nil && strings.Contains(err.Error(), "duplicate key value violates unique constraint") {
-			shortcode, err := utils.GenerateCode(originalURL, codeLength, s.salt)
-			if err != nil {
-				slog.Error("failed to generate shortcode on retry", "error", err)
-				return nil, fmt.Errorf("failed to generate shortcode")
+	if input.Alias != "" {
+		url.ShortCode = input.Alias
+		if err := s.repo.SaveURL(ctx, url); err != nil {
+			if errors.Is(err, repository.ErrUniqueViolation) {
+				return nil, false, ErrAliasTaken
 			}
-			url.ShortCode = shortcode
-			continue
-		}
-		if err != nil {
 			slog.Error("failed to save url", "error", err)
-			return nil, err
+			return nil, false, err
 		}
-		break
+	} else if err := s.saveWithFreshCode(ctx, url, codeReq); err != nil {
+		return nil, false, err
 	}
 
 	metrics.URLShortensTotal.Inc()
 
-	if jsonVal, err := json.Marshal(url); err == nil {
-		_ = s.cache.Set(ctx, cacheKey, string(jsonVal), time.Hour)
+	if workspaceID != "" {
+		invalidateWorkspaceURLs(ctx, s.cache, workspaceID)
 	}
 
-	return url, nil
+	if s.metadata != nil {
+		s.metaQueue.Enqueue(url.Key())
+	}
+
+	s.emit(model.EventLinkCreated, url)
+
+	return url, false, nil
+}
+
+func (s *URLServiceImpl) saveWithFreshCode(ctx context.Context, url *model.URL, req shortcode.Request) error {
+	attempts := s.collisions.Attempts()
+	for attempt := 1; ; attempt++ {
+		code, err := s.codes.Generate(ctx, req)
+		if err != nil {
+			slog.Error("failed to generate shortcode", "generator", s.codes.NameFor(req.Style), "error", err)
+			return err
+		}
+		url.ShortCode = code
+
+		err = s.repo.SaveURL(ctx, url)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, repository.ErrUniqueViolation) {
+			slog.Error("failed to save url", "error", err)
+			return err
+		}
+
+		metrics.ShortCodeCollisionsTotal.WithLabelValues(s.codes.NameFor(req.Style)).Inc()
+		slog.Warn("short code collision", "generator", s.codes.NameFor(req.Style), "attempt", attempt, "length", len(code))
+		if attempt >= attempts {
+			return shortcode.ErrExhausted
+		}
+		req = s.collisions.Next(req)
+	}
 }
 
 func (s *URLServiceImpl) GetURLByShortCode(ctx context.Context, shortcode string) (*model.URL, error) {
@@ -108,26 +229,32 @@ func (s *URLServiceImpl) GetURLByShortCode(ctx context.Context, shortcode string
 	return url, nil
 }
 
-func (s *URLServiceImpl) GetUserUrls(ctx context.Context, userID string) ([]model.URL, error) {
-	cacheKey := "user_urls:" + userID
+func (s *URLServiceImpl) GetWorkspaceURLs(ctx context.Context, workspaceID string, q model.URLQuery) (model.Page[model.URL], error) {
+	cacheKey := ""
+	if !q.HasFilters() {
+		version := cache.Version(ctx, s.cache, "workspace_urls_version:"+workspaceID)
+		cacheKey = fmt.Sprintf("workspace_urls:%s:%d:%s:%d:%s", workspaceID, version, q.SortKey(), q.EffectiveLimit(), q.Cursor)
 
-	if val, ok, err := s.cache.Get(ctx, cacheKey); err == nil && ok {
-		var urls []model.URL
-		if err := json.Unmarshal([]byte(val), &urls); err == nil {
-			return urls, nil
+		if val, ok, err := s.cache.Get(ctx, cacheKey); err == nil && ok {
+			var page model.Page[model.URL]
+			if err := json.Unmarshal([]byte(val), &page); err == nil {
+				return page, nil
+			}
 		}
 	}
 
-	urls, err := s.repo.GetUserUrls(ctx, userID)
+	page, err := s.repo.GetWorkspaceURLs(ctx, workspaceID, q)
 	if err != nil {
-		slog.Error("db fetch failed for user urls", "user_id", userID, "error", err)
-		return nil, err
+		slog.Error("db fetch failed for workspace urls", "workspace_id", workspaceID, "error", err)
+		return model.Page[model.URL]{}, err
 	}
 
-	jsonVal, _ := json.Marshal(urls)
-	_ = s.cache.Set(ctx, cacheKey, string(jsonVal), time.Hour)
+	if cacheKey != "" {
+		jsonVal, _ := json.Marshal(page)
+		_ = s.cache.Set(ctx, cacheKey, string(jsonVal), time.Hour)
+	}
 
-	return urls, nil
+	return page, nil
 }
 
 func (s *URLServiceImpl) IncrementClickCount(ctx context.Context, shortcode string) error {
@@ -137,3 +264,311 @@ func (s *URLServiceImpl) IncrementClickCount(ctx context.Context, shortcode stri
 	}
 	return err
 }
+
+func (s *URLServiceImpl) SetURLDisabled(ctx context.Context, shortcode, reason string, disabled bool) (*model.URL, error) {
+	url, err := s.repo.SetURLDisabled(ctx, shortcode, reason, disabled)
+	if err != nil {
+		slog.Error("failed to update url status", "shortcode", shortcode, "disabled", disabled, "error", err)
+		return nil, err
+	}
+
+	purgeURLCaches(ctx, s.cache, url)
+	s.emit(model.EventLinkUpdated, url)
+
+	slog.Info("url status updated", "shortcode", shortcode, "disabled", disabled, "reason", reason)
+	return url, nil
+}
+
+func (s *URLServiceImpl) UpdateURL(ctx context.Context, shortcode string, access model.WorkspaceAccess, update model.URLUpdate) (*model.URL, error) {
+	if update.IsEmpty() {
+		return nil, ErrEmptyUpdate
+	}
+
+	existing, err := s.repo.GetURLByShortCode(ctx, shortcode)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeURL(existing, access, model.RoleEditor); err != nil {
+		return nil, err
+	}
+
+	if update.Rules != nil {
+		for _, rule := range *update.Rules {
+			if err := s.checkReputation(ctx, rule.Destination); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if update.Variants != nil {
+		for _, v := range *update.Variants {
+			if err := s.checkReputation(ctx, v.Destination); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, destination := range update.DeepLinks.WebURLs() {
+		if err := s.checkReputation(ctx, destination); err != nil {
+			return nil, err
+		}
+	}
+
+	url, err := s.repo.UpdateURL(ctx, shortcode, update)
+	if err != nil {
+		slog.Error("failed to update url", "shortcode", shortcode, "error", err)
+		return nil, err
+	}
+
+	purgeURLCaches(ctx, s.cache, url)
+	s.emit(model.EventLinkUpdated, url)
+
+	slog.Info("url updated", "shortcode", shortcode, "workspace_id", access.WorkspaceID, "user_id", access.UserID)
+	return url, nil
+}
+
+func (s *URLServiceImpl) DeleteURL(ctx context.Context, shortcode string, access model.WorkspaceAccess) error {
+	existing, err := s.repo.GetURLByShortCode(ctx, shortcode)
+	if err != nil {
+		return err
+	}
+	if err := authorizeURL(existing, access, model.RoleEditor); err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteURL(ctx, shortcode); err != nil {
+		return err
+	}
+
+	purgeURLCaches(ctx, s.cache, existing)
+	s.emit(model.EventLinkDeleted, existing)
+
+	slog.Info("url deleted", "shortcode", shortcode, "workspace_id", access.WorkspaceID, "user_id", access.UserID)
+	return nil
+}
+
+func (s *URLServiceImpl) RefreshMetadata(ctx context.Context, shortcode string, access model.WorkspaceAccess) (*model.URL, error) {
+	if s.metadata == nil {
+		return nil, ErrMetadataDisabled
+	}
+
+	existing, err := s.repo.GetURLByShortCode(ctx, shortcode)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeURL(existing, access, model.RoleEditor); err != nil {
+		return nil, err
+	}
+
+	return s.refreshMetadata(ctx, existing)
+}
+
+func (s *URLServiceImpl) RunMetadataWorkers(ctx context.Context, workers int) {
+	if s.metadata == nil {
+		return
+	}
+
+	s.metaQueue.Run(ctx, workers, func(ctx context.Context, shortcode string) error {
+		url, err := s.repo.GetURLByShortCode(ctx, shortcode)
+		if err != nil {
+			return err
+		}
+		_, err = s.refreshMetadata(ctx, url)
+		return err
+	})
+}
+
+func (s *URLServiceImpl) refreshMetadata(ctx context.Context, url *model.URL) (*model.URL, error) {
+	meta, err := s.metadata.Fetch(ctx, url.OriginalURL)
+	if err != nil {
+		slog.Warn("metadata fetch failed", "shortcode", url.ShortCode, "error", err)
+		return nil, fmt.Errorf("%w: %v", ErrMetadataFetch, err)
+	}
+
+	updated, err := s.repo.SetURLMetadata(ctx, url.Key(), *meta)
+	if err != nil {
+		slog.Error("failed to store url metadata", "shortcode", url.ShortCode, "error", err)
+		return nil, err
+	}
+
+	purgeURLCaches(ctx, s.cache, updated)
+
+	slog.Info("url metadata refreshed", "shortcode", url.ShortCode)
+	return updated, nil
+}
+
+func (s *URLServiceImpl) emit(eventType string, url *model.URL) {
+	if s.events != nil {
+		s.events.LinkChanged(eventType, url)
+	}
+}
+
+func purgeURLCaches(ctx context.Context, c cache.Cache, url *model.URL) {
+	key := "short_url:" + url.Key()
+	if err := c.Delete(ctx, key); err != nil {
+		slog.Warn("failed to delete cache key", "key", key, "error", err)
+	}
+	if url.WorkspaceID != nil && *url.WorkspaceID != "" {
+		invalidateWorkspaceURLs(ctx, c, *url.WorkspaceID)
+	}
+}
+
+func invalidateWorkspaceURLs(ctx context.Context, c cache.Cache, workspaceID string) {
+	if err := cache.BumpVersion(ctx, c, "workspace_urls_version:"+workspaceID); err != nil {
+		slog.Warn("failed to invalidate workspace url pages", "workspace_id", workspaceID, "error", err)
+	}
+}
+
+func authorizeURL(url *model.URL, access model.WorkspaceAccess, required model.Role) error {
+	if url.WorkspaceID == nil || *url.WorkspaceID != access.WorkspaceID {
+		return ErrNotURLOwner
+	}
+	if !access.Can(required) {
+		return ErrWorkspaceForbidden
+	}
+	return nil
+}
+
+func (s *URLServiceImpl) checkReputation(ctx context.Context, rawURL string) error {
+	if s.reputation == nil {
+		return nil
+	}
+
+	verdict, err := s.reputation.Check(ctx, rawURL)
+	if err != nil {
+		slog.Error("reputation check failed", "error", err)
+		return err
+	}
+
+	if !verdict.Safe {
+		slog.Warn("url rejected by reputation check", "threat", verdict.Threat, "source", verdict.Source)
+		return fmt.Errorf("%w: %s", ErrUnsafeURL, verdict.Threat)
+	}
+	if verdict.Suspect {
+		slog.Warn("url matched a blocklist prefix without full hash confirmation", "threat", verdict.Threat, "source", verdict.Source)
+	}
+
+	return nil
+}
+
+func (s *URLServiceImpl) RecheckReputation(ctx context.Context) error {
+	if s.reputation == nil {
+		return nil
+	}
+
+	checked, disabled := 0, 0
+	afterID := ""
+	for {
+		urls, err := s.repo.ListActiveURLs(ctx, afterID, reputationRecheckBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to list urls for reputation recheck: %w", err)
+		}
+
+		for _, url := range urls {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			checked++
+			verdict, err := s.recheckDestinations(ctx, url)
+			if err != nil {
+				slog.Warn("reputation recheck failed", "shortcode", url.ShortCode, "error", err)
+				continue
+			}
+			if verdict.Safe {
+				if verdict.Suspect {
+					slog.Warn("url matched a blocklist prefix without full hash confirmation", "shortcode", url.ShortCode, "threat", verdict.Threat)
+				}
+				continue
+			}
+
+			if _, err := s.SetURLDisabled(ctx, url.Key(), reasonForThreat(verdict.Threat), true); err != nil {
+				slog.Error("failed to disable unsafe url", "shortcode", url.ShortCode, "error", err)
+				continue
+			}
+			disabled++
+			slog.Warn("url disabled by reputation recheck", "shortcode", url.ShortCode, "threat", verdict.Threat, "source", verdict.Source)
+		}
+
+		if len(urls) < reputationRecheckBatchSize {
+			break
+		}
+		afterID = urls[len(urls)-1].ID
+	}
+
+	slog.Info("reputation recheck finished", "checked", checked, "disabled", disabled)
+	return nil
+}
+
+func (s *URLServiceImpl) NotifyExpiredLinks(ctx context.Context) error {
+	if s.events == nil {
+		return nil
+	}
+
+	notified := 0
+	afterID := ""
+	for {
+		urls, err := s.repo.ListUnnotifiedExpiredURLs(ctx, time.Now(), afterID, expirySweepBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to list expired urls: %w", err)
+		}
+
+		for i := range urls {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			claimed, err := s.repo.MarkExpiryNotified(ctx, urls[i].ID)
+			if err != nil {
+				slog.Warn("failed to mark link expiry", "shortcode", urls[i].ShortCode, "error", err)
+				continue
+			}
+			if !claimed {
+				continue
+			}
+			s.emit(model.EventLinkExpired, &urls[i])
+			notified++
+		}
+
+		if len(urls) < expirySweepBatchSize {
+			break
+		}
+		afterID = urls[len(urls)-1].ID
+	}
+
+	if notified > 0 {
+		slog.Info("expired links notified", "count", notified)
+	}
+	return nil
+}
+
+func (s *URLServiceImpl) recheckDestinations(ctx context.Context, url model.URL) (reputation.Verdict, error) {
+	destinations := url.Destinations()
+	if url.NormalizedURL != "" && url.NormalizedURL != url.OriginalURL {
+		destinations = append(destinations, url.NormalizedURL)
+	}
+
+	var verdict reputation.Verdict
+	for _, destination := range destinations {
+		v, err := s.reputation.Check(ctx, destination)
+		if err != nil {
+			return v, err
+		}
+		if !v.Safe {
+			return v, nil
+		}
+		if !verdict.Suspect {
+			verdict = v
+		}
+	}
+	return verdict, nil
+}
+
+func reasonForThreat(threat string) string {
+	switch t := strings.ToLower(threat); {
+	case strings.Contains(t, "social_engineering"), strings.Contains(t, "phish"):
+		return model.ReportReasonPhishing
+	case strings.Contains(t, "malware"), strings.Contains(t, "unwanted_software"), strings.Contains(t, "harmful"):
+		return model.ReportReasonMalware
+	default:
+		return model.ReportReasonOther
+	}
+}
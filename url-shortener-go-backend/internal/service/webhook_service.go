@@ -0,0 +1,508 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"url-shortener-go-backend/internal/cache"
+	"url-shortener-go-backend/internal/metrics"
+	"url-shortener-go-backend/internal/model"
+	"url-shortener-go-backend/internal/repository"
+	"url-shortener-go-backend/internal/utils"
+	"url-shortener-go-backend/internal/webhook"
+	"url-shortener-go-backend/internal/worker"
+)
+
+const (
+	webhookQueueSize      = 5000
+	webhookRetryBatchSize = 200
+	webhookCacheTTL       = 10 * time.Minute
+	webhookDeliveryLease  = time.Minute
+	maxBufferedClickLinks = 10000
+
+	DefaultDeliveryPageSize = 50
+	MaxDeliveryPageSize     = 100
+)
+
+type LinkEvents interface {
+	LinkChanged(eventType string, url *model.URL)
+	LinkClicked(url *model.URL)
+}
+
+type WebhookService interface {
+	LinkEvents
+	CreateWebhook(ctx context.Context, access model.WorkspaceAccess, input model.WebhookInput) (*model.Webhook, error)
+	ListWebhooks(ctx context.Context, access model.WorkspaceAccess) ([]model.Webhook, error)
+	GetWebhook(ctx context.Context, access model.WorkspaceAccess, id string) (*model.Webhook, error)
+	UpdateWebhook(ctx context.Context, access model.WorkspaceAccess, id string, update model.WebhookUpdate) (*model.Webhook, error)
+	RotateSecret(ctx context.Context, access model.WorkspaceAccess, id string) (*model.Webhook, error)
+	DeleteWebhook(ctx context.Context, access model.WorkspaceAccess, id string) error
+	ListDeliveries(ctx context.Context, access model.WorkspaceAccess, id, status string, limit int) ([]model.WebhookDelivery, error)
+	RunWorkers(ctx context.Context)
+	RetryDeliveries(ctx context.Context) error
+	FlushClicks(ctx context.Context) error
+	PruneDeliveries(ctx context.Context) error
+}
+
+type clickWindow struct {
+	workspaceID string
+	link        model.WebhookLink
+	count       int64
+	from, to    time.Time
+}
+
+type webhookTarget struct {
+	ID     string   `json:"id"`
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+}
+
+type WebhookServiceImpl struct {
+	repo        repository.WebhookRepository
+	cache       cache.Cache
+	sender      *webhook.Sender
+	policy      model.DeliveryPolicy
+	concurrency int
+	retention   time.Duration
+	queue       *worker.Queue[model.WebhookEvent]
+
+	clicksMu sync.Mutex
+	clicks   map[string]*clickWindow
+}
+
+func NewWebhookService(repo repository.WebhookRepository, c cache.Cache, sender *webhook.Sender, policy model.DeliveryPolicy, concurrency int, retention time.Duration) WebhookService {
+	return &WebhookServiceImpl{
+		repo:        repo,
+		cache:       c,
+		sender:      sender,
+		policy:      policy,
+		concurrency: max(concurrency, 1),
+		retention:   retention,
+		queue:       worker.NewQueue[model.WebhookEvent]("webhook-events", webhookQueueSize),
+		clicks:      make(map[string]*clickWindow),
+	}
+}
+
+func (s *WebhookServiceImpl) CreateWebhook(ctx context.Context, access model.WorkspaceAccess, input model.WebhookInput) (*model.Webhook, error) {
+	if !access.Can(model.RoleOwner) {
+		return nil, ErrWorkspaceForbidden
+	}
+
+	endpoint, err := webhook.ValidateEndpoint(input.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.ListWorkspaceWebhooks(ctx, access.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= model.MaxWebhooksPerWorkspace {
+		return nil, ErrWebhookLimit
+	}
+
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	hook := &model.Webhook{
+		WorkspaceID: access.WorkspaceID,
+		URL:         endpoint,
+		Description: input.Description,
+		Secret:      secret,
+		Events:      input.Events,
+		Active:      true,
+		CreatedBy:   access.UserID,
+	}
+	if err := s.repo.CreateWebhook(ctx, hook); err != nil {
+		return nil, err
+	}
+	s.purgeWebhookCache(ctx, access.WorkspaceID)
+
+	slog.Info("webhook created", "id", hook.ID, "workspace_id", access.WorkspaceID, "events", hook.Events)
+	return hook, nil
+}
+
+func (s *WebhookServiceImpl) ListWebhooks(ctx context.Context, access model.WorkspaceAccess) ([]model.Webhook, error) {
+	if !access.Can(model.RoleEditor) {
+		return nil, ErrWorkspaceForbidden
+	}
+
+	hooks, err := s.repo.ListWorkspaceWebhooks(ctx, access.WorkspaceID)
+	if err != nil {
+		slog.Error("failed to list webhooks", "workspace_id", access.WorkspaceID, "error", err)
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	for i := range hooks {
+		hooks[i].Secret = ""
+	}
+	return hooks, nil
+}
+
+func (s *WebhookServiceImpl) GetWebhook(ctx context.Context, access model.WorkspaceAccess, id string) (*model.Webhook, error) {
+	hook, err := s.ownedWebhook(ctx, access, id, model.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	hook.Secret = ""
+	return hook, nil
+}
+
+func (s *WebhookServiceImpl) UpdateWebhook(ctx context.Context, access model.WorkspaceAccess, id string, update model.WebhookUpdate) (*model.Webhook, error) {
+	hook, err := s.updateWebhook(ctx, access, id, update)
+	if err != nil {
+		return nil, err
+	}
+	hook.Secret = ""
+	return hook, nil
+}
+
+func (s *WebhookServiceImpl) updateWebhook(ctx context.Context, access model.WorkspaceAccess, id string, update model.WebhookUpdate) (*model.Webhook, error) {
+	if update.IsEmpty() {
+		return nil, ErrEmptyUpdate
+	}
+	if _, err := s.ownedWebhook(ctx, access, id, model.RoleOwner); err != nil {
+		return nil, err
+	}
+
+	if update.URL != nil {
+		endpoint, err := webhook.ValidateEndpoint(*update.URL)
+		if err != nil {
+			return nil, err
+		}
+		update.URL = &endpoint
+	}
+
+	hook, err := s.repo.UpdateWebhook(ctx, id, update)
+	if err != nil {
+		return nil, err
+	}
+	s.purgeWebhookCache(ctx, access.WorkspaceID)
+
+	slog.Info("webhook updated", "id", id, "workspace_id", access.WorkspaceID, "user_id", access.UserID)
+	return hook, nil
+}
+
+func (s *WebhookServiceImpl) RotateSecret(ctx context.Context, access model.WorkspaceAccess, id string) (*model.Webhook, error) {
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return s.updateWebhook(ctx, access, id, model.WebhookUpdate{Secret: &secret})
+}
+
+func (s *WebhookServiceImpl) DeleteWebhook(ctx context.Context, access model.WorkspaceAccess, id string) error {
+	if _, err := s.ownedWebhook(ctx, access, id, model.RoleOwner); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteWebhook(ctx, id); err != nil {
+		return err
+	}
+	s.purgeWebhookCache(ctx, access.WorkspaceID)
+
+	slog.Info("webhook deleted", "id", id, "workspace_id", access.WorkspaceID, "user_id", access.UserID)
+	return nil
+}
+
+func (s *WebhookServiceImpl) ListDeliveries(ctx context.Context, access model.WorkspaceAccess, id, status string, limit int) ([]model.WebhookDelivery, error) {
+	if _, err := s.ownedWebhook(ctx, access, id, model.RoleEditor); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > MaxDeliveryPageSize {
+		limit = DefaultDeliveryPageSize
+	}
+
+	deliveries, err := s.repo.ListDeliveries(ctx, id, status, limit)
+	if err != nil {
+		slog.Error("failed to list webhook deliveries", "webhook_id", id, "error", err)
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+func (s *WebhookServiceImpl) LinkChanged(eventType string, url *model.URL) {
+	if url == nil || url.WorkspaceID == nil || *url.WorkspaceID == "" {
+		return
+	}
+	s.publish(model.WebhookEvent{
+		Type:        eventType,
+		WorkspaceID: *url.WorkspaceID,
+		Data:        model.WebhookEventData{Link: model.NewWebhookLink(url)},
+	})
+}
+
+func (s *WebhookServiceImpl) LinkClicked(url *model.URL) {
+	if url == nil || url.WorkspaceID == nil || *url.WorkspaceID == "" {
+		return
+	}
+
+	now := time.Now().UTC()
+	s.clicksMu.Lock()
+	defer s.clicksMu.Unlock()
+
+	window, ok := s.clicks[url.Key()]
+	if !ok {
+		if len(s.clicks) >= maxBufferedClickLinks {
+			metrics.WebhookEventsTotal.WithLabelValues(model.EventLinkClicked, "dropped").Inc()
+			return
+		}
+		window = &clickWindow{workspaceID: *url.WorkspaceID, link: model.NewWebhookLink(url), from: now}
+		s.clicks[url.Key()] = window
+	}
+	window.count++
+	window.to = now
+}
+
+func (s *WebhookServiceImpl) FlushClicks(ctx context.Context) error {
+	s.clicksMu.Lock()
+	windows := s.clicks
+	s.clicks = make(map[string]*clickWindow, len(windows))
+	s.clicksMu.Unlock()
+
+	for _, window := range windows {
+		hooks, err := s.subscribers(ctx, window.workspaceID, model.EventLinkClicked)
+		if err != nil {
+			slog.Warn("failed to load webhooks for click flush", "workspace_id", window.workspaceID, "error", err)
+			continue
+		}
+		if len(hooks) == 0 {
+			continue
+		}
+		s.publish(model.WebhookEvent{
+			Type:        model.EventLinkClicked,
+			WorkspaceID: window.workspaceID,
+			Data: model.WebhookEventData{
+				Link:   window.link,
+				Clicks: &model.WebhookClicks{Count: window.count, From: window.from, To: window.to},
+			},
+		})
+	}
+	return nil
+}
+
+func (s *WebhookServiceImpl) RunWorkers(ctx context.Context) {
+	s.queue.Run(ctx, s.concurrency, s.dispatch)
+}
+
+func (s *WebhookServiceImpl) RetryDeliveries(ctx context.Context) error {
+	deliveries, err := s.repo.ListDueDeliveries(ctx, time.Now(), webhookRetryBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to list due webhook deliveries: %w", err)
+	}
+
+	hooks := make(map[string]*model.Webhook)
+	for i := range deliveries {
+		id := deliveries[i].WebhookID
+		if _, ok := hooks[id]; ok {
+			continue
+		}
+		hook, err := s.repo.GetWebhook(ctx, id)
+		if err != nil && !errors.Is(err, utils.ErrNotFound) {
+			return err
+		}
+		hooks[id] = hook
+	}
+
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, s.concurrency)
+	)
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		hook := hooks[delivery.WebhookID]
+		if hook == nil || !hook.Active {
+			s.abandon(ctx, delivery, "webhook is disabled")
+			continue
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return ctx.Err()
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			s.attempt(ctx, *hook, delivery)
+		}()
+	}
+	wg.Wait()
+
+	if len(deliveries) > 0 {
+		slog.Info("webhook retries finished", "deliveries", len(deliveries))
+	}
+	return nil
+}
+
+func (s *WebhookServiceImpl) PruneDeliveries(ctx context.Context) error {
+	if s.retention <= 0 {
+		return nil
+	}
+	return s.repo.DeleteDeliveriesBefore(ctx, time.Now().Add(-s.retention))
+}
+
+func (s *WebhookServiceImpl) publish(event model.WebhookEvent) {
+	id, err := newEventID()
+	if err != nil {
+		slog.Error("failed to generate webhook event id", "error", err)
+		return
+	}
+	event.ID = id
+	event.CreatedAt = time.Now().UTC()
+
+	outcome := "queued"
+	if !s.queue.Enqueue(event) {
+		outcome = "dropped"
+	}
+	metrics.WebhookEventsTotal.WithLabelValues(event.Type, outcome).Inc()
+}
+
+func (s *WebhookServiceImpl) dispatch(ctx context.Context, event model.WebhookEvent) error {
+	hooks, err := s.subscribers(ctx, event.WorkspaceID, event.Type)
+	if err != nil {
+		return err
+	}
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook event: %w", err)
+	}
+
+	for _, target := range hooks {
+		hook, err := s.repo.GetWebhook(ctx, target.ID)
+		if errors.Is(err, utils.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			slog.Error("failed to load webhook", "webhook_id", target.ID, "event_id", event.ID, "error", err)
+			continue
+		}
+		if !hook.Active || !hook.Subscribes(event.Type) {
+			continue
+		}
+
+		lease := time.Now().Add(webhookDeliveryLease)
+		delivery := &model.WebhookDelivery{
+			WebhookID:     hook.ID,
+			EventID:       event.ID,
+			EventType:     event.Type,
+			Payload:       payload,
+			Status:        model.DeliveryPending,
+			NextAttemptAt: &lease,
+		}
+		if err := s.repo.CreateDelivery(ctx, delivery); err != nil {
+			slog.Error("failed to record webhook delivery", "webhook_id", hook.ID, "event_id", event.ID, "error", err)
+			continue
+		}
+		s.attempt(ctx, *hook, delivery)
+	}
+	return nil
+}
+
+func (s *WebhookServiceImpl) attempt(ctx context.Context, hook model.Webhook, delivery *model.WebhookDelivery) {
+	result := s.sender.Send(ctx, hook, *delivery)
+	delivery.Record(result, s.policy, time.Now().UTC())
+	metrics.WebhookDeliveriesTotal.WithLabelValues(delivery.Status).Inc()
+
+	if delivery.Status != model.DeliverySucceeded {
+		slog.Warn("webhook delivery failed", "webhook_id", hook.ID, "event_id", delivery.EventID,
+			"attempt", delivery.Attempts, "status", delivery.Status, "response_status", result.StatusCode, "error", result.Error)
+	}
+
+	if err := s.repo.UpdateDelivery(ctx, *delivery); err != nil {
+		slog.Error("failed to update webhook delivery", "id", delivery.ID, "error", err)
+	}
+}
+
+func (s *WebhookServiceImpl) abandon(ctx context.Context, delivery *model.WebhookDelivery, reason string) {
+	delivery.Status = model.DeliveryFailed
+	delivery.Error = reason
+	delivery.NextAttemptAt = nil
+	metrics.WebhookDeliveriesTotal.WithLabelValues(delivery.Status).Inc()
+	if err := s.repo.UpdateDelivery(ctx, *delivery); err != nil {
+		slog.Error("failed to update webhook delivery", "id", delivery.ID, "error", err)
+	}
+}
+
+func (s *WebhookServiceImpl) subscribers(ctx context.Context, workspaceID, eventType string) ([]webhookTarget, error) {
+	targets, err := s.activeWebhooks(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var subscribed []webhookTarget
+	for _, target := range targets {
+		if slices.Contains(target.Events, eventType) {
+			subscribed = append(subscribed, target)
+		}
+	}
+	return subscribed, nil
+}
+
+func (s *WebhookServiceImpl) activeWebhooks(ctx context.Context, workspaceID string) ([]webhookTarget, error) {
+	cacheKey := "webhooks:" + workspaceID
+	if val, ok, err := s.cache.Get(ctx, cacheKey); err == nil && ok {
+		var targets []webhookTarget
+		if err := json.Unmarshal([]byte(val), &targets); err == nil {
+			return targets, nil
+		}
+	}
+
+	all, err := s.repo.ListWorkspaceWebhooks(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make([]webhookTarget, 0, len(all))
+	for _, hook := range all {
+		if hook.Active {
+			targets = append(targets, webhookTarget{ID: hook.ID, URL: hook.URL, Events: hook.Events})
+		}
+	}
+
+	jsonVal, _ := json.Marshal(targets)
+	_ = s.cache.Set(ctx, cacheKey, string(jsonVal), webhookCacheTTL)
+	return targets, nil
+}
+
+func (s *WebhookServiceImpl) ownedWebhook(ctx context.Context, access model.WorkspaceAccess, id string, required model.Role) (*model.Webhook, error) {
+	if !access.Can(required) {
+		return nil, ErrWorkspaceForbidden
+	}
+	hook, err := s.repo.GetWebhook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if hook.WorkspaceID != access.WorkspaceID {
+		return nil, utils.ErrNotFound
+	}
+	return hook, nil
+}
+
+func (s *WebhookServiceImpl) purgeWebhookCache(ctx context.Context, workspaceID string) {
+	if err := s.cache.Delete(ctx, "webhooks:"+workspaceID); err != nil {
+		slog.Warn("failed to delete cache key", "key", "webhooks:"+workspaceID, "error", err)
+	}
+}
+
+func newEventID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "evt_" + hex.EncodeToString(b), nil
+}
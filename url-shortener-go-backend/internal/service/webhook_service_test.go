@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"url-shortener-go-backend/internal/model"
+	"url-shortener-go-backend/internal/netguard"
+	"url-shortener-go-backend/internal/utils"
+	"url-shortener-go-backend/internal/webhook"
+)
+
+type fakeWebhookRepo struct {
+	mu         sync.Mutex
+	hooks      map[string]*model.Webhook
+	deliveries map[string]*model.WebhookDelivery
+	nextID     int
+}
+
+func newFakeWebhookRepo() *fakeWebhookRepo {
+	return &fakeWebhookRepo{hooks: map[string]*model.Webhook{}, deliveries: map[string]*model.WebhookDelivery{}}
+}
+
+func (r *fakeWebhookRepo) id() string {
+	r.nextID++
+	return fmt.Sprintf("00000000-0000-0000-0000-%012d", r.nextID)
+}
+
+func (r *fakeWebhookRepo) CreateWebhook(ctx context.Context, hook *model.Webhook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	hook.ID = r.id()
+	hook.CreatedAt = time.Now()
+	stored := *hook
+	r.hooks[hook.ID] = &stored
+	return nil
+}
+
+func (r *fakeWebhookRepo) GetWebhook(ctx context.Context, id string) (*model.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	hook, ok := r.hooks[id]
+	if !ok {
+		return nil, utils.ErrNotFound
+	}
+	copied := *hook
+	return &copied, nil
+}
+
+func (r *fakeWebhookRepo) ListWorkspaceWebhooks(ctx context.Context, workspaceID string) ([]model.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	hooks := []model.Webhook{}
+	for _, hook := range r.hooks {
+		if hook.WorkspaceID == workspaceID {
+			hooks = append(hooks, *hook)
+		}
+	}
+	return hooks, nil
+}
+
+func (r *fakeWebhookRepo) UpdateWebhook(ctx context.Context, id string, update model.WebhookUpdate) (*model.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	hook, ok := r.hooks[id]
+	if !ok {
+		return nil, utils.ErrNotFound
+	}
+	if update.URL != nil {
+		hook.URL = *update.URL
+	}
+	if update.Description != nil {
+		hook.Description = *update.Description
+	}
+	if update.Events != nil {
+		hook.Events = *update.Events
+	}
+	if update.Active != nil {
+		hook.Active = *update.Active
+	}
+	if update.Secret != nil {
+		hook.Secret = *update.Secret
+	}
+	copied := *hook
+	return &copied, nil
+}
+
+func (r *fakeWebhookRepo) DeleteWebhook(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.hooks, id)
+	return nil
+}
+
+func (r *fakeWebhookRepo) CreateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delivery.ID = r.id()
+	stored := *delivery
+	r.deliveries[delivery.ID] = &stored
+	return nil
+}
+
+func (r *fakeWebhookRepo) UpdateDelivery(ctx context.Context, delivery model.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliveries[delivery.ID] = &delivery
+	return nil
+}
+
+func (r *fakeWebhookRepo) ListDeliveries(ctx context.Context, webhookID, status string, limit int) ([]model.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []model.WebhookDelivery
+	for _, d := range r.deliveries {
+		if d.WebhookID == webhookID && (status == "" || d.Status == status) {
+			out = append(out, *d)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeWebhookRepo) ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	return []model.WebhookDelivery{}, nil
+}
+
+func (r *fakeWebhookRepo) DeleteDeliveriesBefore(ctx context.Context, before time.Time) error {
+	return nil
+}
+
+func newTestWebhookService(repo *fakeWebhookRepo, c *memCache) *WebhookServiceImpl {
+	policy := model.DeliveryPolicy{MaxAttempts: 3, BaseBackoff: time.Minute, MaxBackoff: time.Hour}
+	sender := webhook.NewSender(time.Second, netguard.Policy{AllowLoopback: true})
+	return NewWebhookService(repo, c, sender, policy, 1, 0).(*WebhookServiceImpl)
+}
+
+func TestWebhookSecretOnlyOnCreateAndRotate(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeWebhookRepo()
+	svc := newTestWebhookService(repo, newMemCache())
+	owner := model.WorkspaceAccess{WorkspaceID: "ws-1", UserID: "owner", Role: model.RoleOwner}
+	editor := model.WorkspaceAccess{WorkspaceID: "ws-1", UserID: "editor", Role: model.RoleEditor}
+
+	created, err := svc.CreateWebhook(ctx, owner, model.WebhookInput{URL: "https://hooks.example.com/in", Events: []string{model.EventLinkCreated}})
+	if err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+	if !strings.HasPrefix(created.Secret, webhook.SecretPrefix) {
+		t.Fatalf("CreateWebhook secret = %q, want a new secret", created.Secret)
+	}
+
+	hooks, err := svc.ListWebhooks(ctx, editor)
+	if err != nil || len(hooks) != 1 || hooks[0].Secret != "" {
+		t.Fatalf("ListWebhooks = %+v, %v; want one hook without a secret", hooks, err)
+	}
+
+	got, err := svc.GetWebhook(ctx, editor, created.ID)
+	if err != nil || got.Secret != "" {
+		t.Fatalf("GetWebhook = %+v, %v; want no secret", got, err)
+	}
+
+	description := "CRM"
+	updated, err := svc.UpdateWebhook(ctx, owner, created.ID, model.WebhookUpdate{Description: &description})
+	if err != nil || updated.Secret != "" {
+		t.Fatalf("UpdateWebhook = %+v, %v; want no secret", updated, err)
+	}
+
+	if _, err := svc.RotateSecret(ctx, editor, created.ID); err == nil {
+		t.Fatal("editor rotated the secret")
+	}
+	rotated, err := svc.RotateSecret(ctx, owner, created.ID)
+	if err != nil || rotated.Secret == "" || rotated.Secret == created.Secret {
+		t.Fatalf("RotateSecret = %+v, %v; want a new secret", rotated, err)
+	}
+}
+
+func TestWebhookDispatchLoadsSecretOutsideCache(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeWebhookRepo()
+	c := newMemCache()
+	svc := newTestWebhookService(repo, c)
+	owner := model.WorkspaceAccess{WorkspaceID: "ws-1", UserID: "owner", Role: model.RoleOwner}
+
+	var (
+		mu        sync.Mutex
+		signature string
+		timestamp string
+		body      []byte
+	)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		signature = r.Header.Get(webhook.HeaderSignature)
+		timestamp = r.Header.Get(webhook.HeaderTimestamp)
+		body, _ = io.ReadAll(r.Body)
+	}))
+	t.Cleanup(receiver.Close)
+
+	hook, err := svc.CreateWebhook(ctx, owner, model.WebhookInput{URL: receiver.URL, Events: []string{model.EventLinkCreated}})
+	if err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+
+	workspaceID := "ws-1"
+	event := model.WebhookEvent{ID: "evt_1", Type: model.EventLinkCreated, WorkspaceID: workspaceID,
+		Data: model.WebhookEventData{Link: model.NewWebhookLink(&model.URL{ID: "1", ShortCode: "abc1234", WorkspaceID: &workspaceID})}}
+	if err := svc.dispatch(ctx, event); err != nil {
+		t.Fatalf("dispatch: %v", err)
+	}
+
+	cached, ok, _ := c.Get(ctx, "webhooks:"+workspaceID)
+	if !ok || !strings.Contains(cached, hook.ID) {
+		t.Fatalf("webhooks cache = %q, want the webhook target", cached)
+	}
+	if strings.Contains(cached, hook.Secret) || strings.Contains(cached, "secret") {
+		t.Fatalf("webhooks cache leaks the signing secret: %s", cached)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		t.Fatalf("timestamp header = %q", timestamp)
+	}
+	if want := webhook.Sign(hook.Secret, ts, body); signature != want {
+		t.Fatalf("signature = %q, want %q", signature, want)
+	}
+}
@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"url-shortener-go-backend/internal/cache"
+	"url-shortener-go-backend/internal/model"
+	"url-shortener-go-backend/internal/repository"
+	"url-shortener-go-backend/internal/utils"
+)
+
+const (
+	workspaceRoleCacheTTL     = 5 * time.Minute
+	workspaceCacheTTL         = 5 * time.Minute
+	personalWorkspaceCacheTTL = time.Hour
+	workspaceNoRoleMarker     = "-"
+)
+
+type WorkspaceService interface {
+	Resolve(ctx context.Context, userID, workspaceID string) (*model.WorkspaceAccess, error)
+	Authorize(ctx context.Context, userID, workspaceID string, required model.Role) (*model.WorkspaceAccess, error)
+	ListWorkspaces(ctx context.Context, userID string) ([]model.WorkspaceMembership, error)
+	CreateWorkspace(ctx context.Context, userID, name string) (*model.Workspace, error)
+	GetWorkspace(ctx context.Context, access model.WorkspaceAccess) (*model.Workspace, error)
+	UpdateWorkspace(ctx context.Context, access model.WorkspaceAccess, update model.WorkspaceUpdate) (*model.Workspace, error)
+	ListMembers(ctx context.Context, access model.WorkspaceAccess) ([]model.WorkspaceMember, error)
+	AddMember(ctx context.Context, access model.WorkspaceAccess, userID string, role model.Role) (*model.WorkspaceMember, error)
+	UpdateMemberRole(ctx context.Context, access model.WorkspaceAccess, userID string, role model.Role) (*model.WorkspaceMember, error)
+	RemoveMember(ctx context.Context, access model.WorkspaceAccess, userID string) error
+}
+
+type WorkspaceServiceImpl struct {
+	repo  repository.WorkspaceRepository
+	cache cache.Cache
+}
+
+func NewWorkspaceService(repo repository.WorkspaceRepository, c cache.Cache) WorkspaceService {
+	return &WorkspaceServiceImpl{
+		repo:  repo,
+		cache: c,
+	}
+}
+
+func (s *WorkspaceServiceImpl) Resolve(ctx context.Context, userID, workspaceID string) (*model.WorkspaceAccess, error) {
+	workspaceID = strings.TrimSpace(workspaceID)
+	if workspaceID == "" {
+		personal, err := s.personalWorkspaceID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		workspaceID = personal
+	}
+	return s.Authorize(ctx, userID, workspaceID, model.RoleViewer)
+}
+
+func (s *WorkspaceServiceImpl) Authorize(ctx context.Context, userID, workspaceID string, required model.Role) (*model.WorkspaceAccess, error) {
+	if !utils.IsValidUUID(workspaceID) {
+		return nil, ErrInvalidWorkspace
+	}
+
+	role, err := s.memberRole(ctx, workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	access := &model.WorkspaceAccess{WorkspaceID: workspaceID, UserID: userID, Role: role}
+	if !access.Can(required) {
+		return nil, ErrWorkspaceForbidden
+	}
+	return access, nil
+}
+
+func (s *WorkspaceServiceImpl) ListWorkspaces(ctx context.Context, userID string) ([]model.WorkspaceMembership, error) {
+	if _, err := s.personalWorkspaceID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	workspaces, err := s.repo.ListUserWorkspaces(ctx, userID)
+	if err != nil {
+		slog.Error("failed to list workspaces", "user_id", userID, "error", err)
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	return workspaces, nil
+}
+
+func (s *WorkspaceServiceImpl) CreateWorkspace(ctx context.Context, userID, name string) (*model.Workspace, error) {
+	workspace := &model.Workspace{
+		Name:      name,
+		CreatedBy: userID,
+	}
+	if err := s.repo.CreateWorkspace(ctx, workspace); err != nil {
+		return nil, err
+	}
+
+	slog.Info("workspace created", "workspace_id", workspace.ID, "user_id", userID)
+	return workspace, nil
+}
+
+func (s *WorkspaceServiceImpl) GetWorkspace(ctx context.Context, access model.WorkspaceAccess) (*model.Workspace, error) {
+	if !access.Can(model.RoleViewer) {
+		return nil, ErrWorkspaceForbidden
+	}
+
+	cacheKey := "workspace:" + access.WorkspaceID
+	if val, ok, err := s.cache.Get(ctx, cacheKey); err == nil && ok {
+		var workspace model.Workspace
+		if err := json.Unmarshal([]byte(val), &workspace); err == nil {
+			return &workspace, nil
+		}
+	}
+
+	workspace, err := s.repo.GetWorkspace(ctx, access.WorkspaceID)
+	if err != nil {
+		slog.Error("workspace lookup failed", "workspace_id", access.WorkspaceID, "error", err)
+		return nil, err
+	}
+
+	if jsonVal, err := json.Marshal(workspace); err == nil {
+		_ = s.cache.Set(ctx, cacheKey, string(jsonVal), workspaceCacheTTL)
+	}
+	return workspace, nil
+}
+
+func (s *WorkspaceServiceImpl) UpdateWorkspace(ctx context.Context, access model.WorkspaceAccess, update model.WorkspaceUpdate) (*model.Workspace, error) {
+	if !access.Can(model.RoleOwner) {
+		return nil, ErrWorkspaceForbidden
+	}
+	if update.IsEmpty() {
+		return nil, ErrEmptyUpdate
+	}
+
+	workspace, err := s.repo.UpdateWorkspace(ctx, access.WorkspaceID, update)
+	if err != nil {
+		return nil, err
+	}
+
+	key := "workspace:" + access.WorkspaceID
+	if err := s.cache.Delete(ctx, key); err != nil {
+		slog.Warn("failed to delete cache key", "key", key, "error", err)
+	}
+
+	slog.Info("workspace updated", "workspace_id", access.WorkspaceID, "by", access.UserID)
+	return workspace, nil
+}
+
+func (s *WorkspaceServiceImpl) ListMembers(ctx context.Context, access model.WorkspaceAccess) ([]model.WorkspaceMember, error) {
+	if !access.Can(model.RoleViewer) {
+		return nil, ErrWorkspaceForbidden
+	}
+
+	members, err := s.repo.ListMembers(ctx, access.WorkspaceID)
+	if err != nil {
+		slog.Error("failed to list workspace members", "workspace_id", access.WorkspaceID, "error", err)
+		return nil, fmt.Errorf("failed to list workspace members: %w", err)
+	}
+	return members, nil
+}
+
+func (s *WorkspaceServiceImpl) AddMember(ctx context.Context, access model.WorkspaceAccess, userID string, role model.Role) (*model.WorkspaceMember, error) {
+	if !access.Can(model.RoleOwner) {
+		return nil, ErrWorkspaceForbidden
+	}
+
+	member := &model.WorkspaceMember{
+		WorkspaceID: access.WorkspaceID,
+		UserID:      userID,
+		Role:        role,
+	}
+	if err := s.repo.AddMember(ctx, member); err != nil {
+		if errors.Is(err, repository.ErrUniqueViolation) {
+			return nil, ErrWorkspaceMemberExists
+		}
+		return nil, err
+	}
+	s.purgeRoleCache(ctx, access.WorkspaceID, userID)
+
+	slog.Info("workspace member added", "workspace_id", access.WorkspaceID, "member_id", userID, "role", role, "by", access.UserID)
+	return member, nil
+}
+
+func (s *WorkspaceServiceImpl) UpdateMemberRole(ctx context.Context, access model.WorkspaceAccess, userID string, role model.Role) (*model.WorkspaceMember, error) {
+	if !access.Can(model.RoleOwner) {
+		return nil, ErrWorkspaceForbidden
+	}
+
+	if role != model.RoleOwner {
+		if err := s.ensureAnotherOwner(ctx, access.WorkspaceID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	member, err := s.repo.UpdateMemberRole(ctx, access.WorkspaceID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+	s.purgeRoleCache(ctx, access.WorkspaceID, userID)
+
+	slog.Info("workspace member updated", "workspace_id", access.WorkspaceID, "member_id", userID, "role", role, "by", access.UserID)
+	return member, nil
+}
+
+func (s *WorkspaceServiceImpl) RemoveMember(ctx context.Context, access model.WorkspaceAccess, userID string) error {
+	if userID != access.UserID && !access.Can(model.RoleOwner) {
+		return ErrWorkspaceForbidden
+	}
+
+	if err := s.ensureAnotherOwner(ctx, access.WorkspaceID, userID); err != nil {
+		return err
+	}
+
+	if err := s.repo.RemoveMember(ctx, access.WorkspaceID, userID); err != nil {
+		return err
+	}
+	s.purgeRoleCache(ctx, access.WorkspaceID, userID)
+
+	slog.Info("workspace member removed", "workspace_id", access.WorkspaceID, "member_id", userID, "by", access.UserID)
+	return nil
+}
+
+func (s *WorkspaceServiceImpl) ensureAnotherOwner(ctx context.Context, workspaceID, userID string) error {
+	members, err := s.repo.ListMembers(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, m := range members {
+		if m.UserID == userID {
+			found = true
+			if m.Role != model.RoleOwner {
+				return nil
+			}
+		}
+	}
+	if !found {
+		return utils.ErrNotFound
+	}
+
+	for _, m := range members {
+		if m.Role == model.RoleOwner && m.UserID != userID {
+			return nil
+		}
+	}
+	return ErrLastWorkspaceOwner
+}
+
+func (s *WorkspaceServiceImpl) memberRole(ctx context.Context, workspaceID, userID string) (model.Role, error) {
+	cacheKey := fmt.Sprintf("workspace_role:%s:%s", workspaceID, userID)
+	if val, ok, err := s.cache.Get(ctx, cacheKey); err == nil && ok {
+		if val == workspaceNoRoleMarker {
+			return "", ErrNotWorkspaceMember
+		}
+		return model.Role(val), nil
+	}
+
+	member, err := s.repo.GetMember(ctx, workspaceID, userID)
+	if errors.Is(err, utils.ErrNotFound) {
+		_ = s.cache.Set(ctx, cacheKey, workspaceNoRoleMarker, workspaceRoleCacheTTL)
+		return "", ErrNotWorkspaceMember
+	}
+	if err != nil {
+		slog.Error("workspace member lookup failed", "workspace_id", workspaceID, "error", err)
+		return "", err
+	}
+
+	_ = s.cache.Set(ctx, cacheKey, string(member.Role), workspaceRoleCacheTTL)
+	return member.Role, nil
+}
+
+func (s *WorkspaceServiceImpl) personalWorkspaceID(ctx context.Context, userID string) (string, error) {
+	cacheKey := "personal_workspace:" + userID
+	if val, ok, err := s.cache.Get(ctx, cacheKey); err == nil && ok {
+		return val, nil
+	}
+
+	workspace, err := s.repo.GetPersonalWorkspace(ctx, userID)
+	if errors.Is(err, utils.ErrNotFound) {
+		workspace = &model.Workspace{
+			Name:          model.PersonalWorkspaceName,
+			PersonalOwner: &userID,
+			CreatedBy:     userID,
+		}
+		err = s.repo.CreateWorkspace(ctx, workspace)
+		if errors.Is(err, repository.ErrUniqueViolation) {
+			workspace, err = s.repo.GetPersonalWorkspace(ctx, userID)
+		} else if err == nil {
+			slog.Info("personal workspace created", "workspace_id", workspace.ID, "user_id", userID)
+		}
+	}
+	if err != nil {
+		slog.Error("personal workspace lookup failed", "user_id", userID, "error", err)
+		return "", err
+	}
+
+	_ = s.cache.Set(ctx, cacheKey, workspace.ID, personalWorkspaceCacheTTL)
+	return workspace.ID, nil
+}
+
+func (s *WorkspaceServiceImpl) purgeRoleCache(ctx context.Context, workspaceID, userID string) {
+	key := fmt.Sprintf("workspace_role:%s:%s", workspaceID, userID)
+	if err := s.cache.Delete(ctx, key); err != nil {
+		slog.Warn("failed to delete cache key", "key", key, "error", err)
+	}
+}
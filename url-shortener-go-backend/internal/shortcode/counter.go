@@ -0,0 +1,94 @@
+package shortcode
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+
+	"url-shortener-go-backend/internal/cache"
+)
+
+const (
+	counterKey       = "shortcode:counter"
+	MaxCounterLength = 10
+	feistelRounds    = 4
+)
+
+type CounterGenerator struct {
+	cache cache.Cache
+	keys  [feistelRounds]uint64
+}
+
+func NewCounterGenerator(c cache.Cache, salt string) *CounterGenerator {
+	g := &CounterGenerator{cache: c}
+	for i := range g.keys {
+		sum := sha256.Sum256([]byte(fmt.Sprintf("%s:feistel:%d", salt, i)))
+		g.keys[i] = binary.BigEndian.Uint64(sum[:8])
+	}
+	return g
+}
+
+func (g *CounterGenerator) Name() string {
+	return GeneratorCounter
+}
+
+func (g *CounterGenerator) Generate(ctx context.Context, req Request) (string, error) {
+	n, err := g.cache.Incr(ctx, counterKey)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	if n < 1 {
+		return "", fmt.Errorf("%w: counter returned %d", ErrUnavailable, n)
+	}
+
+	width := min(req.EffectiveLength(), MaxCounterLength)
+	value := uint64(n - 1)
+	for value >= pow62(width) {
+		if width == MaxCounterLength {
+			return "", ErrExhausted
+		}
+		width++
+	}
+
+	return encodeBase62(g.permute(value, pow62(width)), width), nil
+}
+
+func (g *CounterGenerator) permute(value, domain uint64) uint64 {
+	width := bits.Len64(domain - 1)
+	if width%2 == 1 {
+		width++
+	}
+	half := max(width/2, 1)
+
+	value = g.encrypt(value, half)
+	for value >= domain {
+		value = g.encrypt(value, half)
+	}
+	return value
+}
+
+func (g *CounterGenerator) encrypt(value uint64, half int) uint64 {
+	mask := uint64(1)<<half - 1
+	left, right := value>>half&mask, value&mask
+	for _, key := range g.keys {
+		left, right = right, left^(round(right, key)&mask)
+	}
+	return left<<half | right
+}
+
+func round(value, key uint64) uint64 {
+	h := (value ^ key) * 0x9e3779b97f4a7c15
+	h ^= h >> 29
+	h *= 0xbf58476d1ce4e5b9
+	return h ^ h>>32
+}
+
+func pow62(n int) uint64 {
+	p := uint64(1)
+	for i := 0; i < n; i++ {
+		p *= 62
+	}
+	return p
+}
@@ -0,0 +1,177 @@
+package shortcode
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type memCache struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	values  map[string]int64
+	expires map[string]time.Time
+	fail    error
+}
+
+func newMemCache(now func() time.Time) *memCache {
+	return &memCache{now: now, values: map[string]int64{}, expires: map[string]time.Time{}}
+}
+
+func (c *memCache) expire(key string) {
+	if at, ok := c.expires[key]; ok && !c.now().Before(at) {
+		delete(c.values, key)
+		delete(c.expires, key)
+	}
+}
+
+func (c *memCache) Get(ctx context.Context, key string) (string, bool, error) {
+	return "", false, nil
+}
+
+func (c *memCache) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	return nil
+}
+
+func (c *memCache) Incr(ctx context.Context, key string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.fail != nil {
+		return 0, c.fail
+	}
+	c.expire(key)
+	c.values[key]++
+	return c.values[key], nil
+}
+
+func (c *memCache) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.expire(key)
+	if _, ok := c.values[key]; ok {
+		c.expires[key] = c.now().Add(ttl)
+	}
+	return nil
+}
+
+func (c *memCache) TTL(ctx context.Context, key string) (time.Duration, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.expire(key)
+	at, ok := c.expires[key]
+	if !ok {
+		return -1, nil
+	}
+	return at.Sub(c.now()), nil
+}
+
+func (c *memCache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.values, key)
+	delete(c.expires, key)
+	return nil
+}
+
+func (c *memCache) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (c *memCache) Close() error {
+	return nil
+}
+
+func TestCounterPermuteIsBijection(t *testing.T) {
+	g := NewCounterGenerator(newMemCache(time.Now), "test-salt")
+
+	for _, domain := range []uint64{2, 62, 1000, pow62(2), pow62(3)} {
+		seen := make([]bool, domain)
+		for v := uint64(0); v < domain; v++ {
+			p := g.permute(v, domain)
+			if p >= domain {
+				t.Fatalf("permute(%d, %d) = %d, outside the domain", v, domain, p)
+			}
+			if seen[p] {
+				t.Fatalf("permute(_, %d) maps two values to %d", domain, p)
+			}
+			seen[p] = true
+		}
+	}
+}
+
+func TestCounterPermuteDependsOnSalt(t *testing.T) {
+	a := NewCounterGenerator(newMemCache(time.Now), "salt-a")
+	b := NewCounterGenerator(newMemCache(time.Now), "salt-b")
+	domain := pow62(6)
+
+	same := 0
+	for v := uint64(0); v < 100; v++ {
+		if a.permute(v, domain) == b.permute(v, domain) {
+			same++
+		}
+		if a.permute(v, domain) != a.permute(v, domain) {
+			t.Fatalf("permute(%d) is not deterministic", v)
+		}
+	}
+	if same > 5 {
+		t.Fatalf("%d of 100 values permute the same under different salts", same)
+	}
+}
+
+func TestCounterWidthGrows(t *testing.T) {
+	tests := []struct {
+		name      string
+		length    int
+		counter   int64
+		wantWidth int
+		wantErr   error
+	}{
+		{name: "first code", length: 6, counter: 0, wantWidth: 6},
+		{name: "last six character code", length: 6, counter: int64(pow62(6)) - 1, wantWidth: 6},
+		{name: "grows to seven", length: 6, counter: int64(pow62(6)), wantWidth: 7},
+		{name: "grows past the request", length: 6, counter: int64(pow62(8)), wantWidth: 9},
+		{name: "longer request", length: 8, counter: 0, wantWidth: 8},
+		{name: "capped request", length: 12, counter: 0, wantWidth: MaxCounterLength},
+		{name: "exhausted", length: 6, counter: int64(pow62(MaxCounterLength)), wantErr: ErrExhausted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newMemCache(time.Now)
+			c.values[counterKey] = tt.counter
+			code, err := NewCounterGenerator(c, "test-salt").Generate(context.Background(), Request{Length: tt.length})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Generate = %q, %v; want error %v", code, err, tt.wantErr)
+			}
+			if tt.wantErr == nil && len(code) != tt.wantWidth {
+				t.Fatalf("Generate = %q (%d characters), want %d", code, len(code), tt.wantWidth)
+			}
+		})
+	}
+}
+
+func TestCounterCodesAreUnique(t *testing.T) {
+	g := NewCounterGenerator(newMemCache(time.Now), "test-salt")
+	seen := make(map[string]bool)
+	for i := 0; i < 2000; i++ {
+		code, err := g.Generate(context.Background(), Request{Length: MinLength})
+		if err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+		if seen[code] || strings.Trim(code, base62Alphabet) != "" {
+			t.Fatalf("Generate returned %q twice or with a non-base62 character", code)
+		}
+		seen[code] = true
+	}
+}
+
+func TestCounterUnavailable(t *testing.T) {
+	c := newMemCache(time.Now)
+	c.fail = errors.New("connection refused")
+	if _, err := NewCounterGenerator(c, "test-salt").Generate(context.Background(), Request{}); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("Generate with Redis down = %v, want ErrUnavailable", err)
+	}
+}
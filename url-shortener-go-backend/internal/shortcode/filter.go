@@ -0,0 +1,80 @@
+package shortcode
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrReservedCode  = errors.New("code is reserved")
+	ErrOffensiveCode = errors.New("code contains a blocked word")
+)
+
+var defaultReserved = []string{
+	"about", "account", "admin", "analytics", "api", "app", "assets", "auth",
+	"billing", "broken", "callback", "continue", "dashboard", "docs", "domains", "favicon",
+	"health", "help", "login", "logout", "metrics", "preview", "privacy", "qrcode",
+	"register", "reports", "settings", "signin", "signup", "static", "status",
+	"support", "terms", "well-known", "workspaces", "www",
+}
+
+var blockedWords = []string{
+	"asshole", "bastard", "bitch", "bollock", "butthole", "chink", "clit", "cunt",
+	"dildo", "douche", "faggot", "fuck", "jizz", "milf", "motherf", "nazi",
+	"nigga", "nigger", "penis", "porn", "pussy", "retard", "scrotum", "shit",
+	"slut", "twat", "vagina", "whore",
+}
+
+var tokenWords = map[string]bool{
+	"anal": true, "anus": true, "arse": true, "ass": true, "boner": true, "cock": true,
+	"coon": true, "crap": true, "cum": true, "dick": true, "dyke": true, "fag": true,
+	"fap": true, "gook": true, "homo": true, "kike": true, "kkk": true, "piss": true,
+	"prick": true, "rape": true, "semen": true, "sex": true, "spic": true, "tit": true,
+	"tits": true, "wank": true, "wtf": true,
+}
+
+var leet = strings.NewReplacer("0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "8", "b", "$", "s", "@", "a")
+
+type Filter struct {
+	reserved map[string]bool
+}
+
+func NewFilter(extraReserved []string) *Filter {
+	f := &Filter{reserved: make(map[string]bool, len(defaultReserved)+len(extraReserved))}
+	for _, word := range append(append([]string{}, defaultReserved...), extraReserved...) {
+		f.reserved[strings.ToLower(strings.TrimSpace(word))] = true
+	}
+	return f
+}
+
+func (f *Filter) Check(code string) error {
+	lower := strings.ToLower(code)
+	if f.reserved[lower] {
+		return ErrReservedCode
+	}
+
+	for _, token := range strings.FieldsFunc(lower, isSeparator) {
+		if tokenWords[token] || tokenWords[leet.Replace(token)] {
+			return ErrOffensiveCode
+		}
+	}
+
+	squashed := strings.Map(func(r rune) rune {
+		if isSeparator(r) {
+			return -1
+		}
+		return r
+	}, lower)
+	for _, candidate := range []string{squashed, leet.Replace(squashed)} {
+		for _, word := range blockedWords {
+			if strings.Contains(candidate, word) {
+				return ErrOffensiveCode
+			}
+		}
+	}
+	return nil
+}
+
+func isSeparator(r rune) bool {
+	return r == '-' || r == '_'
+}
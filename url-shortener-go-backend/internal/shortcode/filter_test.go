@@ -0,0 +1,151 @@
+package shortcode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestFilterCheck(t *testing.T) {
+	f := NewFilter([]string{" Promo ", ""})
+
+	tests := []struct {
+		code string
+		want error
+	}{
+		{code: "admin", want: ErrReservedCode},
+		{code: "ADMIN", want: ErrReservedCode},
+		{code: "well-known", want: ErrReservedCode},
+		{code: "workspaces", want: ErrReservedCode},
+		{code: "promo", want: ErrReservedCode},
+		{code: "fuckit", want: ErrOffensiveCode},
+		{code: "xxFUCKxx", want: ErrOffensiveCode},
+		{code: "5h1t", want: ErrOffensiveCode},
+		{code: "bu11sh1t", want: ErrOffensiveCode},
+		{code: "f-u-c-k", want: ErrOffensiveCode},
+		{code: "s_h_i_t_99", want: ErrOffensiveCode},
+		{code: "ass-hat", want: ErrOffensiveCode},
+		{code: "big_a55", want: ErrOffensiveCode},
+		{code: "wtf-now", want: ErrOffensiveCode},
+		{code: "administrator"},
+		{code: "admin-panel"},
+		{code: "classic"},
+		{code: "passage"},
+		{code: "grapefruit"},
+		{code: "cockpit"},
+		{code: "analysis"},
+		{code: "sextant"},
+		{code: "hello-world"},
+		{code: "aB3x9Qz"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			if err := f.Check(tt.code); !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+				t.Fatalf("Check(%q) = %v, want %v", tt.code, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadableGeneratorAlphabet(t *testing.T) {
+	g := NewReadableGenerator()
+	for i := 0; i < 2000; i++ {
+		length := MinLength + i%(MaxLength-MinLength+1)
+		code, err := g.Generate(context.Background(), Request{Length: length})
+		if err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+		if len(code) != length {
+			t.Fatalf("Generate(length %d) = %q", length, code)
+		}
+		if strings.ContainsAny(code, "0O1lIio") {
+			t.Fatalf("Generate = %q, contains an ambiguous character", code)
+		}
+		if strings.Trim(code, readableAlphabet) != "" {
+			t.Fatalf("Generate = %q, outside the readable alphabet", code)
+		}
+	}
+}
+
+func TestWordGenerator(t *testing.T) {
+	pattern := regexp.MustCompile(`^[a-z]+-[a-z]+-[1-9][0-9]$`)
+	g := NewWordGenerator()
+	for i := 0; i < 500; i++ {
+		code, err := g.Generate(context.Background(), Request{})
+		if err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+		if !pattern.MatchString(code) {
+			t.Fatalf("Generate = %q, want adjective-noun-NN", code)
+		}
+	}
+}
+
+func TestWordListsPassFilter(t *testing.T) {
+	f := NewFilter(nil)
+	for _, a := range adjectives {
+		for _, n := range nouns {
+			if code := fmt.Sprintf("%s-%s-10", a, n); f.Check(code) != nil {
+				t.Fatalf("word code %q is rejected by the filter", code)
+			}
+		}
+	}
+}
+
+type sequenceGenerator struct {
+	codes []string
+	calls int
+}
+
+func (g *sequenceGenerator) Name() string {
+	return "sequence"
+}
+
+func (g *sequenceGenerator) Generate(ctx context.Context, req Request) (string, error) {
+	code := g.codes[min(g.calls, len(g.codes)-1)]
+	g.calls++
+	return code, nil
+}
+
+func TestMuxFiltersGeneratedCodes(t *testing.T) {
+	base := &sequenceGenerator{codes: []string{"admin", "xfuckx1", "aB3x9Qz"}}
+	code, err := NewMux(base, NewFilter(nil)).Generate(context.Background(), Request{})
+	if err != nil || code != "aB3x9Qz" || base.calls != 3 {
+		t.Fatalf("Generate = %q, %v after %d calls; want the first clean code", code, err, base.calls)
+	}
+
+	blocked := &sequenceGenerator{codes: []string{"5h1t5h1t"}}
+	if _, err := NewMux(blocked, NewFilter(nil)).Generate(context.Background(), Request{}); !errors.Is(err, ErrExhausted) {
+		t.Fatalf("Generate with only blocked codes = %v, want ErrExhausted", err)
+	}
+
+	readable, err := NewMux(base, NewFilter(nil)).Generate(context.Background(), Request{Style: StyleReadable})
+	if err != nil || strings.Trim(readable, readableAlphabet) != "" {
+		t.Fatalf("readable style = %q, %v", readable, err)
+	}
+}
+
+func TestMuxValidateAlias(t *testing.T) {
+	m := NewMux(&sequenceGenerator{codes: []string{"unused"}}, NewFilter(nil))
+
+	tests := []struct {
+		alias string
+		want  error
+	}{
+		{alias: "launch-2026"},
+		{alias: "admin", want: ErrInvalidAlias},
+		{alias: "well-known", want: ErrReservedCode},
+		{alias: "dashboard", want: ErrReservedCode},
+		{alias: "f-u-c-k-it", want: ErrOffensiveCode},
+		{alias: "has space", want: ErrInvalidAlias},
+	}
+	for _, tt := range tests {
+		if err := m.ValidateAlias(tt.alias); !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+			t.Fatalf("ValidateAlias(%q) = %v, want %v", tt.alias, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package shortcode
+
+import (
+	"context"
+	"fmt"
+
+	"url-shortener-go-backend/internal/utils"
+)
+
+const (
+	StyleDefault  = "default"
+	StyleReadable = "readable"
+	StyleWords    = "words"
+
+	maxFilteredAttempts = 10
+)
+
+func ValidateStyle(style string) error {
+	switch style {
+	case "", StyleDefault, StyleReadable, StyleWords:
+		return nil
+	default:
+		return fmt.Errorf("code_style must be %q, %q or %q", StyleDefault, StyleReadable, StyleWords)
+	}
+}
+
+type Mux struct {
+	base   CodeGenerator
+	styles map[string]CodeGenerator
+	filter *Filter
+}
+
+func NewMux(base CodeGenerator, filter *Filter) *Mux {
+	return &Mux{
+		base: base,
+		styles: map[string]CodeGenerator{
+			StyleReadable: NewReadableGenerator(),
+			StyleWords:    NewWordGenerator(),
+		},
+		filter: filter,
+	}
+}
+
+func (m *Mux) Name() string {
+	return m.base.Name()
+}
+
+func (m *Mux) NameFor(style string) string {
+	return m.generator(style).Name()
+}
+
+func (m *Mux) Generate(ctx context.Context, req Request) (string, error) {
+	gen := m.generator(req.Style)
+
+	for i := 0; i < maxFilteredAttempts; i++ {
+		code, err := gen.Generate(ctx, req)
+		if err != nil {
+			return "", err
+		}
+		if m.filter.Check(code) == nil {
+			return code, nil
+		}
+	}
+	return "", ErrExhausted
+}
+
+func (m *Mux) ValidateAlias(alias string) error {
+	if !utils.IsValidShortCode(alias) {
+		return ErrInvalidAlias
+	}
+	return m.filter.Check(alias)
+}
+
+func (m *Mux) generator(style string) CodeGenerator {
+	if gen, ok := m.styles[style]; ok {
+		return gen
+	}
+	return m.base
+}
@@ -0,0 +1,23 @@
+package shortcode
+
+import (
+	"context"
+
+	"url-shortener-go-backend/internal/utils"
+)
+
+type RandomGenerator struct {
+	salt string
+}
+
+func NewRandomGenerator(salt string) *RandomGenerator {
+	return &RandomGenerator{salt: salt}
+}
+
+func (g *RandomGenerator) Name() string {
+	return GeneratorRandom
+}
+
+func (g *RandomGenerator) Generate(ctx context.Context, req Request) (string, error) {
+	return utils.GenerateCode(req.URL, req.EffectiveLength(), g.salt)
+}
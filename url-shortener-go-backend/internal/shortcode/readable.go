@@ -0,0 +1,40 @@
+package shortcode
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+)
+
+const readableAlphabet = "23456789abcdefghjkmnpqrstuvwxyz"
+
+type ReadableGenerator struct{}
+
+func NewReadableGenerator() *ReadableGenerator {
+	return &ReadableGenerator{}
+}
+
+func (g *ReadableGenerator) Name() string {
+	return StyleReadable
+}
+
+func (g *ReadableGenerator) Generate(ctx context.Context, req Request) (string, error) {
+	code := make([]byte, req.EffectiveLength())
+	for i := range code {
+		c, err := randomIndex(len(readableAlphabet))
+		if err != nil {
+			return "", err
+		}
+		code[i] = readableAlphabet[c]
+	}
+	return string(code), nil
+}
+
+func randomIndex(n int) (int, error) {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to generate random bytes: %w", err)
+	}
+	return int(v.Int64()), nil
+}
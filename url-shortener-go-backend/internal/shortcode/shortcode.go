@@ -0,0 +1,86 @@
+package shortcode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+const (
+	GeneratorRandom    = "random"
+	GeneratorCounter   = "counter"
+	GeneratorSnowflake = "snowflake"
+
+	MinLength      = 6
+	MaxLength      = 12
+	DefaultLength  = 7
+	MaxAliasLength = 32
+
+	base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+var (
+	ErrInvalidLength = fmt.Errorf("code length must be between %d and %d", MinLength, MaxLength)
+	ErrUnavailable   = errors.New("short code generator unavailable")
+	ErrExhausted     = errors.New("no free short code after retries")
+	ErrInvalidAlias  = fmt.Errorf("alias must be %d to %d letters, digits, '-' or '_'", MinLength, MaxAliasLength)
+)
+
+type Request struct {
+	URL    string
+	Length int
+	Style  string
+}
+
+func (r Request) EffectiveLength() int {
+	if r.Length == 0 {
+		return DefaultLength
+	}
+	return r.Length
+}
+
+func (r Request) Validate() error {
+	if l := r.EffectiveLength(); l < MinLength || l > MaxLength {
+		return ErrInvalidLength
+	}
+	return nil
+}
+
+type CodeGenerator interface {
+	Name() string
+	Generate(ctx context.Context, req Request) (string, error)
+}
+
+type CollisionPolicy struct {
+	MaxAttempts int
+	GrowLength  bool
+}
+
+func (p CollisionPolicy) Attempts() int {
+	if p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+func (p CollisionPolicy) Next(req Request) Request {
+	if p.GrowLength && req.EffectiveLength() < MaxLength {
+		req.Length = req.EffectiveLength() + 1
+	}
+	return req
+}
+
+func encodeBase62(n uint64, width int) string {
+	var buf [16]byte
+	i := len(buf)
+	for n > 0 || i == len(buf) {
+		i--
+		buf[i] = base62Alphabet[n%62]
+		n /= 62
+	}
+	for len(buf)-i < width {
+		i--
+		buf[i] = base62Alphabet[0]
+	}
+	return string(buf[i:])
+}
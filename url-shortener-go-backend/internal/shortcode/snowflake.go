@@ -0,0 +1,119 @@
+package shortcode
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"url-shortener-go-backend/internal/cache"
+)
+
+const (
+	snowflakeNodeKey      = "shortcode:snowflake:node"
+	snowflakeLeaseKey     = "shortcode:snowflake:lease:"
+	snowflakeLeaseTTL     = 10 * time.Minute
+	snowflakeRenewAfter   = snowflakeLeaseTTL / 2
+	snowflakeReclaimAfter = snowflakeLeaseTTL * 9 / 10
+	snowflakeNodeBits     = 10
+	snowflakeSeqBits      = 12
+	MaxSnowflakeNode      = 1<<snowflakeNodeBits - 1
+	snowflakeSeqMask      = 1<<snowflakeSeqBits - 1
+	SnowflakeLength       = 11
+)
+
+var snowflakeEpoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+type SnowflakeGenerator struct {
+	cache     cache.Cache
+	mu        sync.Mutex
+	node      int64
+	leased    bool
+	renewedAt time.Time
+	last      int64
+	seq       int64
+	now       func() time.Time
+}
+
+func NewSnowflakeGenerator(c cache.Cache, node int) *SnowflakeGenerator {
+	return &SnowflakeGenerator{cache: c, node: int64(node), leased: node < 0, now: time.Now}
+}
+
+func (g *SnowflakeGenerator) Name() string {
+	return GeneratorSnowflake
+}
+
+func (g *SnowflakeGenerator) Generate(ctx context.Context, req Request) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.leased {
+		if err := g.holdLease(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	now := max(g.now().Sub(snowflakeEpoch).Milliseconds(), g.last)
+	if now == g.last {
+		g.seq = (g.seq + 1) & snowflakeSeqMask
+		if g.seq == 0 {
+			for now <= g.last {
+				time.Sleep(time.Millisecond)
+				now = g.now().Sub(snowflakeEpoch).Milliseconds()
+			}
+		}
+	} else {
+		g.seq = 0
+	}
+	g.last = now
+
+	id := now<<(snowflakeNodeBits+snowflakeSeqBits) | g.node<<snowflakeSeqBits | g.seq
+	return encodeBase62(uint64(id), SnowflakeLength), nil
+}
+
+func (g *SnowflakeGenerator) holdLease(ctx context.Context) error {
+	since := g.now().Sub(g.renewedAt)
+	switch {
+	case g.node >= 0 && since < snowflakeRenewAfter:
+		return nil
+	case g.node >= 0 && since < snowflakeReclaimAfter:
+		if err := g.cache.Expire(ctx, leaseKey(g.node), snowflakeLeaseTTL); err != nil {
+			return fmt.Errorf("%w: %v", ErrUnavailable, err)
+		}
+		g.renewedAt = g.now()
+		return nil
+	}
+
+	if g.node >= 0 {
+		slog.Warn("snowflake node lease lapsed, claiming a new node", "node", g.node)
+		g.node = -1
+	}
+
+	start, err := g.cache.Incr(ctx, snowflakeNodeKey)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	for i := int64(0); i <= MaxSnowflakeNode; i++ {
+		node := (start - 1 + i) % (MaxSnowflakeNode + 1)
+		n, err := g.cache.Incr(ctx, leaseKey(node))
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrUnavailable, err)
+		}
+		if n != 1 {
+			continue
+		}
+		if err := g.cache.Expire(ctx, leaseKey(node), snowflakeLeaseTTL); err != nil {
+			_ = g.cache.Delete(ctx, leaseKey(node))
+			return fmt.Errorf("%w: %v", ErrUnavailable, err)
+		}
+		g.node, g.renewedAt = node, g.now()
+		slog.Info("snowflake node leased", "node", node, "ttl", snowflakeLeaseTTL)
+		return nil
+	}
+	return fmt.Errorf("%w: all %d snowflake node IDs are leased", ErrUnavailable, MaxSnowflakeNode+1)
+}
+
+func leaseKey(node int64) string {
+	return fmt.Sprintf("%s%d", snowflakeLeaseKey, node)
+}
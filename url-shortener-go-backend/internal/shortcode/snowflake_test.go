@@ -0,0 +1,138 @@
+package shortcode
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.t
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.t = c.t.Add(d)
+}
+
+func snowflakeNode(t *testing.T, code string) int64 {
+	t.Helper()
+	var id uint64
+	for _, r := range code {
+		i := strings.IndexRune(base62Alphabet, r)
+		if i < 0 {
+			t.Fatalf("code %q is not base62", code)
+		}
+		id = id*62 + uint64(i)
+	}
+	return int64(id>>snowflakeSeqBits) & MaxSnowflakeNode
+}
+
+func TestSnowflakeFixedWidth(t *testing.T) {
+	g := NewSnowflakeGenerator(nil, 7)
+
+	for _, length := range []int{0, MinLength, DefaultLength, 10, MaxLength} {
+		code, err := g.Generate(context.Background(), Request{Length: length})
+		if err != nil {
+			t.Fatalf("Generate(length %d): %v", length, err)
+		}
+		if len(code) != SnowflakeLength {
+			t.Fatalf("Generate(length %d) = %q, want %d characters", length, code, SnowflakeLength)
+		}
+		if node := snowflakeNode(t, code); node != 7 {
+			t.Fatalf("Generate(length %d) = %q with node %d, want 7", length, code, node)
+		}
+	}
+
+	if got := encodeBase62(^uint64(0), SnowflakeLength); len(got) != SnowflakeLength {
+		t.Fatalf("largest 64-bit ID encodes to %q, wider than %d", got, SnowflakeLength)
+	}
+}
+
+func TestSnowflakeCodesIncrease(t *testing.T) {
+	g := NewSnowflakeGenerator(nil, 1)
+	prev := ""
+	for i := 0; i < 10000; i++ {
+		code, err := g.Generate(context.Background(), Request{})
+		if err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+		if code <= prev {
+			t.Fatalf("code %d = %q, not after %q", i, code, prev)
+		}
+		prev = code
+	}
+}
+
+func TestSnowflakeNodeLeases(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeClock{t: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	c := newMemCache(clock.Now)
+
+	newGenerator := func() *SnowflakeGenerator {
+		g := NewSnowflakeGenerator(c, -1)
+		g.now = clock.Now
+		return g
+	}
+	generate := func(g *SnowflakeGenerator) int64 {
+		t.Helper()
+		clock.Advance(time.Millisecond)
+		code, err := g.Generate(ctx, Request{})
+		if err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+		return snowflakeNode(t, code)
+	}
+
+	a, b := newGenerator(), newGenerator()
+	nodeA, nodeB := generate(a), generate(b)
+	if nodeA == nodeB {
+		t.Fatalf("two instances leased node %d", nodeA)
+	}
+
+	clock.Advance(snowflakeRenewAfter)
+	if node := generate(a); node != nodeA {
+		t.Fatalf("renewed instance moved from node %d to %d", nodeA, node)
+	}
+	if ttl, _ := c.TTL(ctx, leaseKey(nodeA)); ttl < snowflakeLeaseTTL-time.Second {
+		t.Fatalf("lease TTL after renewal = %s, want about %s", ttl, snowflakeLeaseTTL)
+	}
+
+	clock.Advance(snowflakeLeaseTTL - snowflakeRenewAfter + time.Second)
+	late := newGenerator()
+	if node := generate(late); node == nodeA {
+		t.Fatalf("new instance took node %d while its lease was live", nodeA)
+	}
+
+	clock.Advance(snowflakeLeaseTTL)
+	node := generate(b)
+	if ttl, _ := c.TTL(ctx, leaseKey(node)); ttl < snowflakeLeaseTTL-time.Second {
+		t.Fatalf("idle instance uses node %d with lease TTL %s, want a fresh lease", node, ttl)
+	}
+}
+
+func TestSnowflakeLeasesDoNotWrap(t *testing.T) {
+	ctx := context.Background()
+	clock := &fakeClock{t: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	c := newMemCache(clock.Now)
+	for node := int64(0); node <= MaxSnowflakeNode; node++ {
+		c.values[leaseKey(node)] = 1
+		c.expires[leaseKey(node)] = clock.Now().Add(snowflakeLeaseTTL)
+	}
+
+	g := NewSnowflakeGenerator(c, -1)
+	g.now = clock.Now
+	if _, err := g.Generate(ctx, Request{}); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("Generate with every node leased = %v, want ErrUnavailable", err)
+	}
+
+	clock.Advance(snowflakeLeaseTTL)
+	if _, err := g.Generate(ctx, Request{}); err != nil {
+		t.Fatalf("Generate after leases expired: %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package shortcode
+
+import (
+	"context"
+	"fmt"
+)
+
+var adjectives = []string{
+	"amber", "bold", "brave", "bright", "brisk", "calm", "clever", "cosy",
+	"crisp", "daring", "eager", "early", "fair", "fancy", "fast", "fresh",
+	"gentle", "glad", "golden", "grand", "green", "happy", "hardy", "honest",
+	"jolly", "keen", "kind", "lively", "lucky", "mellow", "merry", "mighty",
+	"modest", "neat", "noble", "plucky", "polite", "proud", "quick", "quiet",
+	"rapid", "ready", "rosy", "royal", "rustic", "shiny", "silver", "simple",
+	"sleek", "smart", "snowy", "solid", "sunny", "swift", "tidy", "tranquil",
+	"upbeat", "vivid", "warm", "wise", "witty", "young", "zany", "zesty",
+}
+
+var nouns = []string{
+	"badger", "beacon", "bison", "breeze", "canyon", "cedar", "comet", "coral",
+	"crane", "delta", "dune", "eagle", "ember", "falcon", "fern", "fjord",
+	"forest", "fox", "glacier", "harbor", "hawk", "heron", "island", "jaguar",
+	"koala", "lagoon", "lark", "lemur", "lynx", "maple", "meadow", "meteor",
+	"moose", "nebula", "oasis", "orchid", "otter", "owl", "panda", "pebble",
+	"pine", "planet", "puffin", "quartz", "raven", "reef", "river", "robin",
+	"salmon", "sparrow", "spruce", "summit", "thunder", "tiger", "trail", "tulip",
+	"valley", "walrus", "willow", "wolf", "wombat", "yak", "zebra", "zephyr",
+}
+
+type WordGenerator struct{}
+
+func NewWordGenerator() *WordGenerator {
+	return &WordGenerator{}
+}
+
+func (g *WordGenerator) Name() string {
+	return StyleWords
+}
+
+func (g *WordGenerator) Generate(ctx context.Context, req Request) (string, error) {
+	a, err := randomIndex(len(adjectives))
+	if err != nil {
+		return "", err
+	}
+	n, err := randomIndex(len(nouns))
+	if err != nil {
+		return "", err
+	}
+	d, err := randomIndex(90)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s-%s-%d", adjectives[a], nouns[n], d+10), nil
+}
@@ -0,0 +1,200 @@
+package targeting
+
+import (
+	"hash/fnv"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"url-shortener-go-backend/internal/model"
+)
+
+type Client struct {
+	DeviceType string
+	OS         string
+	Country    string
+	Languages  []string
+	Time       time.Time
+}
+
+type Detector struct {
+	countryHeader string
+}
+
+func NewDetector(countryHeader string) *Detector {
+	return &Detector{countryHeader: countryHeader}
+}
+
+func (d *Detector) Detect(r *http.Request) Client {
+	ua := strings.ToLower(r.UserAgent())
+	c := Client{
+		DeviceType: deviceType(ua),
+		OS:         operatingSystem(ua),
+		Languages:  parseAcceptLanguage(r.Header.Get("Accept-Language")),
+		Time:       time.Now().UTC(),
+	}
+	if d.countryHeader != "" {
+		country := strings.ToUpper(strings.TrimSpace(r.Header.Get(d.countryHeader)))
+		if len(country) == 2 && country != "XX" && country != "T1" {
+			c.Country = country
+		}
+	}
+	return c
+}
+
+func Match(rules []model.RedirectRule, c Client) (int, *model.RedirectRule) {
+	for i := range rules {
+		if matches(&rules[i], c) {
+			return i, &rules[i]
+		}
+	}
+	return -1, nil
+}
+
+func matches(rule *model.RedirectRule, c Client) bool {
+	if len(rule.Devices) > 0 && !contains(rule.Devices, c.DeviceType) {
+		return false
+	}
+	if len(rule.OS) > 0 && !contains(rule.OS, c.OS) {
+		return false
+	}
+	if len(rule.Countries) > 0 && !contains(rule.Countries, c.Country) {
+		return false
+	}
+	if len(rule.Languages) > 0 && !matchesLanguage(rule.Languages, c.Languages) {
+		return false
+	}
+	if rule.StartsAt != nil && c.Time.Before(*rule.StartsAt) {
+		return false
+	}
+	if rule.EndsAt != nil && !c.Time.Before(*rule.EndsAt) {
+		return false
+	}
+	return true
+}
+
+func contains(values []string, v string) bool {
+	if v == "" {
+		return false
+	}
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesLanguage(wanted, accepted []string) bool {
+	for _, lang := range accepted {
+		for _, w := range wanted {
+			w = strings.ToLower(w)
+			if lang == w || strings.HasPrefix(lang, w+"-") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func deviceType(ua string) string {
+	switch {
+	case ua == "":
+		return model.DeviceUnknown
+	case strings.Contains(ua, "bot"), strings.Contains(ua, "crawler"), strings.Contains(ua, "spider"),
+		strings.Contains(ua, "facebookexternalhit"), strings.Contains(ua, "curl/"), strings.Contains(ua, "wget/"):
+		return model.DeviceBot
+	case strings.Contains(ua, "ipad"), strings.Contains(ua, "tablet"),
+		strings.Contains(ua, "android") && !strings.Contains(ua, "mobile"):
+		return model.DeviceTablet
+	case strings.Contains(ua, "mobi"), strings.Contains(ua, "iphone"), strings.Contains(ua, "ipod"):
+		return model.DeviceMobile
+	default:
+		return model.DeviceDesktop
+	}
+}
+
+func operatingSystem(ua string) string {
+	switch {
+	case strings.Contains(ua, "iphone"), strings.Contains(ua, "ipad"), strings.Contains(ua, "ipod"):
+		return model.OSiOS
+	case strings.Contains(ua, "android"):
+		return model.OSAndroid
+	case strings.Contains(ua, "cros"):
+		return model.OSChromeOS
+	case strings.Contains(ua, "windows"):
+		return model.OSWindows
+	case strings.Contains(ua, "mac os x"), strings.Contains(ua, "macintosh"):
+		return model.OSMacOS
+	case strings.Contains(ua, "linux"):
+		return model.OSLinux
+	default:
+		return ""
+	}
+}
+
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	var langs []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		if q > 0 {
+			langs = append(langs, weighted{tag: tag, q: q})
+		}
+	}
+
+	sort.SliceStable(langs, func(i, j int) bool { return langs[i].q > langs[j].q })
+
+	tags := make([]string, 0, len(langs))
+	for _, l := range langs {
+		tags = append(tags, l.tag)
+	}
+	return tags
+}
+
+func PickVariant(variants []model.Variant, stickyKey string) int {
+	total := 0
+	for _, v := range variants {
+		total += v.Weight
+	}
+	if total <= 0 {
+		return -1
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(stickyKey))
+	point := int(h.Sum64() % uint64(total))
+
+	for i, v := range variants {
+		if point < v.Weight {
+			return i
+		}
+		point -= v.Weight
+	}
+	return len(variants) - 1
+}
+
+func VariantByName(variants []model.Variant, name string) int {
+	for i, v := range variants {
+		if v.Name == name {
+			return i
+		}
+	}
+	return -1
+}
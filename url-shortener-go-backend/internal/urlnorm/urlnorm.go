@@ -0,0 +1,210 @@
+package urlnorm
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+var ErrInvalidURL = errors.New("invalid URL")
+
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+var trackingParams = map[string]bool{
+	"gclid":       true,
+	"gclsrc":      true,
+	"gbraid":      true,
+	"wbraid":      true,
+	"dclid":       true,
+	"fbclid":      true,
+	"msclkid":     true,
+	"twclid":      true,
+	"ttclid":      true,
+	"yclid":       true,
+	"igshid":      true,
+	"li_fat_id":   true,
+	"mc_cid":      true,
+	"mc_eid":      true,
+	"mkt_tok":     true,
+	"_ga":         true,
+	"_gl":         true,
+	"_hsenc":      true,
+	"_hsmi":       true,
+	"oly_anon_id": true,
+	"oly_enc_id":  true,
+	"vero_id":     true,
+}
+
+type Normalizer struct {
+	stripTracking bool
+}
+
+func New(stripTracking bool) *Normalizer {
+	return &Normalizer{stripTracking: stripTracking}
+}
+
+func (n *Normalizer) Normalize(rawURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", ErrInvalidURL
+	}
+	if u.Scheme == "" || u.Host == "" || u.Opaque != "" {
+		return "", ErrInvalidURL
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "" {
+		return "", ErrInvalidURL
+	}
+	port := u.Port()
+	if port == defaultPorts[scheme] {
+		port = ""
+	}
+	if port != "" {
+		host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+
+	var b strings.Builder
+	b.WriteString(scheme)
+	b.WriteString("://")
+	if u.User != nil {
+		b.WriteString(u.User.String())
+		b.WriteByte('@')
+	}
+	b.WriteString(host)
+	b.WriteString(removeDotSegments(normalizeEscapes(u.EscapedPath())))
+	if query := n.normalizeQuery(u.RawQuery); query != "" {
+		b.WriteByte('?')
+		b.WriteString(query)
+	}
+	return b.String(), nil
+}
+
+func (n *Normalizer) normalizeQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+
+	type pair struct {
+		key string
+		raw string
+	}
+
+	var pairs []pair
+	for _, part := range strings.Split(rawQuery, "&") {
+		if part == "" {
+			continue
+		}
+		part = normalizeEscapes(part)
+		rawKey, _, _ := strings.Cut(part, "=")
+		key, err := url.QueryUnescape(rawKey)
+		if err != nil {
+			key = rawKey
+		}
+		if n.stripTracking && IsTrackingParam(key) {
+			continue
+		}
+		pairs = append(pairs, pair{key: key, raw: part})
+	}
+
+	sort.SliceStable(pairs, func(i, j int) bool {
+		if pairs[i].key != pairs[j].key {
+			return pairs[i].key < pairs[j].key
+		}
+		return pairs[i].raw < pairs[j].raw
+	})
+
+	parts := make([]string, len(pairs))
+	for i, p := range pairs {
+		parts[i] = p.raw
+	}
+	return strings.Join(parts, "&")
+}
+
+func IsTrackingParam(key string) bool {
+	key = strings.ToLower(key)
+	return strings.HasPrefix(key, "utm_") || trackingParams[key]
+}
+
+func normalizeEscapes(s string) string {
+	if !strings.Contains(s, "%") {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]) {
+			c := unhex(s[i+1])<<4 | unhex(s[i+2])
+			if isUnreserved(c) {
+				b.WriteByte(c)
+			} else {
+				b.WriteByte('%')
+				b.WriteString(strings.ToUpper(s[i+1 : i+3]))
+			}
+			i += 2
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+func removeDotSegments(p string) string {
+	if p == "" {
+		return "/"
+	}
+
+	segments := strings.Split(p, "/")
+	resolved := make([]string, 0, len(segments))
+	for i, seg := range segments {
+		switch seg {
+		case ".":
+		case "..":
+			if len(resolved) > 1 {
+				resolved = resolved[:len(resolved)-1]
+			}
+		default:
+			resolved = append(resolved, seg)
+			continue
+		}
+		if i == len(segments)-1 {
+			resolved = append(resolved, "")
+		}
+	}
+
+	out := strings.Join(resolved, "/")
+	if !strings.HasPrefix(out, "/") {
+		out = "/" + out
+	}
+	return out
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+func unhex(c byte) byte {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0'
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
+	}
+}
+
+func isUnreserved(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+		c == '-' || c == '.' || c == '_' || c == '~'
+}
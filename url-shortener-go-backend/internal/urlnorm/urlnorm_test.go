@@ -0,0 +1,98 @@
+package urlnorm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name         string
+		in           string
+		keepTracking bool
+		want         string
+	}{
+		{name: "request example", in: "HTTP://Example.com:80/a/../b?utm_source=x#frag", want: "http://example.com/b"},
+		{name: "request example keeping tracking", in: "HTTP://Example.com:80/a/../b?utm_source=x#frag", keepTracking: true, want: "http://example.com/b?utm_source=x"},
+		{name: "default https port", in: "https://example.com:443/", want: "https://example.com/"},
+		{name: "non-default port", in: "https://example.com:8443/", want: "https://example.com:8443/"},
+		{name: "empty path", in: "https://example.com", want: "https://example.com/"},
+		{name: "trailing dot host", in: "https://Example.COM./x", want: "https://example.com/x"},
+		{name: "path case kept", in: "https://example.com/Docs/README", want: "https://example.com/Docs/README"},
+		{name: "dot segments", in: "https://example.com/a/./b/../c/", want: "https://example.com/a/c/"},
+		{name: "trailing dot dot", in: "https://example.com/a/b/..", want: "https://example.com/a/"},
+		{name: "dot dot above root", in: "https://example.com/../a", want: "https://example.com/a"},
+		{name: "unreserved escapes decoded", in: "https://example.com/%7euser/%2d", want: "https://example.com/~user/-"},
+		{name: "reserved escapes uppercased", in: "https://example.com/a%2fb?q=a%2bb", want: "https://example.com/a%2Fb?q=a%2Bb"},
+		{name: "query sorted", in: "https://example.com/?b=2&c=3&a=1", want: "https://example.com/?a=1&b=2&c=3"},
+		{name: "repeated keys", in: "https://example.com/?tag=z&x=1&tag=a", want: "https://example.com/?tag=a&tag=z&x=1"},
+		{name: "empty query parts", in: "https://example.com/?&a=1&&", want: "https://example.com/?a=1"},
+		{name: "key without value", in: "https://example.com/?b&a=", want: "https://example.com/?a=&b"},
+		{name: "tracking stripped", in: "https://example.com/p?id=7&fbclid=abc&UTM_Campaign=x&gclid=1", want: "https://example.com/p?id=7"},
+		{name: "tracking kept", in: "https://example.com/p?id=7&fbclid=abc", keepTracking: true, want: "https://example.com/p?fbclid=abc&id=7"},
+		{name: "ipv6 with port", in: "http://[2001:DB8::1]:8080/x", want: "http://[2001:db8::1]:8080/x"},
+		{name: "ipv6 default port", in: "https://[2001:db8::1]:443/", want: "https://[2001:db8::1]/"},
+		{name: "ipv6 without port", in: "http://[::1]/", want: "http://[::1]/"},
+		{name: "userinfo kept", in: "https://user:pw@Example.com/", want: "https://user:pw@example.com/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(!tt.keepTracking).Normalize(tt.in)
+			if err != nil {
+				t.Fatalf("Normalize(%q): %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Fatalf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeQueryOrderIsStable(t *testing.T) {
+	n := New(true)
+	orders := []string{
+		"https://example.com/?a=2&b=1&a=1&c",
+		"https://example.com/?c&a=1&b=1&a=2",
+		"https://example.com/?b=1&a=1&c&a=2&utm_medium=email",
+		"https://example.com/?a=1&a=2&b=1&c",
+	}
+
+	want, err := n.Normalize(orders[0])
+	if err != nil {
+		t.Fatalf("Normalize: %v", err)
+	}
+	for _, in := range orders[1:] {
+		got, err := n.Normalize(in)
+		if err != nil || got != want {
+			t.Fatalf("Normalize(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if again, _ := n.Normalize(want); again != want {
+		t.Fatalf("Normalize is not idempotent: %q -> %q", want, again)
+	}
+}
+
+func TestNormalizeRejectsInvalid(t *testing.T) {
+	for _, in := range []string{"", "example.com/path", "mailto:someone@example.com", "http://", "http://%zz/", "https://:443/"} {
+		if _, err := New(true).Normalize(in); !errors.Is(err, ErrInvalidURL) {
+			t.Fatalf("Normalize(%q) = %v, want ErrInvalidURL", in, err)
+		}
+	}
+}
+
+func TestIsTrackingParam(t *testing.T) {
+	for key, want := range map[string]bool{
+		"utm_source": true,
+		"UTM_Term":   true,
+		"fbclid":     true,
+		"_ga":        true,
+		"id":         false,
+		"utm":        false,
+		"source":     false,
+	} {
+		if got := IsTrackingParam(key); got != want {
+			t.Fatalf("IsTrackingParam(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
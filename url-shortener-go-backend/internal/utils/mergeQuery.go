@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+func MergeQuery(rawURL string, extra url.Values, override bool) (string, error) {
+	if len(extra) == 0 {
+		return rawURL, nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("parse error for url %s: %w", rawURL, err)
+	}
+
+	present := make(map[string]bool)
+	var kept []string
+	for _, pair := range strings.Split(u.RawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		key, _, _ := strings.Cut(pair, "=")
+		if decoded, err := url.QueryUnescape(key); err == nil {
+			key = decoded
+		}
+		if override && extra.Has(key) {
+			continue
+		}
+		present[key] = true
+		kept = append(kept, pair)
+	}
+
+	keys := make([]string, 0, len(extra))
+	for key := range extra {
+		if !present[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, value := range extra[key] {
+			kept = append(kept, url.QueryEscape(key)+"="+url.QueryEscape(value))
+		}
+	}
+
+	u.RawQuery = strings.Join(kept, "&")
+	u.ForceQuery = false
+	return u.String(), nil
+}
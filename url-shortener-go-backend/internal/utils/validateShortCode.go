@@ -1,7 +1,9 @@
 package utils
 
+import "strings"
+
 func IsValidShortCode(code string) bool {
-	if len(code) < 6 || len(code) > 12 {
+	if len(code) < 6 || len(code) > 32 {
 		return false
 	}
 
@@ -16,3 +18,22 @@ func IsValidShortCode(code string) bool {
 	}
 	return true
 }
+
+func IsValidLinkKey(key string) bool {
+	i := strings.LastIndex(key, "/")
+	if i < 0 {
+		return IsValidShortCode(key)
+	}
+
+	domain := key[:i]
+	if len(domain) > 253 || !strings.Contains(domain, ".") || strings.Contains(domain, "..") ||
+		strings.HasPrefix(domain, ".") || strings.HasSuffix(domain, ".") {
+		return false
+	}
+	for _, c := range domain {
+		if !(c >= 'a' && c <= 'z') && !(c >= '0' && c <= '9') && c != '-' && c != '.' {
+			return false
+		}
+	}
+	return IsValidShortCode(key[i+1:])
+}
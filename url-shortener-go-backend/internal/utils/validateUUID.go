@@ -0,0 +1,21 @@
+package utils
+
+func IsValidUUID(id string) bool {
+	if len(id) != 36 {
+		return false
+	}
+
+	for i, c := range id {
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return false
+			}
+		default:
+			if !(c >= '0' && c <= '9') && !(c >= 'a' && c <= 'f') && !(c >= 'A' && c <= 'F') {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,80 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+func RunPeriodic(ctx context.Context, name string, interval time.Duration, fn func(context.Context) error) {
+	if interval <= 0 {
+		slog.Info("periodic job disabled", "job", name)
+		return
+	}
+
+	slog.Info("periodic job started", "job", name, "interval", interval.String())
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("periodic job stopped", "job", name)
+			return
+		case <-ticker.C:
+			start := time.Now()
+			if err := fn(ctx); err != nil {
+				slog.Error("periodic job failed", "job", name, "error", err)
+				continue
+			}
+			slog.Info("periodic job completed", "job", name, "duration", time.Since(start).String())
+		}
+	}
+}
+
+type Queue[T any] struct {
+	name  string
+	items chan T
+}
+
+func NewQueue[T any](name string, size int) *Queue[T] {
+	return &Queue[T]{name: name, items: make(chan T, size)}
+}
+
+func (q *Queue[T]) Enqueue(item T) bool {
+	select {
+	case q.items <- item:
+		return true
+	default:
+		slog.Warn("queue full, dropping job", "queue", q.name)
+		return false
+	}
+}
+
+func (q *Queue[T]) Run(ctx context.Context, workers int, fn func(context.Context, T) error) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	slog.Info("queue workers started", "queue", q.name, "workers", workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case item := <-q.items:
+					if err := fn(ctx, item); err != nil {
+						slog.Warn("queue job failed", "queue", q.name, "error", err)
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	slog.Info("queue workers stopped", "queue", q.name)
+}